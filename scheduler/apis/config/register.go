@@ -38,8 +38,15 @@ var (
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&KubeSchedulerConfiguration{},
+		&BalancedPreemptionArgs{},
+		&CallGraphArgs{},
+		&CapacitySchedulingArgs{},
+		&CoschedulingArgs{},
+		&DeadlineSortArgs{},
 		&DefaultPreemptionArgs{},
+		&FairShareArgs{},
 		&InterPodAffinityArgs{},
+		&NetworkTopologyArgs{},
 		&NodeResourcesFitArgs{},
 		&PodTopologySpreadArgs{},
 		&VolumeBindingArgs{},
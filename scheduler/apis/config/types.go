@@ -98,6 +98,65 @@ type KubeSchedulerConfiguration struct {
 	Extenders []Extender
 }
 
+// BackoffPolicyType is the strategy used to grow the backoff of unschedulable
+// pods with the number of attempts.
+type BackoffPolicyType string
+
+const (
+	// ExponentialBackoffPolicy doubles the backoff on every attempt.
+	ExponentialBackoffPolicy BackoffPolicyType = "Exponential"
+	// LinearBackoffPolicy grows the backoff by the initial backoff on every
+	// attempt.
+	LinearBackoffPolicy BackoffPolicyType = "Linear"
+)
+
+// BackoffPolicy configures how long unschedulable pods are backed off before
+// they are retried. The versioned configuration doesn't carry it: it is set
+// with the WithBackoffPolicy scheduler option.
+type BackoffPolicy struct {
+	// Type is the strategy used to grow the backoff, from PodInitialBackoffSeconds
+	// up to PodMaxBackoffSeconds. If empty, Exponential is used.
+	Type BackoffPolicyType
+	// Jitter is the fraction of the backoff that is randomly added to or removed
+	// from it, so that pods that failed together aren't retried together. The
+	// jittered backoff still doesn't exceed the max backoff. It must be in the
+	// range [0, 1).
+	Jitter float64
+	// PluginOverrides override the backoff of the pods that were only rejected
+	// by plugins listed here, for example to retry sooner the pods that were
+	// rejected because an external agent was unreachable. If a pod was rejected
+	// by several of them, the longest of their backoffs is used.
+	PluginOverrides []PluginBackoffOverride
+}
+
+// PluginBackoffOverride overrides the backoff of the pods rejected by a plugin.
+type PluginBackoffOverride struct {
+	// PluginName is the name of the plugin.
+	PluginName string
+	// InitialBackoffSeconds is the backoff after the first attempt. It must be
+	// greater than 0.
+	InitialBackoffSeconds int64
+	// MaxBackoffSeconds is the maximum backoff. It must be greater than or equal
+	// to InitialBackoffSeconds.
+	MaxBackoffSeconds int64
+}
+
+// ProfileRoute routes the pods matching all of its selectors to a profile.
+// Routes assign a profile to the pods that don't ask for one, that is those
+// with the "default-scheduler" scheduler name. The versioned configuration
+// doesn't carry them: they are set with the WithProfileRoutes scheduler option.
+type ProfileRoute struct {
+	// SchedulerName is the name of the profile the matching pods are scheduled
+	// with.
+	SchedulerName string
+	// NamespaceSelector matches the labels of the namespace of the pods. If null,
+	// the pods of all namespaces match.
+	NamespaceSelector *metav1.LabelSelector
+	// PodSelector matches the labels of the pods. If null, all pods match.
+	// At least one of NamespaceSelector and PodSelector is required.
+	PodSelector *metav1.LabelSelector
+}
+
 // KubeSchedulerProfile is a scheduling profile.
 type KubeSchedulerProfile struct {
 	// SchedulerName is the name of the scheduler associated to this profile.
@@ -121,6 +180,64 @@ type KubeSchedulerProfile struct {
 	PluginConfig []PluginConfig
 }
 
+// ScoreWeightTuning configures the online tuning of the weights of the Score
+// plugins of a profile. Candidate weight vectors are derived from the
+// configured weights by doubling or halving the weight of one tuned plugin at
+// a time, and are tried as the arms of an epsilon-greedy multi-armed bandit
+// rewarded with the balance of the utilization of the nodes. The versioned
+// configuration doesn't carry it: it is set with the WithScoreWeightTuning
+// scheduler option.
+type ScoreWeightTuning struct {
+	// Plugins are the names of the Score plugins whose weights are tuned. If
+	// empty, the weights of all the Score plugins of the profile are tuned.
+	Plugins []string
+	// Interval is how long each weight vector is used before the balance of the
+	// nodes is evaluated and the next one is picked. It must be greater than 0.
+	Interval metav1.Duration
+	// Epsilon is the probability with which a random weight vector is tried
+	// instead of the best one so far. It must be in the range [0, 1].
+	Epsilon float64
+	// MaxWeight is the maximum weight a tuned plugin can get. It must be
+	// greater than 0.
+	MaxWeight int32
+}
+
+// HostSelectorType is the strategy used to pick a node among the scored feasible nodes.
+type HostSelectorType string
+
+const (
+	// MaxScoreHostSelector picks the node with the highest score, breaking ties
+	// uniformly at random.
+	MaxScoreHostSelector HostSelectorType = "MaxScore"
+	// SoftmaxHostSelector picks a node with a probability proportional to
+	// exp(score/temperature).
+	SoftmaxHostSelector HostSelectorType = "Softmax"
+	// TopKHostSelector picks a node uniformly at random among the K nodes with
+	// the highest scores.
+	TopKHostSelector HostSelectorType = "TopK"
+	// EpsilonGreedyHostSelector picks a node uniformly at random among all the
+	// feasible nodes with probability epsilon, and behaves like MaxScore otherwise.
+	EpsilonGreedyHostSelector HostSelectorType = "EpsilonGreedy"
+)
+
+// HostSelector configures how the node a pod is assigned to is picked among the
+// scored feasible nodes. The versioned configuration doesn't carry it: it is set
+// with the WithHostSelector scheduler option.
+type HostSelector struct {
+	// Type is the selection strategy.
+	Type HostSelectorType
+	// Temperature controls how strongly the Softmax strategy favors nodes with
+	// higher scores. It must be greater than zero. Lower values get closer to
+	// MaxScore, higher values get closer to a uniform choice.
+	Temperature float64
+	// K is the number of top-scoring nodes the TopK strategy picks from.
+	// It must be greater than zero.
+	K int32
+	// Epsilon is the probability with which the EpsilonGreedy strategy explores
+	// a random node. It must be in the range [0, 1].
+	Epsilon float64
+}
+
 // Plugins include multiple extension points. When specified, the list of plugins for
 // a particular extension point are the only ones enabled. If an extension point is
 // omitted from the config, then the default set of plugins is used for that extension point.
@@ -259,6 +376,16 @@ type Extender struct {
 	// If this method is implemented by the extender, it is the extender's responsibility to bind the pod to apiserver. Only one extender
 	// can implement this function.
 	BindVerb string
+	// Verb for the reserve call, empty if not supported. This verb is appended to the URLPrefix when issuing the reserve call to extender.
+	// The call tells the extender that the pod is reserved on a node. An error rejects the pod from the node, unless the extender
+	// is ignorable.
+	ReserveVerb string
+	// Verb for the unreserve call, empty if not supported. This verb is appended to the URLPrefix when issuing the unreserve call to
+	// extender. The call tells the extender that a reserved pod was rejected from the node or failed to bind to it.
+	UnreserveVerb string
+	// Verb for the postBind call, empty if not supported. This verb is appended to the URLPrefix when issuing the postBind call to
+	// extender. The call tells the extender that the pod is bound to the node.
+	PostBindVerb string
 	// EnableHTTPS specifies whether https should be used to communicate with the extender
 	EnableHTTPS bool
 	// TLSConfig specifies the transport layer security config
@@ -272,8 +399,9 @@ type Extender struct {
 	NodeCacheCapable bool
 	// ManagedResources is a list of extended resources that are managed by
 	// this extender.
-	// - A pod will be sent to the extender on the Filter, Prioritize and Bind
-	//   (if the extender is the binder) phases iff the pod requests at least
+	// - A pod will be sent to the extender on the Filter, Prioritize, Reserve,
+	//   Unreserve, PostBind and Bind (if the extender is the binder) phases
+	//   iff the pod requests at least
 	//   one of the extended resources in this list. If empty or unspecified,
 	//   all pods will be sent to this extender.
 	// - If IgnoredByScheduler is set to true for a resource, kube-scheduler
@@ -283,8 +411,29 @@ type Extender struct {
 	// Ignorable specifies if the extender is ignorable, i.e. scheduling should not
 	// fail when the extender returns an error or is not reachable.
 	Ignorable bool
+	// ResultCacheTTL is how long the results of the filter and prioritize calls
+	// are reused for pods created from the same template, as identified by
+	// their pod-template-hash label, when given the same nodes. Zero disables
+	// the cache.
+	ResultCacheTTL metav1.Duration
+	// Transport is the protocol used to call the extender, HTTP if empty.
+	// With GRPC, URLPrefix is the target of the gRPC connection, EnableHTTPS
+	// enables TLS, and the verbs only enable the matching calls of the Extender
+	// service, whatever their value. The service has no reserve, unreserve and
+	// postBind calls.
+	Transport ExtenderTransport
 }
 
+// ExtenderTransport is the protocol used to call an extender.
+type ExtenderTransport string
+
+const (
+	// ExtenderTransportHTTP calls the extender with JSON over HTTP.
+	ExtenderTransportHTTP ExtenderTransport = "HTTP"
+	// ExtenderTransportGRPC calls the Extender gRPC service of the extender.
+	ExtenderTransportGRPC ExtenderTransport = "GRPC"
+)
+
 // ExtenderManagedResource describes the arguments of extended resources
 // managed by an extender.
 type ExtenderManagedResource struct {
@@ -23,6 +23,128 @@ import (
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// BalancedPreemptionArgs holds arguments used to configure the
+// BalancedPreemption plugin.
+type BalancedPreemptionArgs struct {
+	metav1.TypeMeta
+
+	// MinCandidateNodesPercentage and MinCandidateNodesAbsolute bound the
+	// number of candidates shortlisted when dry running preemption, as in
+	// DefaultPreemptionArgs. They default to 10% and 100 nodes if unspecified.
+	MinCandidateNodesPercentage int32
+	MinCandidateNodesAbsolute   int32
+	// DisruptionWeight is the weight of the disruption caused by preempting
+	// the victims of a candidate, counted in victims and PDB violations.
+	// Defaults to 1 if unspecified.
+	DisruptionWeight int32
+	// BalanceWeight is the weight of the load imbalance of the cluster after
+	// preempting the victims of a candidate and placing the preemptor on it.
+	// Defaults to 1 if unspecified.
+	BalanceWeight int32
+	// AgentURL is the endpoint of the RL agent ranking the candidates. The
+	// candidates are ranked by the weighted cost of disruption and imbalance
+	// if it's empty or the agent fails.
+	AgentURL string
+	// AgentTimeout is the timeout of the requests to the agent. Defaults to
+	// 100ms if unspecified.
+	AgentTimeout metav1.Duration
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CallGraphArgs holds arguments used to configure the CallGraph plugin.
+type CallGraphArgs struct {
+	metav1.TypeMeta
+
+	// ServiceLabel is the key of the label holding the name of the service of
+	// a pod. Defaults to "app" if unspecified.
+	ServiceLabel string
+	// Dependencies are the edges of the service dependency graph.
+	Dependencies []ServiceDependency
+	// DependencyConfigMapNamespace and DependencyConfigMapName reference an
+	// optional ConfigMap holding dependencies. Each key of its data is an
+	// upstream service, and each value a YAML map from its downstream services
+	// to the weight of the traffic sent to them. Dependencies found in the
+	// ConfigMap take precedence over Dependencies.
+	DependencyConfigMapNamespace string
+	DependencyConfigMapName      string
+}
+
+// ServiceDependency is an edge of the service dependency graph.
+type ServiceDependency struct {
+	// Upstream is the service sending the traffic.
+	Upstream string
+	// Downstream is the service receiving the traffic.
+	Downstream string
+	// Weight is the relative volume of the traffic. Must be greater than 0.
+	Weight int64
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CapacitySchedulingArgs holds arguments used to configure the
+// CapacityScheduling plugin.
+type CapacitySchedulingArgs struct {
+	metav1.TypeMeta
+
+	// Quotas are the elastic quotas of the namespaces. Pods of namespaces
+	// without a quota are not constrained by the plugin.
+	Quotas []ElasticQuota
+	// QuotaConfigMapNamespace and QuotaConfigMapName reference an optional
+	// ConfigMap holding elastic quotas. Each key of its data is a namespace,
+	// and each value a YAML document with the min and max resources of the
+	// namespace. Quotas found in the ConfigMap take precedence over Quotas.
+	QuotaConfigMapNamespace string
+	QuotaConfigMapName      string
+}
+
+// ElasticQuota is the elastic capacity quota of a namespace.
+type ElasticQuota struct {
+	// Namespace is the namespace the quota applies to.
+	Namespace string
+	// Min is the amount of resources guaranteed to the namespace. Pods of a
+	// namespace using more than its min borrow from the unused min of other
+	// namespaces, and can be preempted to give it back.
+	Min v1.ResourceList
+	// Max is the maximum amount of resources the namespace can use. Resources
+	// that are not listed are not limited.
+	Max v1.ResourceList
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CoschedulingArgs holds arguments used to configure the Coscheduling plugin.
+type CoschedulingArgs struct {
+	metav1.TypeMeta
+
+	// PermitWaitingTimeSeconds is the maximum time, in seconds, that the members
+	// of a pod group wait at Permit for the rest of the group to be placed.
+	// Defaults to 60 seconds if unspecified.
+	PermitWaitingTimeSeconds int64
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeadlineSortArgs holds arguments used to configure the DeadlineSort plugin.
+type DeadlineSortArgs struct {
+	metav1.TypeMeta
+
+	// SLOClasses are the scheduling SLO classes pods can declare through an
+	// annotation instead of an explicit deadline.
+	SLOClasses []SLOClass
+}
+
+// SLOClass is a scheduling SLO class of the DeadlineSort plugin.
+type SLOClass struct {
+	// Name is the name of the class.
+	Name string
+	// Budget is the time the pods of the class have to be scheduled in,
+	// counted from their creation.
+	Budget metav1.Duration
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // DefaultPreemptionArgs holds arguments used to configure the
 // DefaultPreemption plugin.
 type DefaultPreemptionArgs struct {
@@ -45,6 +167,38 @@ type DefaultPreemptionArgs struct {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// FairShareArgs holds arguments used to configure the FairShare plugin.
+type FairShareArgs struct {
+	metav1.TypeMeta
+
+	// TenantLabel is the label holding the tenant of a pod, such as its
+	// workload class. Pods without the label, or all pods if TenantLabel is
+	// empty, belong to the tenant named after their namespace.
+	TenantLabel string
+	// Weights are the weights of the tenants. Tenants get a share of the
+	// scheduled pods proportional to their weight.
+	Weights []TenantWeight
+	// DefaultWeight is the weight of the tenants not listed in Weights.
+	// Defaults to 1 if unspecified.
+	DefaultWeight int32
+	// HalfLifeSeconds is the time, in seconds, after which half of the pods
+	// scheduled for a tenant are forgotten. Longer half-lives make sharing
+	// fairer over time, shorter ones let tenants that had many pods scheduled
+	// recently catch up sooner.
+	// Defaults to 300 seconds if unspecified.
+	HalfLifeSeconds int64
+}
+
+// TenantWeight is the weight of a tenant of the FairShare plugin.
+type TenantWeight struct {
+	// Tenant is the name of the tenant.
+	Tenant string
+	// Weight is the weight of the tenant.
+	Weight int32
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // InterPodAffinityArgs holds arguments used to configure the InterPodAffinity plugin.
 type InterPodAffinityArgs struct {
 	metav1.TypeMeta
@@ -56,6 +210,29 @@ type InterPodAffinityArgs struct {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// NetworkTopologyArgs holds arguments used to configure the NetworkTopology
+// plugin.
+type NetworkTopologyArgs struct {
+	metav1.TypeMeta
+
+	// ServiceLabel is the key of the label holding the name of the service of
+	// a pod. Defaults to "app" if unspecified.
+	ServiceLabel string
+	// MaxPeerLatency is the maximum latency from the node of a pod to the
+	// nodes running its peer services. Pods can override it with an
+	// annotation. If zero, only the pods with the annotation are filtered.
+	MaxPeerLatency metav1.Duration
+	// LinkConfigMapNamespace and LinkConfigMapName reference an optional
+	// ConfigMap holding links between nodes. Each key of its data is a node,
+	// and each value a YAML map from other nodes to the latency and bandwidth,
+	// in bytes per second, of the links to them. Links reported by the node
+	// telemetry take precedence over the ones of the ConfigMap.
+	LinkConfigMapNamespace string
+	LinkConfigMapName      string
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // NodeResourcesFitArgs holds arguments used to configure the NodeResourcesFit plugin.
 type NodeResourcesFitArgs struct {
 	metav1.TypeMeta
@@ -105,3 +105,11 @@ func convertToExternalPluginConfigArgs(out *v1beta2.KubeSchedulerConfiguration)
 	}
 	return nil
 }
+
+// Convert_config_Extender_To_v1beta2_Extender drops the transport, the result
+// cache TTL and the reserve, unreserve and postBind verbs of the extender,
+// which only exist in the internal type, where they default to HTTP, no
+// caching and unsupported.
+func Convert_config_Extender_To_v1beta2_Extender(in *config.Extender, out *v1beta2.Extender, s conversion.Scope) error {
+	return autoConvert_config_Extender_To_v1beta2_Extender(in, out, s)
+}
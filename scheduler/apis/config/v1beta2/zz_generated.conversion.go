@@ -55,11 +55,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*config.Extender)(nil), (*v1beta2.Extender)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_config_Extender_To_v1beta2_Extender(a.(*config.Extender), b.(*v1beta2.Extender), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*v1beta2.ExtenderManagedResource)(nil), (*config.ExtenderManagedResource)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_ExtenderManagedResource_To_config_ExtenderManagedResource(a.(*v1beta2.ExtenderManagedResource), b.(*config.ExtenderManagedResource), scope)
 	}); err != nil {
@@ -230,6 +225,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*config.Extender)(nil), (*v1beta2.Extender)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_Extender_To_v1beta2_Extender(a.(*config.Extender), b.(*v1beta2.Extender), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*config.KubeSchedulerConfiguration)(nil), (*v1beta2.KubeSchedulerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_config_KubeSchedulerConfiguration_To_v1beta2_KubeSchedulerConfiguration(a.(*config.KubeSchedulerConfiguration), b.(*v1beta2.KubeSchedulerConfiguration), scope)
 	}); err != nil {
@@ -301,20 +301,20 @@ func autoConvert_config_Extender_To_v1beta2_Extender(in *config.Extender, out *v
 	out.PrioritizeVerb = in.PrioritizeVerb
 	out.Weight = in.Weight
 	out.BindVerb = in.BindVerb
+	// WARNING: in.ReserveVerb requires manual conversion: does not exist in peer-type
+	// WARNING: in.UnreserveVerb requires manual conversion: does not exist in peer-type
+	// WARNING: in.PostBindVerb requires manual conversion: does not exist in peer-type
 	out.EnableHTTPS = in.EnableHTTPS
 	out.TLSConfig = (*v1beta2.ExtenderTLSConfig)(unsafe.Pointer(in.TLSConfig))
 	out.HTTPTimeout = in.HTTPTimeout
 	out.NodeCacheCapable = in.NodeCacheCapable
 	out.ManagedResources = *(*[]v1beta2.ExtenderManagedResource)(unsafe.Pointer(&in.ManagedResources))
 	out.Ignorable = in.Ignorable
+	// WARNING: in.ResultCacheTTL requires manual conversion: does not exist in peer-type
+	// WARNING: in.Transport requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_config_Extender_To_v1beta2_Extender is an autogenerated conversion function.
-func Convert_config_Extender_To_v1beta2_Extender(in *config.Extender, out *v1beta2.Extender, s conversion.Scope) error {
-	return autoConvert_config_Extender_To_v1beta2_Extender(in, out, s)
-}
-
 func autoConvert_v1beta2_ExtenderManagedResource_To_config_ExtenderManagedResource(in *v1beta2.ExtenderManagedResource, out *config.ExtenderManagedResource, s conversion.Scope) error {
 	out.Name = in.Name
 	out.IgnoredByScheduler = in.IgnoredByScheduler
@@ -434,7 +434,17 @@ func autoConvert_v1beta2_KubeSchedulerConfiguration_To_config_KubeSchedulerConfi
 	} else {
 		out.Profiles = nil
 	}
-	out.Extenders = *(*[]config.Extender)(unsafe.Pointer(&in.Extenders))
+	if in.Extenders != nil {
+		in, out := &in.Extenders, &out.Extenders
+		*out = make([]config.Extender, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta2_Extender_To_config_Extender(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Extenders = nil
+	}
 	return nil
 }
 
@@ -477,7 +487,17 @@ func autoConvert_config_KubeSchedulerConfiguration_To_v1beta2_KubeSchedulerConfi
 	} else {
 		out.Profiles = nil
 	}
-	out.Extenders = *(*[]v1beta2.Extender)(unsafe.Pointer(&in.Extenders))
+	if in.Extenders != nil {
+		in, out := &in.Extenders, &out.Extenders
+		*out = make([]v1beta2.Extender, len(*in))
+		for i := range *in {
+			if err := Convert_config_Extender_To_v1beta2_Extender(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Extenders = nil
+	}
 	return nil
 }
 
@@ -25,7 +25,11 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -105,6 +109,142 @@ func ValidateKubeSchedulerConfiguration(cc *config.KubeSchedulerConfiguration) u
 	return utilerrors.Flatten(utilerrors.NewAggregate(errs))
 }
 
+// ValidateProfileRoutes ensures that the profile routes and the default profile
+// refer to the given profiles, and that no pod can match routes to different
+// profiles.
+func ValidateProfileRoutes(routes []config.ProfileRoute, defaultProfile string, profiles []config.KubeSchedulerProfile) utilerrors.Aggregate {
+	var errs []error
+	profileNames := sets.NewString()
+	for _, p := range profiles {
+		profileNames.Insert(p.SchedulerName)
+	}
+	if len(defaultProfile) != 0 && !profileNames.Has(defaultProfile) {
+		errs = append(errs, field.Invalid(field.NewPath("defaultProfile"), defaultProfile, "must be the scheduler name of a profile"))
+	}
+
+	path := field.NewPath("profileRoutes")
+	type parsedRoute struct {
+		schedulerName                  string
+		namespaceSelector, podSelector labels.Selector
+	}
+	var parsed []parsedRoute
+	for i, r := range routes {
+		p := path.Index(i)
+		if len(r.SchedulerName) == 0 {
+			errs = append(errs, field.Required(p.Child("schedulerName"), ""))
+		} else if !profileNames.Has(r.SchedulerName) {
+			errs = append(errs, field.Invalid(p.Child("schedulerName"), r.SchedulerName, "must be the scheduler name of a profile"))
+		}
+		if r.NamespaceSelector == nil && r.PodSelector == nil {
+			errs = append(errs, field.Required(p, "at least one of namespaceSelector and podSelector is required"))
+			continue
+		}
+		selectorErrs := metav1validation.ValidateLabelSelector(r.NamespaceSelector, p.Child("namespaceSelector"))
+		selectorErrs = append(selectorErrs, metav1validation.ValidateLabelSelector(r.PodSelector, p.Child("podSelector"))...)
+		if len(selectorErrs) != 0 {
+			errs = append(errs, selectorErrs.ToAggregate())
+			continue
+		}
+		route := parsedRoute{schedulerName: r.SchedulerName, namespaceSelector: labels.Everything(), podSelector: labels.Everything()}
+		if r.NamespaceSelector != nil {
+			route.namespaceSelector, _ = metav1.LabelSelectorAsSelector(r.NamespaceSelector)
+		}
+		if r.PodSelector != nil {
+			route.podSelector, _ = metav1.LabelSelectorAsSelector(r.PodSelector)
+		}
+		for j, other := range parsed {
+			if other.schedulerName == route.schedulerName {
+				continue
+			}
+			if !selectorsDisjoint(route.namespaceSelector, other.namespaceSelector) && !selectorsDisjoint(route.podSelector, other.podSelector) {
+				errs = append(errs, field.Invalid(p, r.SchedulerName,
+					fmt.Sprintf("pods may also match route %d, to profile %q: the selectors of routes to different profiles must exclude each other", j, other.schedulerName)))
+			}
+		}
+		parsed = append(parsed, route)
+	}
+	return utilerrors.Flatten(utilerrors.NewAggregate(errs))
+}
+
+// selectorsDisjoint returns true if no set of labels can match both selectors,
+// that is if they have exclusive requirements on the same key. It may return
+// false for some disjoint selectors.
+func selectorsDisjoint(a, b labels.Selector) bool {
+	reqsA, _ := a.Requirements()
+	reqsB, _ := b.Requirements()
+	for _, ra := range reqsA {
+		for _, rb := range reqsB {
+			if ra.Key() == rb.Key() && requirementsDisjoint(ra, rb) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requirementsDisjoint returns true if no value of a key can match both
+// requirements on it.
+func requirementsDisjoint(a, b labels.Requirement) bool {
+	if !requiresValue(a.Operator()) {
+		a, b = b, a
+	}
+	switch a.Operator() {
+	case selection.In, selection.Equals, selection.DoubleEquals:
+		switch b.Operator() {
+		case selection.In, selection.Equals, selection.DoubleEquals:
+			return !a.Values().HasAny(b.Values().UnsortedList()...)
+		case selection.NotIn, selection.NotEquals:
+			return b.Values().IsSuperset(a.Values())
+		case selection.DoesNotExist:
+			return true
+		}
+	case selection.Exists:
+		return b.Operator() == selection.DoesNotExist
+	case selection.DoesNotExist:
+		return b.Operator() == selection.Exists
+	}
+	return false
+}
+
+// requiresValue returns true if the operator only matches the given values.
+func requiresValue(op selection.Operator) bool {
+	return op == selection.In || op == selection.Equals || op == selection.DoubleEquals
+}
+
+// ValidateBackoffPolicy validates the backoff policy of unschedulable pods.
+func ValidateBackoffPolicy(bp *config.BackoffPolicy) utilerrors.Aggregate {
+	var errs []error
+	path := field.NewPath("backoffPolicy")
+	switch bp.Type {
+	case "", config.ExponentialBackoffPolicy, config.LinearBackoffPolicy:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("type"), bp.Type, []string{
+			string(config.ExponentialBackoffPolicy),
+			string(config.LinearBackoffPolicy),
+		}))
+	}
+	if bp.Jitter < 0 || bp.Jitter >= 1 {
+		errs = append(errs, field.Invalid(path.Child("jitter"), bp.Jitter, "must be in the range [0, 1)"))
+	}
+	seen := sets.NewString()
+	for i, o := range bp.PluginOverrides {
+		p := path.Child("pluginOverrides").Index(i)
+		if len(o.PluginName) == 0 {
+			errs = append(errs, field.Required(p.Child("pluginName"), ""))
+		} else if seen.Has(o.PluginName) {
+			errs = append(errs, field.Duplicate(p.Child("pluginName"), o.PluginName))
+		}
+		seen.Insert(o.PluginName)
+		if o.InitialBackoffSeconds <= 0 {
+			errs = append(errs, field.Invalid(p.Child("initialBackoffSeconds"), o.InitialBackoffSeconds, "must be greater than 0"))
+		}
+		if o.MaxBackoffSeconds < o.InitialBackoffSeconds {
+			errs = append(errs, field.Invalid(p.Child("maxBackoffSeconds"), o.MaxBackoffSeconds, "must be greater than or equal to initialBackoffSeconds"))
+		}
+	}
+	return utilerrors.Flatten(utilerrors.NewAggregate(errs))
+}
+
 func splitHostIntPort(s string) (string, int, error) {
 	host, port, err := net.SplitHostPort(s)
 	if err != nil {
@@ -171,6 +311,62 @@ func validateKubeSchedulerProfile(path *field.Path, apiVersion string, profile *
 	return errs
 }
 
+// ValidateScoreWeightTuning validates the tuning of the weights of the Score
+// plugins of a profile.
+func ValidateScoreWeightTuning(t *config.ScoreWeightTuning) utilerrors.Aggregate {
+	var errs []error
+	path := field.NewPath("scoreWeightTuning")
+	seen := sets.NewString()
+	for i, name := range t.Plugins {
+		if len(name) == 0 {
+			errs = append(errs, field.Required(path.Child("plugins").Index(i), ""))
+		} else if seen.Has(name) {
+			errs = append(errs, field.Duplicate(path.Child("plugins").Index(i), name))
+		}
+		seen.Insert(name)
+	}
+	if t.Interval.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("interval"), t.Interval, "must be greater than zero"))
+	}
+	if t.Epsilon < 0 || t.Epsilon > 1 {
+		errs = append(errs, field.Invalid(path.Child("epsilon"), t.Epsilon, "must be in the range [0, 1]"))
+	}
+	if t.MaxWeight <= 0 {
+		errs = append(errs, field.Invalid(path.Child("maxWeight"), t.MaxWeight, "must be greater than zero"))
+	}
+	return utilerrors.Flatten(utilerrors.NewAggregate(errs))
+}
+
+// ValidateHostSelector validates the strategy picking the node a pod is
+// assigned to.
+func ValidateHostSelector(hs *config.HostSelector) utilerrors.Aggregate {
+	var errs []error
+	path := field.NewPath("hostSelector")
+	switch hs.Type {
+	case config.MaxScoreHostSelector:
+	case config.SoftmaxHostSelector:
+		if hs.Temperature <= 0 {
+			errs = append(errs, field.Invalid(path.Child("temperature"), hs.Temperature, "must be greater than zero"))
+		}
+	case config.TopKHostSelector:
+		if hs.K <= 0 {
+			errs = append(errs, field.Invalid(path.Child("k"), hs.K, "must be greater than zero"))
+		}
+	case config.EpsilonGreedyHostSelector:
+		if hs.Epsilon < 0 || hs.Epsilon > 1 {
+			errs = append(errs, field.Invalid(path.Child("epsilon"), hs.Epsilon, "must be in the range [0, 1]"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(path.Child("type"), hs.Type, []string{
+			string(config.MaxScoreHostSelector),
+			string(config.SoftmaxHostSelector),
+			string(config.TopKHostSelector),
+			string(config.EpsilonGreedyHostSelector),
+		}))
+	}
+	return utilerrors.Flatten(utilerrors.NewAggregate(errs))
+}
+
 func validatePluginConfig(path *field.Path, apiVersion string, profile *config.KubeSchedulerProfile) []error {
 	var errs []error
 	m := map[string]interface{}{
@@ -286,6 +482,26 @@ func validateExtenders(fldPath *field.Path, extenders []config.Extender) []error
 		if extender.BindVerb != "" {
 			binders++
 		}
+		if extender.ResultCacheTTL.Duration < 0 {
+			errs = append(errs, field.Invalid(path.Child("resultCacheTTL"),
+				extender.ResultCacheTTL, "must be greater than or equal to 0"))
+		}
+		switch extender.Transport {
+		case "", config.ExtenderTransportHTTP:
+		case config.ExtenderTransportGRPC:
+			for verb, value := range map[string]string{
+				"reserveVerb":   extender.ReserveVerb,
+				"unreserveVerb": extender.UnreserveVerb,
+				"postBindVerb":  extender.PostBindVerb,
+			} {
+				if value != "" {
+					errs = append(errs, field.Invalid(path.Child(verb), value, "not supported with the GRPC transport"))
+				}
+			}
+		default:
+			errs = append(errs, field.NotSupported(path.Child("transport"), extender.Transport,
+				[]string{string(config.ExtenderTransportHTTP), string(config.ExtenderTransportGRPC)}))
+		}
 		for j, resource := range extender.ManagedResources {
 			managedResourcesPath := path.Child("managedResources").Index(j)
 			validationErrors := validateExtendedResourceName(managedResourcesPath.Child("name"), v1.ResourceName(resource.Name))
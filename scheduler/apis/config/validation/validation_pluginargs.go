@@ -18,6 +18,7 @@ package validation
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
@@ -31,6 +32,147 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/apis/config"
 )
 
+// ValidateBalancedPreemptionArgs validates that BalancedPreemptionArgs are correct.
+func ValidateBalancedPreemptionArgs(path *field.Path, args *config.BalancedPreemptionArgs) error {
+	var allErrs field.ErrorList
+	percentagePath := path.Child("minCandidateNodesPercentage")
+	absolutePath := path.Child("minCandidateNodesAbsolute")
+	if err := validateMinCandidateNodesPercentage(args.MinCandidateNodesPercentage, percentagePath); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := validateMinCandidateNodesAbsolute(args.MinCandidateNodesAbsolute, absolutePath); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if args.MinCandidateNodesPercentage == 0 && args.MinCandidateNodesAbsolute == 0 {
+		allErrs = append(allErrs,
+			field.Invalid(percentagePath, args.MinCandidateNodesPercentage, "cannot be zero at the same time as minCandidateNodesAbsolute"),
+			field.Invalid(absolutePath, args.MinCandidateNodesAbsolute, "cannot be zero at the same time as minCandidateNodesPercentage"))
+	}
+	if args.DisruptionWeight < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("disruptionWeight"), args.DisruptionWeight, "must not be negative"))
+	}
+	if args.BalanceWeight < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("balanceWeight"), args.BalanceWeight, "must not be negative"))
+	}
+	if args.DisruptionWeight == 0 && args.BalanceWeight == 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("balanceWeight"), args.BalanceWeight, "cannot be zero at the same time as disruptionWeight"))
+	}
+	if len(args.AgentURL) != 0 {
+		if u, err := url.Parse(args.AgentURL); err != nil || u.Scheme == "" || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("agentURL"), args.AgentURL, "must be an absolute URL"))
+		}
+	}
+	if args.AgentTimeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("agentTimeout"), args.AgentTimeout, "must not be negative"))
+	}
+	return allErrs.ToAggregate()
+}
+
+// ValidateCallGraphArgs validates that CallGraphArgs are correct.
+func ValidateCallGraphArgs(path *field.Path, args *config.CallGraphArgs) error {
+	var allErrs field.ErrorList
+	if len(args.ServiceLabel) != 0 {
+		allErrs = append(allErrs, metav1validation.ValidateLabelName(args.ServiceLabel, path.Child("serviceLabel"))...)
+	}
+	edges := sets.NewString()
+	for i, d := range args.Dependencies {
+		depPath := path.Child("dependencies").Index(i)
+		allErrs = append(allErrs, ValidateServiceDependency(depPath, &d)...)
+		edge := d.Upstream + "->" + d.Downstream
+		if edges.Has(edge) {
+			allErrs = append(allErrs, field.Duplicate(depPath, edge))
+		}
+		edges.Insert(edge)
+	}
+	if (len(args.DependencyConfigMapNamespace) == 0) != (len(args.DependencyConfigMapName) == 0) {
+		allErrs = append(allErrs, field.Invalid(path.Child("dependencyConfigMapName"), args.DependencyConfigMapName, "dependencyConfigMapNamespace and dependencyConfigMapName must be set together"))
+	}
+	return allErrs.ToAggregate()
+}
+
+// ValidateServiceDependency validates that a ServiceDependency is correct.
+func ValidateServiceDependency(path *field.Path, d *config.ServiceDependency) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(d.Upstream) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("upstream"), ""))
+	}
+	if len(d.Downstream) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("downstream"), ""))
+	}
+	if d.Weight <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("weight"), d.Weight, "must be greater than 0"))
+	}
+	return allErrs
+}
+
+// ValidateCapacitySchedulingArgs validates that CapacitySchedulingArgs are correct.
+func ValidateCapacitySchedulingArgs(path *field.Path, args *config.CapacitySchedulingArgs) error {
+	var allErrs field.ErrorList
+	namespaces := sets.NewString()
+	for i, q := range args.Quotas {
+		quotaPath := path.Child("quotas").Index(i)
+		allErrs = append(allErrs, ValidateElasticQuota(quotaPath, &q)...)
+		if namespaces.Has(q.Namespace) {
+			allErrs = append(allErrs, field.Duplicate(quotaPath.Child("namespace"), q.Namespace))
+		}
+		namespaces.Insert(q.Namespace)
+	}
+	if (len(args.QuotaConfigMapNamespace) == 0) != (len(args.QuotaConfigMapName) == 0) {
+		allErrs = append(allErrs, field.Invalid(path.Child("quotaConfigMapName"), args.QuotaConfigMapName, "quotaConfigMapNamespace and quotaConfigMapName must be set together"))
+	}
+	return allErrs.ToAggregate()
+}
+
+// ValidateElasticQuota validates that an ElasticQuota is correct.
+func ValidateElasticQuota(path *field.Path, q *config.ElasticQuota) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(q.Namespace) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("namespace"), ""))
+	}
+	for name, quantity := range q.Min {
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("min").Key(string(name)), quantity.String(), "must not be negative"))
+		}
+	}
+	for name, quantity := range q.Max {
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("max").Key(string(name)), quantity.String(), "must not be negative"))
+		}
+		if min, ok := q.Min[name]; ok && min.Cmp(quantity) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("min").Key(string(name)), min.String(), "must not be greater than max"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateCoschedulingArgs validates that CoschedulingArgs are correct.
+func ValidateCoschedulingArgs(path *field.Path, args *config.CoschedulingArgs) error {
+	var allErrs field.ErrorList
+	if args.PermitWaitingTimeSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("permitWaitingTimeSeconds"), args.PermitWaitingTimeSeconds, "must be greater than 0"))
+	}
+	return allErrs.ToAggregate()
+}
+
+// ValidateDeadlineSortArgs validates that DeadlineSortArgs are correct.
+func ValidateDeadlineSortArgs(path *field.Path, args *config.DeadlineSortArgs) error {
+	var allErrs field.ErrorList
+	names := sets.NewString()
+	for i, c := range args.SLOClasses {
+		classPath := path.Child("sloClasses").Index(i)
+		if len(c.Name) == 0 {
+			allErrs = append(allErrs, field.Required(classPath.Child("name"), ""))
+		} else if names.Has(c.Name) {
+			allErrs = append(allErrs, field.Duplicate(classPath.Child("name"), c.Name))
+		}
+		names.Insert(c.Name)
+		if c.Budget.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(classPath.Child("budget"), c.Budget, "must be greater than 0"))
+		}
+	}
+	return allErrs.ToAggregate()
+}
+
 // ValidateDefaultPreemptionArgs validates that DefaultPreemptionArgs are correct.
 func ValidateDefaultPreemptionArgs(path *field.Path, args *config.DefaultPreemptionArgs) error {
 	var allErrs field.ErrorList
@@ -68,11 +210,54 @@ func validateMinCandidateNodesAbsolute(minCandidateNodesAbsolute int32, p *field
 	return nil
 }
 
+// ValidateFairShareArgs validates that FairShareArgs are correct.
+func ValidateFairShareArgs(path *field.Path, args *config.FairShareArgs) error {
+	var allErrs field.ErrorList
+	if len(args.TenantLabel) != 0 {
+		allErrs = append(allErrs, metav1validation.ValidateLabelName(args.TenantLabel, path.Child("tenantLabel"))...)
+	}
+	tenants := sets.NewString()
+	for i, w := range args.Weights {
+		weightPath := path.Child("weights").Index(i)
+		if len(w.Tenant) == 0 {
+			allErrs = append(allErrs, field.Required(weightPath.Child("tenant"), ""))
+		} else if tenants.Has(w.Tenant) {
+			allErrs = append(allErrs, field.Duplicate(weightPath.Child("tenant"), w.Tenant))
+		}
+		tenants.Insert(w.Tenant)
+		if w.Weight <= 0 {
+			allErrs = append(allErrs, field.Invalid(weightPath.Child("weight"), w.Weight, "must be greater than 0"))
+		}
+	}
+	if args.DefaultWeight <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("defaultWeight"), args.DefaultWeight, "must be greater than 0"))
+	}
+	if args.HalfLifeSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("halfLifeSeconds"), args.HalfLifeSeconds, "must be greater than 0"))
+	}
+	return allErrs.ToAggregate()
+}
+
 // ValidateInterPodAffinityArgs validates that InterPodAffinityArgs are correct.
 func ValidateInterPodAffinityArgs(path *field.Path, args *config.InterPodAffinityArgs) error {
 	return validateHardPodAffinityWeight(path.Child("hardPodAffinityWeight"), args.HardPodAffinityWeight)
 }
 
+// ValidateNetworkTopologyArgs validates that NetworkTopologyArgs are correct.
+func ValidateNetworkTopologyArgs(path *field.Path, args *config.NetworkTopologyArgs) error {
+	var allErrs field.ErrorList
+	if len(args.ServiceLabel) != 0 {
+		allErrs = append(allErrs, metav1validation.ValidateLabelName(args.ServiceLabel, path.Child("serviceLabel"))...)
+	}
+	if args.MaxPeerLatency.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxPeerLatency"), args.MaxPeerLatency, "must not be negative"))
+	}
+	if (len(args.LinkConfigMapNamespace) == 0) != (len(args.LinkConfigMapName) == 0) {
+		allErrs = append(allErrs, field.Invalid(path.Child("linkConfigMapName"), args.LinkConfigMapName, "linkConfigMapNamespace and linkConfigMapName must be set together"))
+	}
+	return allErrs.ToAggregate()
+}
+
 // validateHardPodAffinityWeight validates that weight is within allowed range.
 func validateHardPodAffinityWeight(path *field.Path, w int32) error {
 	const (
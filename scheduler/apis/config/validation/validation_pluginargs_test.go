@@ -20,10 +20,12 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -38,6 +40,328 @@ var (
 	ignoreBadValueDetail = cmpopts.IgnoreFields(field.Error{}, "BadValue", "Detail")
 )
 
+func TestValidateBalancedPreemptionArgs(t *testing.T) {
+	cases := map[string]struct {
+		args     config.BalancedPreemptionArgs
+		wantErrs field.ErrorList
+	}{
+		"valid args": {
+			args: config.BalancedPreemptionArgs{
+				MinCandidateNodesPercentage: 10,
+				MinCandidateNodesAbsolute:   100,
+				DisruptionWeight:            1,
+				BalanceWeight:               2,
+				AgentURL:                    "http://agent:1234/rank",
+				AgentTimeout:                metav1.Duration{Duration: time.Second},
+			},
+		},
+		"balance only": {
+			args: config.BalancedPreemptionArgs{
+				MinCandidateNodesPercentage: 10,
+				BalanceWeight:               1,
+			},
+		},
+		"invalid candidate nodes": {
+			args: config.BalancedPreemptionArgs{
+				MinCandidateNodesPercentage: 900,
+				MinCandidateNodesAbsolute:   -1,
+				DisruptionWeight:            1,
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "minCandidateNodesPercentage",
+				}, &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "minCandidateNodesAbsolute",
+				},
+			},
+		},
+		"negative weight": {
+			args: config.BalancedPreemptionArgs{
+				MinCandidateNodesPercentage: 10,
+				DisruptionWeight:            -1,
+				BalanceWeight:               1,
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "disruptionWeight",
+				},
+			},
+		},
+		"zero weights": {
+			args: config.BalancedPreemptionArgs{
+				MinCandidateNodesPercentage: 10,
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "balanceWeight",
+				},
+			},
+		},
+		"relative agent URL and negative timeout": {
+			args: config.BalancedPreemptionArgs{
+				MinCandidateNodesPercentage: 10,
+				DisruptionWeight:            1,
+				AgentURL:                    "/rank",
+				AgentTimeout:                metav1.Duration{Duration: -time.Second},
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "agentURL",
+				}, &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "agentTimeout",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateBalancedPreemptionArgs(nil, &tc.args)
+			if diff := cmp.Diff(tc.wantErrs.ToAggregate(), err, ignoreBadValueDetail); diff != "" {
+				t.Errorf("ValidateBalancedPreemptionArgs returned err (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCallGraphArgs(t *testing.T) {
+	cases := map[string]struct {
+		args     config.CallGraphArgs
+		wantErrs field.ErrorList
+	}{
+		"valid args": {
+			args: config.CallGraphArgs{
+				ServiceLabel: "app.kubernetes.io/name",
+				Dependencies: []config.ServiceDependency{
+					{Upstream: "frontend", Downstream: "backend", Weight: 10},
+					{Upstream: "backend", Downstream: "frontend", Weight: 1},
+				},
+				DependencyConfigMapNamespace: "kube-system",
+				DependencyConfigMapName:      "call-graph",
+			},
+		},
+		"invalid service label": {
+			args: config.CallGraphArgs{
+				ServiceLabel: "-app",
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "serviceLabel",
+				},
+			},
+		},
+		"invalid dependencies": {
+			args: config.CallGraphArgs{
+				Dependencies: []config.ServiceDependency{
+					{Downstream: "backend", Weight: 1},
+					{Upstream: "frontend", Downstream: "backend"},
+					{Upstream: "frontend", Downstream: "backend", Weight: 1},
+				},
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "dependencies[0].upstream",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "dependencies[1].weight",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeDuplicate,
+					Field: "dependencies[2]",
+				},
+			},
+		},
+		"ConfigMap name without namespace": {
+			args: config.CallGraphArgs{
+				DependencyConfigMapName: "call-graph",
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "dependencyConfigMapName",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateCallGraphArgs(nil, &tc.args)
+			if diff := cmp.Diff(tc.wantErrs.ToAggregate(), err, ignoreBadValueDetail); diff != "" {
+				t.Errorf("ValidateCallGraphArgs returned err (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCapacitySchedulingArgs(t *testing.T) {
+	cases := map[string]struct {
+		args     config.CapacitySchedulingArgs
+		wantErrs field.ErrorList
+	}{
+		"valid args": {
+			args: config.CapacitySchedulingArgs{
+				Quotas: []config.ElasticQuota{
+					{
+						Namespace: "team-a",
+						Min:       v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+						Max:       v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("1Gi")},
+					},
+					{
+						Namespace: "team-b",
+						Min:       v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+					},
+				},
+				QuotaConfigMapNamespace: "kube-system",
+				QuotaConfigMapName:      "quotas",
+			},
+		},
+		"invalid quotas": {
+			args: config.CapacitySchedulingArgs{
+				Quotas: []config.ElasticQuota{
+					{
+						Min: v1.ResourceList{v1.ResourceCPU: resource.MustParse("-1")},
+					},
+					{
+						Namespace: "team-a",
+						Min:       v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+						Max:       v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+					},
+					{
+						Namespace: "team-a",
+					},
+				},
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "quotas[0].namespace",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "quotas[0].min[cpu]",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "quotas[1].min[cpu]",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeDuplicate,
+					Field: "quotas[2].namespace",
+				},
+			},
+		},
+		"configmap name without namespace": {
+			args: config.CapacitySchedulingArgs{
+				QuotaConfigMapName: "quotas",
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "quotaConfigMapName",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateCapacitySchedulingArgs(nil, &tc.args)
+			if diff := cmp.Diff(tc.wantErrs.ToAggregate(), err, ignoreBadValueDetail); diff != "" {
+				t.Errorf("ValidateCapacitySchedulingArgs returned err (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCoschedulingArgs(t *testing.T) {
+	cases := map[string]struct {
+		args     config.CoschedulingArgs
+		wantErrs field.ErrorList
+	}{
+		"valid args": {
+			args: config.CoschedulingArgs{
+				PermitWaitingTimeSeconds: 60,
+			},
+		},
+		"zero permitWaitingTimeSeconds": {
+			args: config.CoschedulingArgs{},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "permitWaitingTimeSeconds",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateCoschedulingArgs(nil, &tc.args)
+			if diff := cmp.Diff(tc.wantErrs.ToAggregate(), err, ignoreBadValueDetail); diff != "" {
+				t.Errorf("ValidateCoschedulingArgs returned err (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateDeadlineSortArgs(t *testing.T) {
+	cases := map[string]struct {
+		args     config.DeadlineSortArgs
+		wantErrs field.ErrorList
+	}{
+		"valid args": {
+			args: config.DeadlineSortArgs{
+				SLOClasses: []config.SLOClass{
+					{Name: "latency-critical", Budget: metav1.Duration{Duration: 500 * time.Millisecond}},
+					{Name: "batch", Budget: metav1.Duration{Duration: time.Hour}},
+				},
+			},
+		},
+		"invalid SLO classes": {
+			args: config.DeadlineSortArgs{
+				SLOClasses: []config.SLOClass{
+					{Budget: metav1.Duration{Duration: time.Second}},
+					{Name: "batch"},
+					{Name: "batch", Budget: metav1.Duration{Duration: time.Second}},
+				},
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "sloClasses[0].name",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "sloClasses[1].budget",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeDuplicate,
+					Field: "sloClasses[2].name",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateDeadlineSortArgs(nil, &tc.args)
+			if diff := cmp.Diff(tc.wantErrs.ToAggregate(), err, ignoreBadValueDetail); diff != "" {
+				t.Errorf("ValidateDeadlineSortArgs returned err (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestValidateDefaultPreemptionArgs(t *testing.T) {
 	cases := map[string]struct {
 		args     config.DefaultPreemptionArgs
@@ -127,6 +451,70 @@ func TestValidateDefaultPreemptionArgs(t *testing.T) {
 	}
 }
 
+func TestValidateFairShareArgs(t *testing.T) {
+	cases := map[string]struct {
+		args     config.FairShareArgs
+		wantErrs field.ErrorList
+	}{
+		"valid args": {
+			args: config.FairShareArgs{
+				TenantLabel: "example.com/workload-class",
+				Weights: []config.TenantWeight{
+					{Tenant: "batch", Weight: 1},
+					{Tenant: "interactive", Weight: 4},
+				},
+				DefaultWeight:   1,
+				HalfLifeSeconds: 300,
+			},
+		},
+		"invalid args": {
+			args: config.FairShareArgs{
+				TenantLabel: "in valid",
+				Weights: []config.TenantWeight{
+					{Weight: 1},
+					{Tenant: "batch", Weight: 0},
+					{Tenant: "batch", Weight: 1},
+				},
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "tenantLabel",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "weights[0].tenant",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "weights[1].weight",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeDuplicate,
+					Field: "weights[2].tenant",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "defaultWeight",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "halfLifeSeconds",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateFairShareArgs(nil, &tc.args)
+			if diff := cmp.Diff(tc.wantErrs.ToAggregate(), err, ignoreBadValueDetail); diff != "" {
+				t.Errorf("ValidateFairShareArgs returned err (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestValidateInterPodAffinityArgs(t *testing.T) {
 	cases := map[string]struct {
 		args    config.InterPodAffinityArgs
@@ -167,6 +555,52 @@ func TestValidateInterPodAffinityArgs(t *testing.T) {
 	}
 }
 
+func TestValidateNetworkTopologyArgs(t *testing.T) {
+	cases := map[string]struct {
+		args     config.NetworkTopologyArgs
+		wantErrs field.ErrorList
+	}{
+		"valid args": {
+			args: config.NetworkTopologyArgs{
+				ServiceLabel:           "app.kubernetes.io/name",
+				MaxPeerLatency:         metav1.Duration{Duration: time.Millisecond},
+				LinkConfigMapNamespace: "kube-system",
+				LinkConfigMapName:      "network-links",
+			},
+		},
+		"invalid args": {
+			args: config.NetworkTopologyArgs{
+				ServiceLabel:           "-app",
+				MaxPeerLatency:         metav1.Duration{Duration: -time.Millisecond},
+				LinkConfigMapNamespace: "kube-system",
+			},
+			wantErrs: field.ErrorList{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "serviceLabel",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "maxPeerLatency",
+				},
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "linkConfigMapName",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateNetworkTopologyArgs(nil, &tc.args)
+			if diff := cmp.Diff(tc.wantErrs.ToAggregate(), err, ignoreBadValueDetail); diff != "" {
+				t.Errorf("ValidateNetworkTopologyArgs returned err (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestValidatePodTopologySpreadArgs(t *testing.T) {
 	cases := map[string]struct {
 		args     *config.PodTopologySpreadArgs
@@ -198,6 +198,23 @@ func TestValidateKubeSchedulerConfigurationV1beta2(t *testing.T) {
 		BindVerb:       "bar",
 	})
 
+	extenderGRPCTransport := validConfig.DeepCopy()
+	extenderGRPCTransport.Extenders[0].Transport = config.ExtenderTransportGRPC
+
+	extenderUnknownTransport := validConfig.DeepCopy()
+	extenderUnknownTransport.Extenders[0].Transport = "UDP"
+
+	extenderReserveVerbs := validConfig.DeepCopy()
+	extenderReserveVerbs.Extenders[0].ReserveVerb = "reserve"
+	extenderReserveVerbs.Extenders[0].UnreserveVerb = "unreserve"
+	extenderReserveVerbs.Extenders[0].PostBindVerb = "postbind"
+
+	extenderGRPCReserveVerb := extenderGRPCTransport.DeepCopy()
+	extenderGRPCReserveVerb.Extenders[0].ReserveVerb = "reserve"
+
+	extenderNegativeCacheTTL := validConfig.DeepCopy()
+	extenderNegativeCacheTTL.Extenders[0].ResultCacheTTL = metav1.Duration{Duration: -time.Second}
+
 	goodRemovedPlugins2 := validConfig.DeepCopy()
 	goodRemovedPlugins2.Profiles[0].Plugins.Score.Enabled = append(goodRemovedPlugins2.Profiles[0].Plugins.Score.Enabled, config.Plugin{Name: "PodTopologySpread", Weight: 2})
 
@@ -262,6 +279,26 @@ func TestValidateKubeSchedulerConfigurationV1beta2(t *testing.T) {
 			expectedToFail: true,
 			config:         extenderDuplicateBind,
 		},
+		"extender-grpc-transport": {
+			expectedToFail: false,
+			config:         extenderGRPCTransport,
+		},
+		"extender-unknown-transport": {
+			expectedToFail: true,
+			config:         extenderUnknownTransport,
+		},
+		"extender-reserve-verbs": {
+			expectedToFail: false,
+			config:         extenderReserveVerbs,
+		},
+		"extender-grpc-reserve-verb": {
+			expectedToFail: true,
+			config:         extenderGRPCReserveVerb,
+		},
+		"extender-negative-result-cache-ttl": {
+			expectedToFail: true,
+			config:         extenderNegativeCacheTTL,
+		},
 		"invalid-node-percentage": {
 			expectedToFail: true,
 			config:         invalidNodePercentage,
@@ -472,6 +509,23 @@ func TestValidateKubeSchedulerConfigurationV1beta3(t *testing.T) {
 		BindVerb:       "bar",
 	})
 
+	extenderGRPCTransport := validConfig.DeepCopy()
+	extenderGRPCTransport.Extenders[0].Transport = config.ExtenderTransportGRPC
+
+	extenderUnknownTransport := validConfig.DeepCopy()
+	extenderUnknownTransport.Extenders[0].Transport = "UDP"
+
+	extenderReserveVerbs := validConfig.DeepCopy()
+	extenderReserveVerbs.Extenders[0].ReserveVerb = "reserve"
+	extenderReserveVerbs.Extenders[0].UnreserveVerb = "unreserve"
+	extenderReserveVerbs.Extenders[0].PostBindVerb = "postbind"
+
+	extenderGRPCReserveVerb := extenderGRPCTransport.DeepCopy()
+	extenderGRPCReserveVerb.Extenders[0].ReserveVerb = "reserve"
+
+	extenderNegativeCacheTTL := validConfig.DeepCopy()
+	extenderNegativeCacheTTL.Extenders[0].ResultCacheTTL = metav1.Duration{Duration: -time.Second}
+
 	goodRemovedPlugins2 := validConfig.DeepCopy()
 	goodRemovedPlugins2.Profiles[0].Plugins.Score.Enabled = append(goodRemovedPlugins2.Profiles[0].Plugins.Score.Enabled, config.Plugin{Name: "PodTopologySpread", Weight: 2})
 
@@ -536,6 +590,26 @@ func TestValidateKubeSchedulerConfigurationV1beta3(t *testing.T) {
 			expectedToFail: true,
 			config:         extenderDuplicateBind,
 		},
+		"extender-grpc-transport": {
+			expectedToFail: false,
+			config:         extenderGRPCTransport,
+		},
+		"extender-unknown-transport": {
+			expectedToFail: true,
+			config:         extenderUnknownTransport,
+		},
+		"extender-reserve-verbs": {
+			expectedToFail: false,
+			config:         extenderReserveVerbs,
+		},
+		"extender-grpc-reserve-verb": {
+			expectedToFail: true,
+			config:         extenderGRPCReserveVerb,
+		},
+		"extender-negative-result-cache-ttl": {
+			expectedToFail: true,
+			config:         extenderNegativeCacheTTL,
+		},
 		"invalid-node-percentage": {
 			expectedToFail: true,
 			config:         invalidNodePercentage,
@@ -575,3 +649,199 @@ func TestValidateKubeSchedulerConfigurationV1beta3(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBackoffPolicy(t *testing.T) {
+	tests := map[string]struct {
+		policy  *config.BackoffPolicy
+		wantErr bool
+	}{
+		"valid": {
+			policy: &config.BackoffPolicy{
+				Type:   config.LinearBackoffPolicy,
+				Jitter: 0.2,
+				PluginOverrides: []config.PluginBackoffOverride{
+					{PluginName: "dqn-plugin", InitialBackoffSeconds: 1, MaxBackoffSeconds: 2},
+				},
+			},
+		},
+		"unknown type": {
+			policy:  &config.BackoffPolicy{Type: "Fibonacci"},
+			wantErr: true,
+		},
+		"invalid jitter": {
+			policy:  &config.BackoffPolicy{Jitter: 1},
+			wantErr: true,
+		},
+		"invalid plugin override": {
+			policy: &config.BackoffPolicy{
+				PluginOverrides: []config.PluginBackoffOverride{
+					{PluginName: "dqn-plugin", InitialBackoffSeconds: 2, MaxBackoffSeconds: 1},
+				},
+			},
+			wantErr: true,
+		},
+		"duplicate plugin override": {
+			policy: &config.BackoffPolicy{
+				PluginOverrides: []config.PluginBackoffOverride{
+					{PluginName: "dqn-plugin", InitialBackoffSeconds: 1, MaxBackoffSeconds: 1},
+					{PluginName: "dqn-plugin", InitialBackoffSeconds: 1, MaxBackoffSeconds: 1},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateBackoffPolicy(tc.policy)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ValidateBackoffPolicy() = %v, want error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateProfileRoutes(t *testing.T) {
+	profiles := []config.KubeSchedulerProfile{{SchedulerName: "me"}, {SchedulerName: "other"}}
+	validRoutes := func() []config.ProfileRoute {
+		return []config.ProfileRoute{
+			{
+				SchedulerName:     "other",
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "drs"}},
+			},
+			{
+				SchedulerName: "me",
+				NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "team", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"drs"}},
+				}},
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			{
+				SchedulerName: "other",
+				PodSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "batch"}},
+			},
+		}
+	}
+
+	overlappingRoutes := validRoutes()
+	overlappingRoutes[1].NamespaceSelector = nil
+
+	unknownProfileRoutes := validRoutes()
+	unknownProfileRoutes[0].SchedulerName = "unknown"
+
+	routesWithoutSelector := validRoutes()
+	routesWithoutSelector[2].PodSelector = nil
+
+	tests := map[string]struct {
+		routes         []config.ProfileRoute
+		defaultProfile string
+		wantErr        bool
+	}{
+		"valid": {
+			routes:         validRoutes(),
+			defaultProfile: "me",
+		},
+		"overlapping routes": {
+			routes:  overlappingRoutes,
+			wantErr: true,
+		},
+		"route to unknown profile": {
+			routes:  unknownProfileRoutes,
+			wantErr: true,
+		},
+		"route without selector": {
+			routes:  routesWithoutSelector,
+			wantErr: true,
+		},
+		"unknown default profile": {
+			defaultProfile: "unknown",
+			wantErr:        true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateProfileRoutes(tc.routes, tc.defaultProfile, profiles)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ValidateProfileRoutes() = %v, want error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHostSelector(t *testing.T) {
+	tests := map[string]struct {
+		selector    *config.HostSelector
+		errorString string
+	}{
+		"valid": {
+			selector: &config.HostSelector{Type: config.SoftmaxHostSelector, Temperature: 0.5},
+		},
+		"unknown type": {
+			selector:    &config.HostSelector{Type: "Unknown"},
+			errorString: `hostSelector.type: Unsupported value: "Unknown": supported values: "MaxScore", "Softmax", "TopK", "EpsilonGreedy"`,
+		},
+		"invalid softmax temperature": {
+			selector:    &config.HostSelector{Type: config.SoftmaxHostSelector},
+			errorString: "hostSelector.temperature: Invalid value: 0: must be greater than zero",
+		},
+		"invalid top k": {
+			selector:    &config.HostSelector{Type: config.TopKHostSelector, K: -1},
+			errorString: "hostSelector.k: Invalid value: -1: must be greater than zero",
+		},
+		"invalid epsilon": {
+			selector:    &config.HostSelector{Type: config.EpsilonGreedyHostSelector, Epsilon: 1.5},
+			errorString: "hostSelector.epsilon: Invalid value: 1.5: must be in the range [0, 1]",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateHostSelector(tc.selector)
+			var got string
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tc.errorString {
+				t.Errorf("Unexpected error string\n want:\t%s\n got:\t%s", tc.errorString, got)
+			}
+		})
+	}
+}
+
+func TestValidateScoreWeightTuning(t *testing.T) {
+	tests := map[string]struct {
+		tuning  *config.ScoreWeightTuning
+		wantErr bool
+	}{
+		"valid": {
+			tuning: &config.ScoreWeightTuning{
+				Plugins:   []string{"ImageLocality", "NodeResourcesBalancedAllocation"},
+				Interval:  metav1.Duration{Duration: time.Minute},
+				Epsilon:   0.1,
+				MaxWeight: 10,
+			},
+		},
+		"no interval": {
+			tuning:  &config.ScoreWeightTuning{MaxWeight: 10},
+			wantErr: true,
+		},
+		"no max weight": {
+			tuning:  &config.ScoreWeightTuning{Interval: metav1.Duration{Duration: time.Minute}},
+			wantErr: true,
+		},
+		"duplicate plugin": {
+			tuning: &config.ScoreWeightTuning{
+				Plugins:   []string{"ImageLocality", "ImageLocality"},
+				Interval:  metav1.Duration{Duration: time.Minute},
+				MaxWeight: 10,
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateScoreWeightTuning(tc.tuning)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ValidateScoreWeightTuning() = %v, want error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
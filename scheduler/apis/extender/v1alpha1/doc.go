@@ -0,0 +1,23 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative extender.proto
+
+// Package v1alpha1 contains the Extender gRPC service defined by
+// extender.proto, which extenders implement to be called by the scheduler
+// over gRPC instead of HTTP. The code is generated by protoc-gen-go and
+// protoc-gen-go-grpc.
+package v1alpha1 // import "k8s.io/kubernetes/pkg/scheduler/apis/extender/v1alpha1"
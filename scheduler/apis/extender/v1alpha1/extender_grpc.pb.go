@@ -0,0 +1,223 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: extender.proto
+
+package v1alpha1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ExtenderClient is the client API for Extender service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExtenderClient interface {
+	// Filter returns the nodes the pod fits on among the given ones.
+	Filter(ctx context.Context, in *ExtenderArgs, opts ...grpc.CallOption) (*ExtenderFilterResult, error)
+	// Prioritize scores the given nodes for the pod.
+	Prioritize(ctx context.Context, in *ExtenderArgs, opts ...grpc.CallOption) (*HostPriorityList, error)
+	// Bind binds the pod to the node.
+	Bind(ctx context.Context, in *ExtenderBindingArgs, opts ...grpc.CallOption) (*ExtenderBindingResult, error)
+	// ProcessPreemption returns the preemption candidates the extender
+	// accepts, with the victims it adds to them.
+	ProcessPreemption(ctx context.Context, in *ExtenderPreemptionArgs, opts ...grpc.CallOption) (*ExtenderPreemptionResult, error)
+}
+
+type extenderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExtenderClient(cc grpc.ClientConnInterface) ExtenderClient {
+	return &extenderClient{cc}
+}
+
+func (c *extenderClient) Filter(ctx context.Context, in *ExtenderArgs, opts ...grpc.CallOption) (*ExtenderFilterResult, error) {
+	out := new(ExtenderFilterResult)
+	err := c.cc.Invoke(ctx, "/scheduler.extender.v1alpha1.Extender/Filter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extenderClient) Prioritize(ctx context.Context, in *ExtenderArgs, opts ...grpc.CallOption) (*HostPriorityList, error) {
+	out := new(HostPriorityList)
+	err := c.cc.Invoke(ctx, "/scheduler.extender.v1alpha1.Extender/Prioritize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extenderClient) Bind(ctx context.Context, in *ExtenderBindingArgs, opts ...grpc.CallOption) (*ExtenderBindingResult, error) {
+	out := new(ExtenderBindingResult)
+	err := c.cc.Invoke(ctx, "/scheduler.extender.v1alpha1.Extender/Bind", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extenderClient) ProcessPreemption(ctx context.Context, in *ExtenderPreemptionArgs, opts ...grpc.CallOption) (*ExtenderPreemptionResult, error) {
+	out := new(ExtenderPreemptionResult)
+	err := c.cc.Invoke(ctx, "/scheduler.extender.v1alpha1.Extender/ProcessPreemption", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExtenderServer is the server API for Extender service.
+// All implementations must embed UnimplementedExtenderServer
+// for forward compatibility
+type ExtenderServer interface {
+	// Filter returns the nodes the pod fits on among the given ones.
+	Filter(context.Context, *ExtenderArgs) (*ExtenderFilterResult, error)
+	// Prioritize scores the given nodes for the pod.
+	Prioritize(context.Context, *ExtenderArgs) (*HostPriorityList, error)
+	// Bind binds the pod to the node.
+	Bind(context.Context, *ExtenderBindingArgs) (*ExtenderBindingResult, error)
+	// ProcessPreemption returns the preemption candidates the extender
+	// accepts, with the victims it adds to them.
+	ProcessPreemption(context.Context, *ExtenderPreemptionArgs) (*ExtenderPreemptionResult, error)
+	mustEmbedUnimplementedExtenderServer()
+}
+
+// UnimplementedExtenderServer must be embedded to have forward compatible implementations.
+type UnimplementedExtenderServer struct {
+}
+
+func (UnimplementedExtenderServer) Filter(context.Context, *ExtenderArgs) (*ExtenderFilterResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Filter not implemented")
+}
+func (UnimplementedExtenderServer) Prioritize(context.Context, *ExtenderArgs) (*HostPriorityList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prioritize not implemented")
+}
+func (UnimplementedExtenderServer) Bind(context.Context, *ExtenderBindingArgs) (*ExtenderBindingResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Bind not implemented")
+}
+func (UnimplementedExtenderServer) ProcessPreemption(context.Context, *ExtenderPreemptionArgs) (*ExtenderPreemptionResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessPreemption not implemented")
+}
+func (UnimplementedExtenderServer) mustEmbedUnimplementedExtenderServer() {}
+
+// UnsafeExtenderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExtenderServer will
+// result in compilation errors.
+type UnsafeExtenderServer interface {
+	mustEmbedUnimplementedExtenderServer()
+}
+
+func RegisterExtenderServer(s grpc.ServiceRegistrar, srv ExtenderServer) {
+	s.RegisterService(&Extender_ServiceDesc, srv)
+}
+
+func _Extender_Filter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtenderArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtenderServer).Filter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/scheduler.extender.v1alpha1.Extender/Filter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtenderServer).Filter(ctx, req.(*ExtenderArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Extender_Prioritize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtenderArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtenderServer).Prioritize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/scheduler.extender.v1alpha1.Extender/Prioritize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtenderServer).Prioritize(ctx, req.(*ExtenderArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Extender_Bind_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtenderBindingArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtenderServer).Bind(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/scheduler.extender.v1alpha1.Extender/Bind",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtenderServer).Bind(ctx, req.(*ExtenderBindingArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Extender_ProcessPreemption_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtenderPreemptionArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtenderServer).ProcessPreemption(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/scheduler.extender.v1alpha1.Extender/ProcessPreemption",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtenderServer).ProcessPreemption(ctx, req.(*ExtenderPreemptionArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Extender_ServiceDesc is the grpc.ServiceDesc for Extender service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Extender_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "scheduler.extender.v1alpha1.Extender",
+	HandlerType: (*ExtenderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Filter",
+			Handler:    _Extender_Filter_Handler,
+		},
+		{
+			MethodName: "Prioritize",
+			Handler:    _Extender_Prioritize_Handler,
+		},
+		{
+			MethodName: "Bind",
+			Handler:    _Extender_Bind_Handler,
+		},
+		{
+			MethodName: "ProcessPreemption",
+			Handler:    _Extender_ProcessPreemption_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "extender.proto",
+}
@@ -18,8 +18,11 @@ package scheduler
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic/dynamicinformer"
@@ -28,6 +31,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
@@ -39,7 +43,6 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/nodeports"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/noderesources"
 	"k8s.io/kubernetes/pkg/scheduler/internal/queue"
-	"k8s.io/kubernetes/pkg/scheduler/profile"
 )
 
 func (sched *Scheduler) onStorageClassAdd(obj interface{}) {
@@ -56,7 +59,7 @@ func (sched *Scheduler) onStorageClassAdd(obj interface{}) {
 	// We don't need to invalidate cached results because results will not be
 	// cached for pod that has unbound immediate PVCs.
 	if sc.VolumeBindingMode != nil && *sc.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
-		sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.StorageClassAdd, nil)
+		sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.StorageClassAdd, nil, sc, nil)
 	}
 }
 
@@ -69,7 +72,7 @@ func (sched *Scheduler) addNodeToCache(obj interface{}) {
 
 	nodeInfo := sched.SchedulerCache.AddNode(node)
 	klog.V(3).InfoS("Add event for node", "node", klog.KObj(node))
-	sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.NodeAdd, preCheckForNode(nodeInfo))
+	sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.NodeAdd, nil, node, preCheckForNode(nodeInfo))
 }
 
 func (sched *Scheduler) updateNodeInCache(oldObj, newObj interface{}) {
@@ -87,7 +90,7 @@ func (sched *Scheduler) updateNodeInCache(oldObj, newObj interface{}) {
 	nodeInfo := sched.SchedulerCache.UpdateNode(oldNode, newNode)
 	// Only requeue unschedulable pods if the node became more schedulable.
 	if event := nodeSchedulingPropertiesChange(newNode, oldNode); event != nil {
-		sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(*event, preCheckForNode(nodeInfo))
+		sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(*event, oldNode, newNode, preCheckForNode(nodeInfo))
 	}
 }
 
@@ -111,6 +114,107 @@ func (sched *Scheduler) deleteNodeFromCache(obj interface{}) {
 	if err := sched.SchedulerCache.RemoveNode(node); err != nil {
 		klog.ErrorS(err, "Scheduler cache RemoveNode failed")
 	}
+	if sched.nodeUtilization != nil {
+		sched.nodeUtilization.forget(node.Name)
+	}
+	if sched.networkTopology != nil {
+		sched.networkTopology.RemoveNode(node.Name)
+	}
+}
+
+// ReportNodeUtilization is called by the node telemetry with the utilization
+// of a node, as a fraction of its capacity. When the utilization of a node
+// drops below the threshold, the pods waiting for nodes to cool down, as
+// registered by plugins with a NodeUtilization Update event, are moved to
+// activeQ or backoffQ.
+func (sched *Scheduler) ReportNodeUtilization(nodeName string, utilization float64) {
+	if sched.nodeUtilization == nil || !sched.nodeUtilization.report(nodeName, utilization) {
+		return
+	}
+	klog.V(4).InfoS("Node utilization dropped below the threshold", "node", klog.KRef("", nodeName), "utilization", utilization)
+	sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.NodeUtilizationDrop, nil, nil, nil)
+}
+
+// ReportNodeLink is called by the node telemetry with the latency and the
+// bandwidth, in bytes per second, measured from a node to another. When a link
+// is new or its latency decreased, the pods waiting for better links, as
+// registered by plugins with a NetworkLink Update event, are moved to activeQ
+// or backoffQ.
+func (sched *Scheduler) ReportNodeLink(from, to string, latency time.Duration, bandwidth int64) {
+	if sched.networkTopology == nil {
+		return
+	}
+	if !sched.networkTopology.SetLink(from, to, framework.NodeLink{Latency: latency, Bandwidth: bandwidth}) {
+		return
+	}
+	klog.V(5).InfoS("Network link improved", "from", klog.KRef("", from), "to", klog.KRef("", to), "latency", latency, "bandwidth", bandwidth)
+	sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.NetworkLinkUpdate, nil, nil, nil)
+}
+
+// nodeUtilizationTracker tracks which nodes are above the utilization
+// threshold, to detect when their utilization drops below it.
+type nodeUtilizationTracker struct {
+	threshold float64
+
+	mu sync.Mutex
+	// hot holds the nodes whose last reported utilization was at or above the
+	// threshold.
+	hot sets.String
+	// utilization holds the last reported utilization of the nodes.
+	utilization map[string]float64
+}
+
+func newNodeUtilizationTracker(threshold float64) *nodeUtilizationTracker {
+	return &nodeUtilizationTracker{
+		threshold:   threshold,
+		hot:         sets.NewString(),
+		utilization: make(map[string]float64),
+	}
+}
+
+// report records the utilization of a node, returning whether it dropped
+// below the threshold. The first report of a node below the threshold
+// doesn't count as a drop, as no pod could have been waiting for it.
+func (t *nodeUtilizationTracker) report(nodeName string, utilization float64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.utilization[nodeName] = utilization
+	if utilization >= t.threshold {
+		t.hot.Insert(nodeName)
+		return false
+	}
+	if !t.hot.Has(nodeName) {
+		return false
+	}
+	t.hot.Delete(nodeName)
+	return true
+}
+
+// forget stops tracking the node.
+func (t *nodeUtilizationTracker) forget(nodeName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.hot.Delete(nodeName)
+	delete(t.utilization, nodeName)
+}
+
+// balance returns the opposite of the standard deviation of the last reported
+// utilization of the nodes, which is higher when the load is better balanced,
+// or false if no node reported its utilization.
+func (t *nodeUtilizationTracker) balance() (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.utilization) == 0 {
+		return 0, false
+	}
+	var sum, sumSquares float64
+	for _, u := range t.utilization {
+		sum += u
+		sumSquares += u * u
+	}
+	n := float64(len(t.utilization))
+	mean := sum / n
+	return -math.Sqrt(math.Max(sumSquares/n-mean*mean, 0)), true
 }
 
 func (sched *Scheduler) addPodToSchedulingQueue(obj interface{}) {
@@ -173,7 +277,7 @@ func (sched *Scheduler) deletePodFromSchedulingQueue(obj interface{}) {
 	// removing it from the scheduler cache. In this case, signal a AssignedPodDelete
 	// event to immediately retry some unscheduled Pods.
 	if fwk.RejectWaitingPod(pod.UID) {
-		sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.AssignedPodDelete, nil)
+		sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.AssignedPodDelete, pod, nil, nil)
 	}
 }
 
@@ -241,7 +345,7 @@ func (sched *Scheduler) deletePodFromCache(obj interface{}) {
 		klog.ErrorS(err, "Scheduler cache RemovePod failed", "pod", klog.KObj(pod))
 	}
 
-	sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.AssignedPodDelete, nil)
+	sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.AssignedPodDelete, pod, nil, nil)
 }
 
 // assignedPod selects pods that are assigned (scheduled and running).
@@ -249,9 +353,10 @@ func assignedPod(pod *v1.Pod) bool {
 	return len(pod.Spec.NodeName) != 0
 }
 
-// responsibleForPod returns true if the pod has asked to be scheduled by the given scheduler.
-func responsibleForPod(pod *v1.Pod, profiles profile.Map) bool {
-	return profiles.HandlesSchedulerName(pod.Spec.SchedulerName)
+// responsibleForPod returns true if the pod has asked to be scheduled by one of
+// the profiles of the scheduler, or is routed to one of them.
+func (sched *Scheduler) responsibleForPod(pod *v1.Pod) bool {
+	return sched.profileMap().HandlesSchedulerName(sched.profileNameForPod(pod))
 }
 
 // addAllEventHandlers is a helper function used in tests and in Scheduler
@@ -295,12 +400,12 @@ func addAllEventHandlers(
 			FilterFunc: func(obj interface{}) bool {
 				switch t := obj.(type) {
 				case *v1.Pod:
-					return !assignedPod(t) && responsibleForPod(t, sched.Profiles)
+					return !assignedPod(t) && sched.responsibleForPod(t)
 				case cache.DeletedFinalStateUnknown:
 					if pod, ok := t.Obj.(*v1.Pod); ok {
 						// The carried object may be stale, so we don't use it to check if
 						// it's assigned or not.
-						return responsibleForPod(pod, sched.Profiles)
+						return sched.responsibleForPod(pod)
 					}
 					utilruntime.HandleError(fmt.Errorf("unable to convert object %T to *v1.Pod in %T", obj, sched))
 					return false
@@ -329,20 +434,20 @@ func addAllEventHandlers(
 		funcs := cache.ResourceEventHandlerFuncs{}
 		if at&framework.Add != 0 {
 			evt := framework.ClusterEvent{Resource: gvk, ActionType: framework.Add, Label: fmt.Sprintf("%vAdd", shortGVK)}
-			funcs.AddFunc = func(_ interface{}) {
-				sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(evt, nil)
+			funcs.AddFunc = func(obj interface{}) {
+				sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(evt, nil, obj, nil)
 			}
 		}
 		if at&framework.Update != 0 {
 			evt := framework.ClusterEvent{Resource: gvk, ActionType: framework.Update, Label: fmt.Sprintf("%vUpdate", shortGVK)}
-			funcs.UpdateFunc = func(_, _ interface{}) {
-				sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(evt, nil)
+			funcs.UpdateFunc = func(oldObj, newObj interface{}) {
+				sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(evt, oldObj, newObj, nil)
 			}
 		}
 		if at&framework.Delete != 0 {
 			evt := framework.ClusterEvent{Resource: gvk, ActionType: framework.Delete, Label: fmt.Sprintf("%vDelete", shortGVK)}
-			funcs.DeleteFunc = func(_ interface{}) {
-				sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(evt, nil)
+			funcs.DeleteFunc = func(obj interface{}) {
+				sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(evt, obj, nil, nil)
 			}
 		}
 		return funcs
@@ -352,6 +457,10 @@ func addAllEventHandlers(
 		switch gvk {
 		case framework.Node, framework.Pod:
 			// Do nothing.
+		case framework.NodeUtilization, framework.NetworkLink, framework.ElasticQuota:
+			// There is no informer for these synthetic resources: their events
+			// are raised by the node telemetry through ReportNodeUtilization
+			// and ReportNodeLink, or reported by plugins.
 		case framework.CSINode:
 			informerFactory.Storage().V1().CSINodes().Informer().AddEventHandler(
 				buildEvtResHandler(at, framework.CSINode, "CSINode"),
@@ -397,8 +506,8 @@ func addAllEventHandlers(
 			if at&framework.Update != 0 {
 				informerFactory.Storage().V1().StorageClasses().Informer().AddEventHandler(
 					cache.ResourceEventHandlerFuncs{
-						UpdateFunc: func(_, _ interface{}) {
-							sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.StorageClassUpdate, nil)
+						UpdateFunc: func(oldObj, newObj interface{}) {
+							sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(queue.StorageClassUpdate, oldObj, newObj, nil)
 						},
 					},
 				)
@@ -31,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	dyfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/informers"
@@ -235,6 +236,142 @@ func TestUpdatePodInCache(t *testing.T) {
 	}
 }
 
+func TestReportNodeUtilization(t *testing.T) {
+	type report struct {
+		node        string
+		utilization float64
+	}
+	tests := []struct {
+		name      string
+		reports   []report
+		wantMoved []string
+	}{
+		{
+			name:    "first report below the threshold",
+			reports: []report{{"n1", 0.5}},
+		},
+		{
+			name:    "node stays above the threshold",
+			reports: []report{{"n1", 0.9}, {"n1", 0.8}},
+		},
+		{
+			name:      "node drops below the threshold",
+			reports:   []report{{"n1", 0.9}, {"n1", 0.5}},
+			wantMoved: []string{"load-aware"},
+		},
+		{
+			name:    "other node drops below the threshold",
+			reports: []report{{"n1", 0.9}, {"n2", 0.5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			less := func(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+				return pInfo1.Timestamp.Before(pInfo2.Timestamp)
+			}
+			q := queue.NewTestQueue(ctx, less, queue.WithClusterEventMap(map[framework.ClusterEvent]sets.String{
+				{Resource: framework.NodeUtilization, ActionType: framework.Update}: sets.NewString("LoadAware"),
+				{Resource: framework.Node, ActionType: framework.Add}:               sets.NewString("NodeResourcesFit"),
+			}))
+			for name, plugin := range map[string]string{"load-aware": "LoadAware", "fit": "NodeResourcesFit"} {
+				q.Add(st.MakePod().Name(name).UID(name).Obj())
+				pInfo, err := q.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+				pInfo.UnschedulablePlugins = sets.NewString(plugin)
+				if err := q.AddUnschedulableIfNotPresent(pInfo, q.SchedulingCycle()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			sched := &Scheduler{
+				SchedulingQueue: q,
+				nodeUtilization: newNodeUtilizationTracker(DefaultNodeUtilizationThreshold),
+			}
+			for _, r := range tt.reports {
+				sched.ReportNodeUtilization(r.node, r.utilization)
+			}
+			var moved []string
+			for _, pInfo := range q.PendingPodInfos() {
+				if pInfo.Queue != queue.UnschedulableQName {
+					moved = append(moved, pInfo.Pod.Name)
+				}
+			}
+			if diff := cmp.Diff(tt.wantMoved, moved); diff != "" {
+				t.Errorf("Unexpected moved pods (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReportNodeLink(t *testing.T) {
+	type report struct {
+		from, to string
+		latency  time.Duration
+	}
+	tests := []struct {
+		name      string
+		reports   []report
+		wantMoved bool
+	}{
+		{
+			name:      "new link",
+			reports:   []report{{"n1", "n2", time.Millisecond}},
+			wantMoved: true,
+		},
+		{
+			name:    "higher latency",
+			reports: []report{{"n1", "n2", time.Millisecond}, {"n1", "n2", 2 * time.Millisecond}},
+		},
+		{
+			name:      "lower latency",
+			reports:   []report{{"n1", "n2", 2 * time.Millisecond}, {"n1", "n2", time.Millisecond}},
+			wantMoved: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			less := func(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+				return pInfo1.Timestamp.Before(pInfo2.Timestamp)
+			}
+			q := queue.NewTestQueue(ctx, less, queue.WithClusterEventMap(map[framework.ClusterEvent]sets.String{
+				{Resource: framework.NetworkLink, ActionType: framework.Update}: sets.NewString("NetworkTopology"),
+			}))
+			sched := &Scheduler{
+				SchedulingQueue: q,
+				networkTopology: framework.NewNetworkTopology(),
+			}
+			// Only the last report can move the pod.
+			for _, r := range tt.reports[:len(tt.reports)-1] {
+				sched.ReportNodeLink(r.from, r.to, r.latency, 0)
+			}
+			q.Add(st.MakePod().Name("p").UID("p").Obj())
+			pInfo, err := q.Pop()
+			if err != nil {
+				t.Fatal(err)
+			}
+			pInfo.UnschedulablePlugins = sets.NewString("NetworkTopology")
+			if err := q.AddUnschedulableIfNotPresent(pInfo, q.SchedulingCycle()); err != nil {
+				t.Fatal(err)
+			}
+			last := tt.reports[len(tt.reports)-1]
+			sched.ReportNodeLink(last.from, last.to, last.latency, 0)
+
+			moved := q.PendingPodInfos()[0].Queue != queue.UnschedulableQName
+			if moved != tt.wantMoved {
+				t.Errorf("Got moved %v, want %v", moved, tt.wantMoved)
+			}
+			if link, _ := sched.networkTopology.Link(last.to, last.from); link.Latency != last.latency {
+				t.Errorf("Got latency %v, want %v", link.Latency, last.latency)
+			}
+		})
+	}
+}
+
 func withPodName(pod *v1.Pod, name string) *v1.Pod {
 	pod.Name = name
 	return pod
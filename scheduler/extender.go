@@ -18,6 +18,7 @@ package scheduler
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -45,6 +46,9 @@ type HTTPExtender struct {
 	filterVerb       string
 	prioritizeVerb   string
 	bindVerb         string
+	reserveVerb      string
+	unreserveVerb    string
+	postBindVerb     string
 	weight           int64
 	client           *http.Client
 	nodeCacheCapable bool
@@ -52,7 +56,9 @@ type HTTPExtender struct {
 	ignorable        bool
 }
 
-func makeTransport(config *schedulerapi.Extender) (http.RoundTripper, error) {
+// makeTLSConfig returns the TLS configuration to connect to the extender, or
+// nil if TLS is not enabled.
+func makeTLSConfig(config *schedulerapi.Extender) (*tls.Config, error) {
 	var cfg restclient.Config
 	if config.TLSConfig != nil {
 		cfg.TLSClientConfig.Insecure = config.TLSConfig.Insecure
@@ -70,7 +76,11 @@ func makeTransport(config *schedulerapi.Extender) (http.RoundTripper, error) {
 			cfg.Insecure = true
 		}
 	}
-	tlsConfig, err := restclient.TLSConfigFor(&cfg)
+	return restclient.TLSConfigFor(&cfg)
+}
+
+func makeTransport(config *schedulerapi.Extender) (http.RoundTripper, error) {
+	tlsConfig, err := makeTLSConfig(config)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +116,9 @@ func NewHTTPExtender(config *schedulerapi.Extender) (framework.Extender, error)
 		filterVerb:       config.FilterVerb,
 		prioritizeVerb:   config.PrioritizeVerb,
 		bindVerb:         config.BindVerb,
+		reserveVerb:      config.ReserveVerb,
+		unreserveVerb:    config.UnreserveVerb,
+		postBindVerb:     config.PostBindVerb,
 		weight:           config.Weight,
 		client:           client,
 		nodeCacheCapable: config.NodeCacheCapable,
@@ -129,6 +142,9 @@ func Equal(e1, e2 *HTTPExtender) bool {
 	if e1.bindVerb != e2.bindVerb {
 		return false
 	}
+	if e1.reserveVerb != e2.reserveVerb || e1.unreserveVerb != e2.unreserveVerb || e1.postBindVerb != e2.postBindVerb {
+		return false
+	}
 	if e1.weight != e2.weight {
 		return false
 	}
@@ -196,7 +212,7 @@ func (h *HTTPExtender) ProcessPreemption(
 
 	// Extender will always return NodeNameToMetaVictims.
 	// So let's convert it to NodeNameToVictims by using <nodeInfos>.
-	newNodeNameToVictims, err := h.convertToNodeNameToVictims(result.NodeNameToMetaVictims, nodeInfos)
+	newNodeNameToVictims, err := convertToNodeNameToVictims(h.extenderURL, result.NodeNameToMetaVictims, nodeInfos)
 	if err != nil {
 		return nil, err
 	}
@@ -206,7 +222,8 @@ func (h *HTTPExtender) ProcessPreemption(
 
 // convertToNodeNameToVictims converts "nodeNameToMetaVictims" from object identifiers,
 // such as UIDs and names, to object pointers.
-func (h *HTTPExtender) convertToNodeNameToVictims(
+func convertToNodeNameToVictims(
+	extenderName string,
 	nodeNameToMetaVictims map[string]*extenderv1.MetaVictims,
 	nodeInfos framework.NodeInfoLister,
 ) (map[string]*extenderv1.Victims, error) {
@@ -220,7 +237,7 @@ func (h *HTTPExtender) convertToNodeNameToVictims(
 			Pods: []*v1.Pod{},
 		}
 		for _, metaPod := range metaVictims.Pods {
-			pod, err := h.convertPodUIDToPod(metaPod, nodeInfo)
+			pod, err := convertPodUIDToPod(extenderName, metaPod, nodeInfo)
 			if err != nil {
 				return nil, err
 			}
@@ -235,7 +252,8 @@ func (h *HTTPExtender) convertToNodeNameToVictims(
 // The v1.Pod object is restored by nodeInfo.Pods().
 // It returns an error if there's cache inconsistency between default scheduler
 // and extender, i.e. when the pod is not found in nodeInfo.Pods.
-func (h *HTTPExtender) convertPodUIDToPod(
+func convertPodUIDToPod(
+	extenderName string,
 	metaPod *extenderv1.MetaPod,
 	nodeInfo *framework.NodeInfo) (*v1.Pod, error) {
 	for _, p := range nodeInfo.Pods {
@@ -244,7 +262,7 @@ func (h *HTTPExtender) convertPodUIDToPod(
 		}
 	}
 	return nil, fmt.Errorf("extender: %v claims to preempt pod (UID: %v) on node: %v, but the pod is not found on that node",
-		h.extenderURL, metaPod, nodeInfo.Node().Name)
+		extenderName, metaPod, nodeInfo.Node().Name)
 }
 
 // convertToNodeNameToMetaVictims converts from struct type to meta types.
@@ -410,6 +428,44 @@ func (h *HTTPExtender) IsBinder() bool {
 	return h.bindVerb != ""
 }
 
+// Reserve tells the extender that the pod is reserved on the node.
+func (h *HTTPExtender) Reserve(pod *v1.Pod, nodeName string) error {
+	return h.sendPlacement(h.reserveVerb, pod, nodeName)
+}
+
+// Unreserve tells the extender that the pod reserved on the node was
+// rejected from it or failed to bind to it.
+func (h *HTTPExtender) Unreserve(pod *v1.Pod, nodeName string) error {
+	return h.sendPlacement(h.unreserveVerb, pod, nodeName)
+}
+
+// PostBind tells the extender that the pod is bound to the node.
+func (h *HTTPExtender) PostBind(pod *v1.Pod, nodeName string) error {
+	return h.sendPlacement(h.postBindVerb, pod, nodeName)
+}
+
+// sendPlacement sends the placement of the pod on the node to the extender
+// with the arguments of the bind call, if the verb is defined.
+func (h *HTTPExtender) sendPlacement(verb string, pod *v1.Pod, nodeName string) error {
+	if verb == "" {
+		return nil
+	}
+	var result extenderv1.ExtenderBindingResult
+	args := &extenderv1.ExtenderBindingArgs{
+		PodName:      pod.Name,
+		PodNamespace: pod.Namespace,
+		PodUID:       pod.UID,
+		Node:         nodeName,
+	}
+	if err := h.send(verb, args, &result); err != nil {
+		return err
+	}
+	if result.Error != "" {
+		return fmt.Errorf(result.Error)
+	}
+	return nil
+}
+
 // Helper function to send messages to the extender
 func (h *HTTPExtender) send(action string, args interface{}, result interface{}) error {
 	out, err := json.Marshal(args)
@@ -442,28 +498,34 @@ func (h *HTTPExtender) send(action string, args interface{}, result interface{})
 // IsInterested returns true if at least one extended resource requested by
 // this pod is managed by this extender.
 func (h *HTTPExtender) IsInterested(pod *v1.Pod) bool {
-	if h.managedResources.Len() == 0 {
+	return isInterested(h.managedResources, pod)
+}
+
+// isInterested returns true if the pod requests at least one of the managed
+// resources, or if there are none.
+func isInterested(managedResources sets.String, pod *v1.Pod) bool {
+	if managedResources.Len() == 0 {
 		return true
 	}
-	if h.hasManagedResources(pod.Spec.Containers) {
+	if hasManagedResources(managedResources, pod.Spec.Containers) {
 		return true
 	}
-	if h.hasManagedResources(pod.Spec.InitContainers) {
+	if hasManagedResources(managedResources, pod.Spec.InitContainers) {
 		return true
 	}
 	return false
 }
 
-func (h *HTTPExtender) hasManagedResources(containers []v1.Container) bool {
+func hasManagedResources(managedResources sets.String, containers []v1.Container) bool {
 	for i := range containers {
 		container := &containers[i]
 		for resourceName := range container.Resources.Requests {
-			if h.managedResources.Has(string(resourceName)) {
+			if managedResources.Has(string(resourceName)) {
 				return true
 			}
 		}
 		for resourceName := range container.Resources.Limits {
-			if h.managedResources.Has(string(resourceName)) {
+			if managedResources.Has(string(resourceName)) {
 				return true
 			}
 		}
@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// maxCachedExtenderResults bounds the number of results a cachingExtender
+// keeps, evicting the least recently used ones beyond it.
+const maxCachedExtenderResults = 1024
+
+// cachingExtender caches the results of the Filter and Prioritize calls of an
+// extender for pods created from the same template, so that bursts of
+// identical pods, such as the replicas of a ReplicaSet, call the extender once.
+//
+// Results are keyed by the namespace and pod-template-hash label of the pod,
+// and by the generation of the set of nodes, made of the names of the nodes
+// given to the call and the generations of their NodeInfo in the snapshot, so
+// that the pods placed on a node invalidate the results too. Pods without the
+// label are never cached, nor are failed calls or calls with nodes missing
+// from the snapshot.
+type cachingExtender struct {
+	framework.Extender
+	ttl       time.Duration
+	cache     *cache.LRUExpireCache
+	nodeInfos framework.NodeInfoLister
+}
+
+var _ framework.ReserveExtender = &cachingExtender{}
+
+// newCachingExtender wraps the extender with a cache of its results expiring
+// after ttl. nodeInfos lists the nodes of the snapshot the extender is called
+// with.
+func newCachingExtender(extender framework.Extender, ttl time.Duration, clock cache.Clock, nodeInfos framework.NodeInfoLister) *cachingExtender {
+	return &cachingExtender{
+		Extender:  extender,
+		ttl:       ttl,
+		cache:     cache.NewLRUExpireCacheWithClock(maxCachedExtenderResults, clock),
+		nodeInfos: nodeInfos,
+	}
+}
+
+type filterCacheKey struct {
+	namespace, podTemplateHash string
+	nodeSetGeneration          uint64
+}
+
+type prioritizeCacheKey filterCacheKey
+
+type filterCacheEntry struct {
+	// feasibleNodes are the names of the nodes that passed the extender.
+	feasibleNodes                           []string
+	failedNodes, failedAndUnresolvableNodes extenderv1.FailedNodesMap
+}
+
+type prioritizeCacheEntry struct {
+	hostPriorities extenderv1.HostPriorityList
+	weight         int64
+}
+
+// cacheKey returns the key of the results of the call for the pod and the
+// nodes, or false if the pod has no template hash or a node is missing from the
+// snapshot.
+func (c *cachingExtender) cacheKey(pod *v1.Pod, nodes []*v1.Node) (filterCacheKey, bool) {
+	hash, ok := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	if !ok {
+		return filterCacheKey{}, false
+	}
+	h := fnv.New64a()
+	for _, n := range nodes {
+		nodeInfo, err := c.nodeInfos.Get(n.Name)
+		if err != nil {
+			return filterCacheKey{}, false
+		}
+		fmt.Fprintf(h, "%s/%d,", n.Name, nodeInfo.Generation)
+	}
+	return filterCacheKey{namespace: pod.Namespace, podTemplateHash: hash, nodeSetGeneration: h.Sum64()}, true
+}
+
+// Filter returns the cached result of the extender for the pod and the nodes
+// if any, or calls the extender and caches its result.
+func (c *cachingExtender) Filter(pod *v1.Pod, nodes []*v1.Node) ([]*v1.Node, extenderv1.FailedNodesMap, extenderv1.FailedNodesMap, error) {
+	key, ok := c.cacheKey(pod, nodes)
+	if !ok {
+		return c.Extender.Filter(pod, nodes)
+	}
+	if v, ok := c.cache.Get(key); ok {
+		entry := v.(*filterCacheEntry)
+		fromNodeName := make(map[string]*v1.Node, len(nodes))
+		for _, n := range nodes {
+			fromNodeName[n.Name] = n
+		}
+		feasibleNodes := make([]*v1.Node, 0, len(entry.feasibleNodes))
+		for _, name := range entry.feasibleNodes {
+			feasibleNodes = append(feasibleNodes, fromNodeName[name])
+		}
+		klog.V(5).InfoS("Using cached extender filter result", "extender", c.Name(), "pod", klog.KObj(pod))
+		return feasibleNodes, copyFailedNodesMap(entry.failedNodes), copyFailedNodesMap(entry.failedAndUnresolvableNodes), nil
+	}
+
+	feasibleNodes, failedNodes, failedAndUnresolvableNodes, err := c.Extender.Filter(pod, nodes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	entry := &filterCacheEntry{
+		feasibleNodes:              make([]string, 0, len(feasibleNodes)),
+		failedNodes:                copyFailedNodesMap(failedNodes),
+		failedAndUnresolvableNodes: copyFailedNodesMap(failedAndUnresolvableNodes),
+	}
+	names := sets.NewString()
+	for _, n := range nodes {
+		names.Insert(n.Name)
+	}
+	for _, n := range feasibleNodes {
+		if !names.Has(n.Name) {
+			// The result can't be restored from the nodes of later calls.
+			return feasibleNodes, failedNodes, failedAndUnresolvableNodes, nil
+		}
+		entry.feasibleNodes = append(entry.feasibleNodes, n.Name)
+	}
+	c.cache.Add(key, entry, c.ttl)
+	return feasibleNodes, failedNodes, failedAndUnresolvableNodes, nil
+}
+
+// Prioritize returns the cached result of the extender for the pod and the
+// nodes if any, or calls the extender and caches its result.
+func (c *cachingExtender) Prioritize(pod *v1.Pod, nodes []*v1.Node) (*extenderv1.HostPriorityList, int64, error) {
+	k, ok := c.cacheKey(pod, nodes)
+	if !ok {
+		return c.Extender.Prioritize(pod, nodes)
+	}
+	key := prioritizeCacheKey(k)
+	if v, ok := c.cache.Get(key); ok {
+		entry := v.(*prioritizeCacheEntry)
+		hostPriorities := append(extenderv1.HostPriorityList(nil), entry.hostPriorities...)
+		klog.V(5).InfoS("Using cached extender prioritize result", "extender", c.Name(), "pod", klog.KObj(pod))
+		return &hostPriorities, entry.weight, nil
+	}
+
+	hostPriorities, weight, err := c.Extender.Prioritize(pod, nodes)
+	if err != nil {
+		return nil, 0, err
+	}
+	c.cache.Add(key, &prioritizeCacheEntry{
+		hostPriorities: append(extenderv1.HostPriorityList(nil), *hostPriorities...),
+		weight:         weight,
+	}, c.ttl)
+	return hostPriorities, weight, nil
+}
+
+// Reserve calls the extender if it is a ReserveExtender.
+func (c *cachingExtender) Reserve(pod *v1.Pod, nodeName string) error {
+	if e, ok := c.Extender.(framework.ReserveExtender); ok {
+		return e.Reserve(pod, nodeName)
+	}
+	return nil
+}
+
+// Unreserve calls the extender if it is a ReserveExtender.
+func (c *cachingExtender) Unreserve(pod *v1.Pod, nodeName string) error {
+	if e, ok := c.Extender.(framework.ReserveExtender); ok {
+		return e.Unreserve(pod, nodeName)
+	}
+	return nil
+}
+
+// PostBind calls the extender if it is a ReserveExtender.
+func (c *cachingExtender) PostBind(pod *v1.Pod, nodeName string) error {
+	if e, ok := c.Extender.(framework.ReserveExtender); ok {
+		return e.PostBind(pod, nodeName)
+	}
+	return nil
+}
+
+func copyFailedNodesMap(m extenderv1.FailedNodesMap) extenderv1.FailedNodesMap {
+	if m == nil {
+		return nil
+	}
+	c := make(extenderv1.FailedNodesMap, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+// countingExtender counts the Filter and Prioritize calls of a FakeExtender.
+type countingExtender struct {
+	st.FakeExtender
+	calls int
+}
+
+func (c *countingExtender) Filter(pod *v1.Pod, nodes []*v1.Node) ([]*v1.Node, extenderv1.FailedNodesMap, extenderv1.FailedNodesMap, error) {
+	c.calls++
+	return c.FakeExtender.Filter(pod, nodes)
+}
+
+func (c *countingExtender) Prioritize(pod *v1.Pod, nodes []*v1.Node) (*extenderv1.HostPriorityList, int64, error) {
+	c.calls++
+	return c.FakeExtender.Prioritize(pod, nodes)
+}
+
+func TestCachingExtender(t *testing.T) {
+	nodes := []*v1.Node{createNode("node1"), createNode("node2")}
+	replica := func(name, hash string) *v1.Pod {
+		p := st.MakePod().Namespace("ns").Name(name).Obj()
+		if hash != "" {
+			p.Labels = map[string]string{"pod-template-hash": hash}
+		}
+		return p
+	}
+	tests := []struct {
+		name  string
+		pod   *v1.Pod
+		nodes []*v1.Node
+		// wait is how long the clock advances before the call.
+		wait time.Duration
+		// placed is a pod placed on a node of the snapshot before the call.
+		placed    *v1.Pod
+		wantCalls int
+	}{
+		{
+			name:      "pod of the same template",
+			pod:       replica("p2", "abc"),
+			nodes:     nodes,
+			wantCalls: 2,
+		},
+		{
+			name:      "pod of another template",
+			pod:       replica("p2", "def"),
+			nodes:     nodes,
+			wantCalls: 4,
+		},
+		{
+			name:      "pod without template",
+			pod:       replica("p2", ""),
+			nodes:     nodes,
+			wantCalls: 4,
+		},
+		{
+			name:      "pod placed on a node",
+			pod:       replica("p2", "abc"),
+			nodes:     nodes,
+			placed:    st.MakePod().Namespace("ns").Name("p0").Node("node2").Obj(),
+			wantCalls: 4,
+		},
+		{
+			name:      "expired result",
+			pod:       replica("p2", "abc"),
+			nodes:     nodes,
+			wait:      time.Minute,
+			wantCalls: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &countingExtender{FakeExtender: st.FakeExtender{
+				Predicates:   []st.FitPredicate{st.Node1PredicateExtender},
+				Prioritizers: []st.PriorityConfig{{Function: st.Node1PrioritizerExtender, Weight: 1}},
+				Weight:       1,
+			}}
+			clock := testingclock.NewFakeClock(time.Now())
+			snapshot := internalcache.NewSnapshot(nil, nodes)
+			extender := newCachingExtender(fake, 30*time.Second, clock, snapshot.NodeInfos())
+
+			first := replica("p1", "abc")
+			wantFiltered, wantFailed, _, err := extender.Filter(first, nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantPriorities, _, err := extender.Prioritize(first, nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			clock.Step(tt.wait)
+			if tt.placed != nil {
+				if err := snapshot.AssumePod(tt.placed); err != nil {
+					t.Fatal(err)
+				}
+			}
+			filtered, failed, _, err := extender.Filter(tt.pod, tt.nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			priorities, _, err := extender.Prioritize(tt.pod, tt.nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fake.calls != tt.wantCalls {
+				t.Errorf("Got %d extender calls, want %d", fake.calls, tt.wantCalls)
+			}
+			// The filtered nodes are the given ones, even when cached.
+			if len(filtered) != len(wantFiltered) || filtered[0] != tt.nodes[0] {
+				t.Errorf("Got filtered nodes %v, want %v", filtered, wantFiltered)
+			}
+			if diff := cmp.Diff(wantFailed, failed); diff != "" {
+				t.Errorf("Unexpected failed nodes (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(*wantPriorities, *priorities, cmp.Transformer("scores", hostScores)); diff != "" {
+				t.Errorf("Unexpected priorities (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func hostScores(l extenderv1.HostPriorityList) map[string]int64 {
+	m := make(map[string]int64, len(l))
+	for _, p := range l {
+		m[p.Host] = p.Score
+	}
+	return m
+}
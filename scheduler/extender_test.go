@@ -18,10 +18,15 @@ package scheduler
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
 	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
@@ -280,7 +286,7 @@ func TestGenericSchedulerWithExtenders(t *testing.T) {
 			scheduler := NewGenericScheduler(
 				cache,
 				emptySnapshot,
-				schedulerapi.DefaultPercentageOfNodesToScore)
+				schedulerapi.DefaultPercentageOfNodesToScore, false, 0)
 			podIgnored := &v1.Pod{}
 			result, err := scheduler.Schedule(context.Background(), extenders, fwk, framework.NewCycleState(), podIgnored)
 			if test.expectsErr {
@@ -388,3 +394,98 @@ func TestIsInterested(t *testing.T) {
 		})
 	}
 }
+
+func TestExtendersReserve(t *testing.T) {
+	tests := []struct {
+		name      string
+		extenders []schedulerapi.Extender
+		// failing are the calls the extenders answer with an error.
+		failing     sets.String
+		wantReserve bool
+		wantCalls   []string
+	}{
+		{
+			name: "reserve, unreserve and post bind",
+			extenders: []schedulerapi.Extender{
+				{URLPrefix: "a", ReserveVerb: "reserve", UnreserveVerb: "unreserve", PostBindVerb: "postbind"},
+				{URLPrefix: "b", UnreserveVerb: "unreserve"},
+				{URLPrefix: "c"},
+			},
+			wantReserve: true,
+			wantCalls:   []string{"/a/reserve", "/b/unreserve", "/a/unreserve", "/a/postbind"},
+		},
+		{
+			name: "failing extender rejects the pod",
+			extenders: []schedulerapi.Extender{
+				{URLPrefix: "a", ReserveVerb: "reserve"},
+				{URLPrefix: "b", ReserveVerb: "reserve"},
+				{URLPrefix: "c", ReserveVerb: "reserve"},
+			},
+			failing:   sets.NewString("/b/reserve"),
+			wantCalls: []string{"/a/reserve", "/b/reserve"},
+		},
+		{
+			name: "failing ignorable extender is skipped",
+			extenders: []schedulerapi.Extender{
+				{URLPrefix: "a", ReserveVerb: "reserve", Ignorable: true},
+				{URLPrefix: "b", ReserveVerb: "reserve", PostBindVerb: "postbind", Ignorable: true},
+			},
+			failing:     sets.NewString("/a/reserve", "/b/postbind"),
+			wantReserve: true,
+			wantCalls:   []string{"/a/reserve", "/b/reserve", "/b/postbind"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var args extenderv1.ExtenderBindingArgs
+				if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+					t.Errorf("Failed to decode arguments of %s: %v", r.URL.Path, err)
+				}
+				if args.PodName != "p" || args.PodUID != "p" || args.Node != "node1" {
+					t.Errorf("Unexpected arguments of %s: %+v", r.URL.Path, args)
+				}
+				calls = append(calls, r.URL.Path)
+				var result extenderv1.ExtenderBindingResult
+				if tt.failing.Has(r.URL.Path) {
+					result.Error = "injected failure"
+				}
+				json.NewEncoder(w).Encode(&result)
+			}))
+			defer server.Close()
+
+			sched := &Scheduler{}
+			for i := range tt.extenders {
+				tt.extenders[i].URLPrefix = server.URL + "/" + tt.extenders[i].URLPrefix
+				extender, err := NewHTTPExtender(&tt.extenders[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				sched.Extenders = append(sched.Extenders, extender)
+			}
+			fwk, err := st.NewFramework(
+				[]st.RegisterPluginFunc{
+					st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+					st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				}, "",
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pod := st.MakePod().Name("p").UID("p").Obj()
+			sts := sched.extendersReserve(pod, "node1")
+			if sts.IsSuccess() != tt.wantReserve {
+				t.Errorf("Got reserve status %v, want success %v", sts, tt.wantReserve)
+			}
+			if sts.IsSuccess() {
+				sched.unreserve(context.Background(), fwk, framework.NewCycleState(), pod, "node1")
+				sched.extendersPostBind(pod, "node1")
+			}
+			if diff := cmp.Diff(tt.wantCalls, calls); diff != "" {
+				t.Errorf("Unexpected extender calls (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
@@ -39,6 +40,8 @@ import (
 	cachedebugger "k8s.io/kubernetes/pkg/scheduler/internal/cache/debugger"
 	internalqueue "k8s.io/kubernetes/pkg/scheduler/internal/queue"
 	"k8s.io/kubernetes/pkg/scheduler/profile"
+	"k8s.io/kubernetes/pkg/scheduler/util"
+	"k8s.io/utils/clock"
 )
 
 // Binder knows how to write a binding.
@@ -73,6 +76,9 @@ type Configurator struct {
 
 	podMaxBackoffSeconds int64
 
+	// backoffPolicy configures the backoff of unschedulable pods.
+	backoffPolicy *schedulerapi.BackoffPolicy
+
 	profiles          []schedulerapi.KubeSchedulerProfile
 	registry          frameworkruntime.Registry
 	nodeInfoSnapshot  *internalcache.Snapshot
@@ -81,6 +87,25 @@ type Configurator struct {
 	parallellism      int32
 	// A "cluster event" -> "plugin names" map.
 	clusterEventMap map[framework.ClusterEvent]sets.String
+	// randomSeed, if set, makes scheduling decisions reproducible.
+	randomSeed *int64
+	// maxFallbackHosts is the number of ranked nodes a pod can fall back to.
+	maxFallbackHosts int32
+	// batchSize is the maximum number of pods placed in a scheduling cycle.
+	batchSize int32
+	// popPolicy, if set, picks which of the popWindow pods at the head of the
+	// queue is scheduled next, skipping none of them for more than maxPopSkip.
+	popPolicy  framework.PopPolicy
+	popWindow  int32
+	maxPopSkip time.Duration
+	// profileRoutes and defaultProfile assign a profile to the pods that don't
+	// ask for one.
+	profileRoutes  []schedulerapi.ProfileRoute
+	defaultProfile string
+	// hostSelectors and scoreWeightTunings configure the profiles, by profile
+	// name.
+	hostSelectors      map[string]*schedulerapi.HostSelector
+	scoreWeightTunings map[string]*schedulerapi.ScoreWeightTuning
 }
 
 // create a scheduler from a set of registered plugins.
@@ -91,10 +116,17 @@ func (c *Configurator) create() (*Scheduler, error) {
 		var ignorableExtenders []framework.Extender
 		for ii := range c.extenders {
 			klog.V(2).InfoS("Creating extender", "extender", c.extenders[ii])
-			extender, err := NewHTTPExtender(&c.extenders[ii])
+			newExtender := NewHTTPExtender
+			if c.extenders[ii].Transport == schedulerapi.ExtenderTransportGRPC {
+				newExtender = NewGRPCExtender
+			}
+			extender, err := newExtender(&c.extenders[ii])
 			if err != nil {
 				return nil, err
 			}
+			if ttl := c.extenders[ii].ResultCacheTTL.Duration; ttl > 0 {
+				extender = newCachingExtender(extender, ttl, clock.RealClock{}, c.nodeInfoSnapshot.NodeInfos())
+			}
 			if !extender.IsIgnorable() {
 				extenders = append(extenders, extender)
 			} else {
@@ -110,37 +142,33 @@ func (c *Configurator) create() (*Scheduler, error) {
 		extenders = append(extenders, ignorableExtenders...)
 	}
 
-	// If there are any extended resources found from the Extenders, append them to the pluginConfig for each profile.
-	// This should only have an effect on ComponentConfig, where it is possible to configure Extenders and
-	// plugin args (and in which case the extender ignored resources take precedence).
-	// For earlier versions, using both policy and custom plugin config is disallowed, so this should be the only
-	// plugin config for this plugin.
-	if len(ignoredExtendedResources) > 0 {
-		for i := range c.profiles {
-			prof := &c.profiles[i]
-			var found = false
-			for k := range prof.PluginConfig {
-				if prof.PluginConfig[k].Name == noderesources.FitName {
-					// Update the existing args
-					pc := &prof.PluginConfig[k]
-					args, ok := pc.Args.(*schedulerapi.NodeResourcesFitArgs)
-					if !ok {
-						return nil, fmt.Errorf("want args to be of type NodeResourcesFitArgs, got %T", pc.Args)
-					}
-					args.IgnoredResources = ignoredExtendedResources
-					found = true
-					break
-				}
-			}
-			if !found {
-				return nil, fmt.Errorf("can't find NodeResourcesFitArgs in plugin config")
-			}
-		}
+	if err := setIgnoredExtendedResources(c.profiles, ignoredExtendedResources); err != nil {
+		return nil, err
+	}
+
+	// All profiles share one source of randomness, so that a seeded scheduler
+	// makes the same decisions regardless of which profiles pods are routed to.
+	seed := time.Now().UnixNano()
+	if c.randomSeed != nil {
+		seed = *c.randomSeed
 	}
+	rnd := util.NewRand(seed)
+	// All profiles share the links between nodes reported by the telemetry.
+	networkTopology := framework.NewNetworkTopology()
 
 	// The nominator will be passed all the way to framework instantiation.
 	nominator := internalqueue.NewPodNominator(c.informerFactory.Core().V1().Pods().Lister())
-	profiles, err := profile.NewMap(c.profiles, c.registry, c.recorderFactory,
+	// A "cluster event" -> "plugin name" -> "queueing hint" map.
+	queueingHintMap := make(map[framework.ClusterEvent]map[string]framework.QueueingHintFn)
+	// The events reported by plugins move pods in the scheduling queue, which
+	// is created once the profiles are.
+	var podQueue internalqueue.SchedulingQueue
+	reportClusterEvent := func(evt framework.ClusterEvent) {
+		if podQueue != nil {
+			podQueue.MoveAllToActiveOrBackoffQueue(evt, nil, nil, nil)
+		}
+	}
+	profileOpts := []frameworkruntime.Option{
 		frameworkruntime.WithComponentConfigVersion(c.componentConfigVersion),
 		frameworkruntime.WithClientSet(c.client),
 		frameworkruntime.WithKubeConfig(c.kubeConfig),
@@ -150,24 +178,72 @@ func (c *Configurator) create() (*Scheduler, error) {
 		frameworkruntime.WithPodNominator(nominator),
 		frameworkruntime.WithCaptureProfile(frameworkruntime.CaptureProfile(c.frameworkCapturer)),
 		frameworkruntime.WithClusterEventMap(c.clusterEventMap),
+		frameworkruntime.WithQueueingHintMap(queueingHintMap),
 		frameworkruntime.WithParallelism(int(c.parallellism)),
 		frameworkruntime.WithExtenders(extenders),
-	)
+		frameworkruntime.WithRand(rnd),
+		frameworkruntime.WithNetworkTopology(networkTopology),
+		frameworkruntime.WithClusterEventHandler(reportClusterEvent),
+		frameworkruntime.WithHostSelectors(c.hostSelectors),
+	}
+	profiles, err := profile.NewMap(c.profiles, c.registry, c.recorderFactory, profileOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("initializing profiles: %v", err)
 	}
 	if len(profiles) == 0 {
 		return nil, errors.New("at least one profile is required")
 	}
+	router, err := profile.NewRouter(c.profileRoutes, c.defaultProfile)
+	if err != nil {
+		return nil, fmt.Errorf("initializing profile routes: %v", err)
+	}
+	for _, r := range c.profileRoutes {
+		if !profiles.HandlesSchedulerName(r.SchedulerName) {
+			return nil, fmt.Errorf("profile route to unknown profile %q", r.SchedulerName)
+		}
+	}
+	if len(c.defaultProfile) != 0 && !profiles.HandlesSchedulerName(c.defaultProfile) {
+		return nil, fmt.Errorf("unknown default profile %q", c.defaultProfile)
+	}
+	profileName := func(pod *v1.Pod) string {
+		return pod.Spec.SchedulerName
+	}
+	if router != nil {
+		nsLister := c.informerFactory.Core().V1().Namespaces().Lister()
+		namespaceLabels := func(namespace string) labels.Set {
+			ns, err := nsLister.Get(namespace)
+			if err != nil {
+				return nil
+			}
+			return ns.Labels
+		}
+		profileName = func(pod *v1.Pod) string {
+			return router.ProfileName(pod, namespaceLabels)
+		}
+	}
+
 	// Profiles are required to have equivalent queue sort plugins.
 	lessFn := profiles[c.profiles[0].SchedulerName].QueueSortFunc()
-	podQueue := internalqueue.NewSchedulingQueue(
+	preEnqueuePluginMap := make(map[string][]framework.PreEnqueuePlugin)
+	for profileName, fwk := range profiles {
+		preEnqueuePluginMap[profileName] = fwk.PreEnqueuePlugins()
+	}
+	podInitialBackoff := time.Duration(c.podInitialBackoffSeconds) * time.Second
+	podMaxBackoff := time.Duration(c.podMaxBackoffSeconds) * time.Second
+	podQueue = internalqueue.NewSchedulingQueue(
 		lessFn,
 		c.informerFactory,
-		internalqueue.WithPodInitialBackoffDuration(time.Duration(c.podInitialBackoffSeconds)*time.Second),
-		internalqueue.WithPodMaxBackoffDuration(time.Duration(c.podMaxBackoffSeconds)*time.Second),
+		internalqueue.WithPodInitialBackoffDuration(podInitialBackoff),
+		internalqueue.WithPodMaxBackoffDuration(podMaxBackoff),
+		internalqueue.WithBackoffPolicy(internalqueue.NewBackoffPolicy(c.backoffPolicy, podInitialBackoff, podMaxBackoff)),
 		internalqueue.WithPodNominator(nominator),
 		internalqueue.WithClusterEventMap(c.clusterEventMap),
+		internalqueue.WithQueueingHintMap(queueingHintMap),
+		internalqueue.WithPreEnqueuePluginMap(preEnqueuePluginMap),
+		internalqueue.WithProfileNameFunc(profileName),
+		internalqueue.WithPopPolicy(c.popPolicy, c.popPolicyNodeInfos),
+		internalqueue.WithPopWindow(int(c.popWindow)),
+		internalqueue.WithMaxPopSkip(c.maxPopSkip),
 	)
 
 	// Setup cache debugger.
@@ -183,24 +259,122 @@ func (c *Configurator) create() (*Scheduler, error) {
 		c.schedulerCache,
 		c.nodeInfoSnapshot,
 		c.percentageOfNodesToScore,
+		c.randomSeed != nil,
+		int(c.maxFallbackHosts),
 	)
 
+	profileConfigs := make(map[string]schedulerapi.KubeSchedulerProfile, len(c.profiles))
+	for _, cfg := range c.profiles {
+		profileConfigs[cfg.SchedulerName] = cfg
+	}
+	// Reloaded profiles are built like the initial ones, except that the events
+	// and queueing hints their plugins register are dropped: the event handlers
+	// and the scheduling queue keep using the ones registered at startup.
+	newProfiles := func(cfgs []schedulerapi.KubeSchedulerProfile) (profile.Map, error) {
+		opts := append(append([]frameworkruntime.Option(nil), profileOpts...),
+			frameworkruntime.WithClusterEventMap(make(map[framework.ClusterEvent]sets.String)),
+			frameworkruntime.WithQueueingHintMap(make(map[framework.ClusterEvent]map[string]framework.QueueingHintFn)),
+		)
+		return profile.NewMap(cfgs, c.registry, c.recorderFactory, opts...)
+	}
+	// The frameworks of the preemption simulations run against a snapshot of
+	// their own, and draw from a random source of their own so that they don't
+	// change the sequence of a seeded scheduler. The events their plugins report
+	// are dropped.
+	newSimulationProfile := func(cfg schedulerapi.KubeSchedulerProfile, snapshot *internalcache.Snapshot) (framework.Framework, error) {
+		opts := append(append([]frameworkruntime.Option(nil), profileOpts...),
+			frameworkruntime.WithSnapshotSharedLister(snapshot),
+			frameworkruntime.WithRand(util.NewRand(time.Now().UnixNano())),
+			frameworkruntime.WithCaptureProfile(nil),
+			frameworkruntime.WithClusterEventHandler(nil),
+			frameworkruntime.WithClusterEventMap(make(map[framework.ClusterEvent]sets.String)),
+			frameworkruntime.WithQueueingHintMap(make(map[framework.ClusterEvent]map[string]framework.QueueingHintFn)),
+		)
+		profiles, err := profile.NewMap([]schedulerapi.KubeSchedulerProfile{cfg}, c.registry, c.recorderFactory, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return profiles[cfg.SchedulerName], nil
+	}
+
 	return &Scheduler{
-		SchedulerCache:  c.schedulerCache,
-		Algorithm:       algo,
-		Extenders:       extenders,
-		Profiles:        profiles,
-		NextPod:         internalqueue.MakeNextPodFunc(podQueue),
-		Error:           MakeDefaultErrorFunc(c.client, c.informerFactory.Core().V1().Pods().Lister(), podQueue, c.schedulerCache),
-		StopEverything:  c.StopEverything,
-		SchedulingQueue: podQueue,
+		SchedulerCache:           c.schedulerCache,
+		Algorithm:                algo,
+		Extenders:                extenders,
+		Profiles:                 profiles,
+		queueSortProfile:         c.profiles[0].SchedulerName,
+		profileRouter:            router,
+		profileName:              profileName,
+		scoreWeightTunings:       c.scoreWeightTunings,
+		NextPod:                  internalqueue.MakeNextPodFunc(podQueue),
+		Error:                    MakeDefaultErrorFunc(c.client, c.informerFactory.Core().V1().Pods().Lister(), podQueue, c.schedulerCache),
+		StopEverything:           c.StopEverything,
+		SchedulingQueue:          podQueue,
+		batchSize:                int(c.batchSize),
+		nodeInfoSnapshot:         c.nodeInfoSnapshot,
+		networkTopology:          networkTopology,
+		randomSeed:               seed,
+		profileConfigs:           profileConfigs,
+		newProfiles:              newProfiles,
+		simulationSnapshot:       internalcache.NewEmptySnapshot(),
+		newSimulationProfile:     newSimulationProfile,
+		ignoredExtendedResources: ignoredExtendedResources,
 	}, nil
 }
 
+// setIgnoredExtendedResources makes the NodeResourcesFit plugin of the profiles
+// ignore the extended resources managed by extenders that the scheduler must
+// not check.
+func setIgnoredExtendedResources(profiles []schedulerapi.KubeSchedulerProfile, resources []string) error {
+	// If there are any extended resources found from the Extenders, append them to the pluginConfig for each profile.
+	// This should only have an effect on ComponentConfig, where it is possible to configure Extenders and
+	// plugin args (and in which case the extender ignored resources take precedence).
+	// For earlier versions, using both policy and custom plugin config is disallowed, so this should be the only
+	// plugin config for this plugin.
+	if len(resources) == 0 {
+		return nil
+	}
+	for i := range profiles {
+		prof := &profiles[i]
+		var found = false
+		for k := range prof.PluginConfig {
+			if prof.PluginConfig[k].Name == noderesources.FitName {
+				// Update the existing args
+				pc := &prof.PluginConfig[k]
+				args, ok := pc.Args.(*schedulerapi.NodeResourcesFitArgs)
+				if !ok {
+					return fmt.Errorf("want args to be of type NodeResourcesFitArgs, got %T", pc.Args)
+				}
+				args.IgnoredResources = resources
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("can't find NodeResourcesFitArgs in plugin config")
+		}
+	}
+	return nil
+}
+
+// popPolicyNodeInfos returns a private snapshot of the cache for the pop
+// policy, which runs outside of the scheduling cycles and so can't read the
+// snapshot the profiles run against.
+func (c *Configurator) popPolicyNodeInfos() (framework.NodeInfoLister, error) {
+	snapshot := internalcache.NewEmptySnapshot()
+	if err := c.schedulerCache.UpdateSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot.NodeInfos(), nil
+}
+
 // MakeDefaultErrorFunc construct a function to handle pod scheduler error
 func MakeDefaultErrorFunc(client clientset.Interface, podLister corelisters.PodLister, podQueue internalqueue.SchedulingQueue, schedulerCache internalcache.Cache) func(*framework.QueuedPodInfo, error) {
 	return func(podInfo *framework.QueuedPodInfo, err error) {
 		pod := podInfo.Pod
+		if err != nil {
+			podInfo.LastFailureMessage = err.Error()
+		}
 		if err == ErrNoNodesAvailable {
 			klog.V(2).InfoS("Unable to schedule pod; no nodes are registered to the cluster; waiting", "pod", klog.KObj(pod))
 		} else if fitError, ok := err.(*framework.FitError); ok {
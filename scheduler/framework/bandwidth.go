@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ResourceIngressBandwidth is the extended resource of the ingress network
+	// bandwidth, in bytes per second. Pods requesting it in their containers
+	// rather than through annotations need nodes advertising it in their
+	// status, as the kubelet admits them against it.
+	ResourceIngressBandwidth v1.ResourceName = "scheduling.k8s.io/ingress-bandwidth"
+	// ResourceEgressBandwidth is the extended resource of the egress network
+	// bandwidth, in bytes per second.
+	ResourceEgressBandwidth v1.ResourceName = "scheduling.k8s.io/egress-bandwidth"
+
+	// IngressBandwidthKey is the key of the annotation of a pod requesting
+	// ingress bandwidth, and of the label of a node advertising its ingress
+	// bandwidth capacity. The value is a quantity of bytes per second.
+	IngressBandwidthKey = "scheduling.alpha.kubernetes.io/ingress-bandwidth"
+	// EgressBandwidthKey is the key of the annotation of a pod requesting
+	// egress bandwidth, and of the label of a node advertising its egress
+	// bandwidth capacity. The value is a quantity of bytes per second.
+	EgressBandwidthKey = "scheduling.alpha.kubernetes.io/egress-bandwidth"
+)
+
+// bandwidthKeys maps the bandwidth resources to the keys of the annotations
+// and labels holding them.
+var bandwidthKeys = map[v1.ResourceName]string{
+	ResourceIngressBandwidth: IngressBandwidthKey,
+	ResourceEgressBandwidth:  EgressBandwidthKey,
+}
+
+// PodBandwidthRequest returns the bandwidth resource requested by the pod
+// through an annotation, if any. Malformed and negative values are ignored.
+func PodBandwidthRequest(pod *v1.Pod, name v1.ResourceName) (int64, bool) {
+	key, ok := bandwidthKeys[name]
+	if !ok {
+		return 0, false
+	}
+	v, ok := pod.Annotations[key]
+	if !ok {
+		return 0, false
+	}
+	bandwidth, ok := parseBandwidth(v)
+	if !ok {
+		klog.V(5).InfoS("Ignoring malformed bandwidth request", "pod", klog.KObj(pod), "annotation", key, "value", v)
+	}
+	return bandwidth, ok
+}
+
+// AddPodBandwidthRequests adds the bandwidth requested by the pod through
+// annotations to its requests, unless its containers request the bandwidth
+// resources themselves.
+func AddPodBandwidthRequests(r *Resource, pod *v1.Pod) {
+	if len(pod.Annotations) == 0 {
+		return
+	}
+	for name := range bandwidthKeys {
+		if _, ok := r.ScalarResources[name]; ok {
+			continue
+		}
+		if bandwidth, ok := PodBandwidthRequest(pod, name); ok {
+			r.SetScalar(name, bandwidth)
+		}
+	}
+}
+
+// setNodeBandwidthCapacity sets the bandwidth advertised by the node through
+// labels as allocatable, unless its status advertises the bandwidth resources
+// itself.
+func setNodeBandwidthCapacity(r *Resource, node *v1.Node) {
+	if len(node.Labels) == 0 {
+		return
+	}
+	for name, key := range bandwidthKeys {
+		if _, ok := r.ScalarResources[name]; ok {
+			continue
+		}
+		v, ok := node.Labels[key]
+		if !ok {
+			continue
+		}
+		if bandwidth, ok := parseBandwidth(v); ok {
+			r.SetScalar(name, bandwidth)
+		} else {
+			klog.V(5).InfoS("Ignoring malformed bandwidth capacity", "node", klog.KObj(node), "label", key, "value", v)
+		}
+	}
+}
+
+func parseBandwidth(v string) (int64, bool) {
+	q, err := resource.ParseQuantity(v)
+	if err != nil || q.Sign() < 0 {
+		return 0, false
+	}
+	return q.Value(), true
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAddPodBandwidthRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		requests    v1.ResourceList
+		want        map[v1.ResourceName]int64
+	}{
+		{
+			name: "annotations",
+			annotations: map[string]string{
+				IngressBandwidthKey: "10M",
+				EgressBandwidthKey:  "1k",
+			},
+			want: map[v1.ResourceName]int64{
+				ResourceIngressBandwidth: 10 * 1000 * 1000,
+				ResourceEgressBandwidth:  1000,
+			},
+		},
+		{
+			name:        "container requests take precedence",
+			annotations: map[string]string{IngressBandwidthKey: "10M"},
+			requests:    v1.ResourceList{ResourceIngressBandwidth: resource.MustParse("1M")},
+			want:        map[v1.ResourceName]int64{ResourceIngressBandwidth: 1000 * 1000},
+		},
+		{
+			name: "malformed and negative values",
+			annotations: map[string]string{
+				IngressBandwidthKey: "fast",
+				EgressBandwidthKey:  "-1M",
+			},
+		},
+		{
+			name: "no annotations",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "p", Annotations: tt.annotations},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: tt.requests}}},
+				},
+			}
+			var r Resource
+			for _, c := range pod.Spec.Containers {
+				r.Add(c.Resources.Requests)
+			}
+			AddPodBandwidthRequests(&r, pod)
+			if diff := cmp.Diff(tt.want, r.ScalarResources); diff != "" {
+				t.Errorf("Unexpected requests (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNodeInfoBandwidth(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "n",
+			Labels: map[string]string{
+				IngressBandwidthKey: "10G",
+				EgressBandwidthKey:  "10G",
+			},
+		},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{ResourceEgressBandwidth: resource.MustParse("1G")},
+		},
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "p",
+			UID:         "p",
+			Annotations: map[string]string{IngressBandwidthKey: "2G"},
+		},
+		Spec: v1.PodSpec{NodeName: "n"},
+	}
+
+	ni := NewNodeInfo()
+	ni.SetNode(node)
+	// The node status takes precedence over the labels.
+	wantAllocatable := map[v1.ResourceName]int64{
+		ResourceIngressBandwidth: 10 * 1000 * 1000 * 1000,
+		ResourceEgressBandwidth:  1000 * 1000 * 1000,
+	}
+	if diff := cmp.Diff(wantAllocatable, ni.Allocatable.ScalarResources); diff != "" {
+		t.Errorf("Unexpected allocatable (-want,+got):\n%s", diff)
+	}
+
+	ni.AddPod(pod)
+	if got, want := ni.Requested.ScalarResources[ResourceIngressBandwidth], int64(2*1000*1000*1000); got != want {
+		t.Errorf("Got requested ingress bandwidth %d after adding the pod, want %d", got, want)
+	}
+	if err := ni.RemovePod(pod); err != nil {
+		t.Fatal(err)
+	}
+	if got := ni.Requested.ScalarResources[ResourceIngressBandwidth]; got != 0 {
+		t.Errorf("Got requested ingress bandwidth %d after removing the pod, want 0", got)
+	}
+}
@@ -46,6 +46,9 @@ type CycleState struct {
 	storage map[StateKey]StateData
 	// if recordPluginMetrics is true, PluginExecutionDuration will be recorded for this cycle.
 	recordPluginMetrics bool
+	// if dryRun is true, the cycle only simulates the scheduling of the pod and
+	// plugins must not have side effects outside of the cycle state.
+	dryRun bool
 }
 
 // NewCycleState initializes a new CycleState and returns its pointer.
@@ -71,6 +74,22 @@ func (c *CycleState) SetRecordPluginMetrics(flag bool) {
 	c.recordPluginMetrics = flag
 }
 
+// IsDryRun returns whether the cycle only simulates the scheduling of the pod.
+func (c *CycleState) IsDryRun() bool {
+	if c == nil {
+		return false
+	}
+	return c.dryRun
+}
+
+// SetDryRun sets dryRun to the given value.
+func (c *CycleState) SetDryRun(flag bool) {
+	if c == nil {
+		return
+	}
+	c.dryRun = flag
+}
+
 // Clone creates a copy of CycleState and returns its pointer. Clone returns
 // nil if the context being cloned is nil.
 func (c *CycleState) Clone() *CycleState {
@@ -78,6 +97,7 @@ func (c *CycleState) Clone() *CycleState {
 		return nil
 	}
 	copy := NewCycleState()
+	copy.dryRun = c.dryRun
 	for k, v := range c.storage {
 		copy.Write(k, v.Clone())
 	}
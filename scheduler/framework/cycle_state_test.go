@@ -72,3 +72,11 @@ func TestCycleStateCloneNil(t *testing.T) {
 		t.Errorf("clone expected to be nil")
 	}
 }
+
+func TestCycleStateCloneDryRun(t *testing.T) {
+	state := NewCycleState()
+	state.SetDryRun(true)
+	if !state.Clone().IsDryRun() {
+		t.Errorf("clone of a dry run expected to be a dry run")
+	}
+}
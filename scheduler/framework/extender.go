@@ -70,3 +70,21 @@ type Extender interface {
 	// is unavailable. This gives scheduler ability to fail fast and tolerate non-critical extenders as well.
 	IsIgnorable() bool
 }
+
+// ReserveExtender is an Extender that is told where the pods it is interested
+// in are reserved and bound, alongside the Reserve and PostBind plugins.
+type ReserveExtender interface {
+	Extender
+
+	// Reserve is called when the pod is reserved on the node. An error rejects
+	// the pod from the node, unless the extender is ignorable.
+	Reserve(pod *v1.Pod, nodeName string) error
+
+	// Unreserve is called when the pod reserved on the node is rejected from it
+	// or fails to bind to it. It may be called without Reserve being called
+	// first, and must be idempotent.
+	Unreserve(pod *v1.Pod, nodeName string) error
+
+	// PostBind is called after the pod is bound to the node.
+	PostBind(pod *v1.Pod, nodeName string) error
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostselector
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+var errEmptyPriorityList = errors.New("empty priorityList")
+
+// New returns the HostSelector described by the given configuration. A nil
+// configuration results in the MaxScore strategy.
+func New(cfg *config.HostSelector) (framework.HostSelector, error) {
+	if cfg == nil {
+		return &MaxScore{}, nil
+	}
+	switch cfg.Type {
+	case config.MaxScoreHostSelector:
+		return &MaxScore{}, nil
+	case config.SoftmaxHostSelector:
+		if cfg.Temperature <= 0 {
+			return nil, fmt.Errorf("softmax temperature must be greater than zero, got %v", cfg.Temperature)
+		}
+		return &Softmax{Temperature: cfg.Temperature}, nil
+	case config.TopKHostSelector:
+		if cfg.K <= 0 {
+			return nil, fmt.Errorf("top-k K must be greater than zero, got %d", cfg.K)
+		}
+		return &TopK{K: int(cfg.K)}, nil
+	case config.EpsilonGreedyHostSelector:
+		if cfg.Epsilon < 0 || cfg.Epsilon > 1 {
+			return nil, fmt.Errorf("epsilon-greedy epsilon must be in the range [0, 1], got %v", cfg.Epsilon)
+		}
+		return &EpsilonGreedy{Epsilon: cfg.Epsilon}, nil
+	}
+	return nil, fmt.Errorf("unknown host selector type %q", cfg.Type)
+}
+
+// MaxScore picks the node with the highest score, breaking ties uniformly at random.
+type MaxScore struct{}
+
+var _ framework.HostSelector = &MaxScore{}
+
+// Name returns the name of the strategy.
+func (s *MaxScore) Name() string {
+	return string(config.MaxScoreHostSelector)
+}
+
+// Select picks one node in a reservoir sampling manner from the nodes that had
+// the highest score.
+func (s *MaxScore) Select(r *rand.Rand, nodeScoreList framework.NodeScoreList) (string, error) {
+	if len(nodeScoreList) == 0 {
+		return "", errEmptyPriorityList
+	}
+	maxScore := nodeScoreList[0].Score
+	selected := nodeScoreList[0].Name
+	cntOfMaxScore := 1
+	for _, ns := range nodeScoreList[1:] {
+		if ns.Score > maxScore {
+			maxScore = ns.Score
+			selected = ns.Name
+			cntOfMaxScore = 1
+		} else if ns.Score == maxScore {
+			cntOfMaxScore++
+			if r.Intn(cntOfMaxScore) == 0 {
+				// Replace the candidate with probability of 1/cntOfMaxScore
+				selected = ns.Name
+			}
+		}
+	}
+	return selected, nil
+}
+
+// Softmax picks a node with a probability proportional to exp(score/Temperature).
+type Softmax struct {
+	Temperature float64
+}
+
+var _ framework.HostSelector = &Softmax{}
+
+// Name returns the name of the strategy.
+func (s *Softmax) Name() string {
+	return string(config.SoftmaxHostSelector)
+}
+
+// Select samples a node from the softmax distribution over the node scores.
+func (s *Softmax) Select(r *rand.Rand, nodeScoreList framework.NodeScoreList) (string, error) {
+	if len(nodeScoreList) == 0 {
+		return "", errEmptyPriorityList
+	}
+	maxScore := nodeScoreList[0].Score
+	for _, ns := range nodeScoreList[1:] {
+		if ns.Score > maxScore {
+			maxScore = ns.Score
+		}
+	}
+	// Shift the scores by the maximum so that the exponentials can't overflow.
+	weights := make([]float64, len(nodeScoreList))
+	var total float64
+	for i, ns := range nodeScoreList {
+		weights[i] = math.Exp(float64(ns.Score-maxScore) / s.Temperature)
+		total += weights[i]
+	}
+	target := r.Float64() * total
+	for i, w := range weights {
+		target -= w
+		if target < 0 {
+			return nodeScoreList[i].Name, nil
+		}
+	}
+	// Guard against rounding errors in the cumulative sum.
+	return nodeScoreList[len(nodeScoreList)-1].Name, nil
+}
+
+// TopK picks a node uniformly at random among the K nodes with the highest scores.
+type TopK struct {
+	K int
+}
+
+var _ framework.HostSelector = &TopK{}
+
+// Name returns the name of the strategy.
+func (s *TopK) Name() string {
+	return string(config.TopKHostSelector)
+}
+
+// Select picks one of the K best scored nodes. Nodes with equal scores keep
+// their relative order, so ties at the K-th position favor earlier nodes.
+func (s *TopK) Select(r *rand.Rand, nodeScoreList framework.NodeScoreList) (string, error) {
+	if len(nodeScoreList) == 0 {
+		return "", errEmptyPriorityList
+	}
+	sorted := make(framework.NodeScoreList, len(nodeScoreList))
+	copy(sorted, nodeScoreList)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Score > sorted[j].Score
+	})
+	k := s.K
+	if k > len(sorted) {
+		k = len(sorted)
+	}
+	return sorted[r.Intn(k)].Name, nil
+}
+
+// EpsilonGreedy picks a node uniformly at random among all the nodes with
+// probability Epsilon, and the node with the highest score otherwise.
+type EpsilonGreedy struct {
+	Epsilon float64
+	greedy  MaxScore
+}
+
+var _ framework.HostSelector = &EpsilonGreedy{}
+
+// Name returns the name of the strategy.
+func (s *EpsilonGreedy) Name() string {
+	return string(config.EpsilonGreedyHostSelector)
+}
+
+// Select either explores a random node or exploits the best scored one.
+func (s *EpsilonGreedy) Select(r *rand.Rand, nodeScoreList framework.NodeScoreList) (string, error) {
+	if len(nodeScoreList) == 0 {
+		return "", errEmptyPriorityList
+	}
+	if r.Float64() < s.Epsilon {
+		return nodeScoreList[r.Intn(len(nodeScoreList))].Name, nil
+	}
+	return s.greedy.Select(r, nodeScoreList)
+}
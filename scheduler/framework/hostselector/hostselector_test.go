@@ -0,0 +1,269 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostselector
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	numDraws = 20000
+	// tolerance is the maximum absolute difference allowed between an observed
+	// frequency and its expected probability.
+	tolerance = 0.02
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      *config.HostSelector
+		wantName string
+		wantErr  bool
+	}{
+		{
+			name:     "nil config defaults to MaxScore",
+			wantName: "MaxScore",
+		},
+		{
+			name:     "softmax",
+			cfg:      &config.HostSelector{Type: config.SoftmaxHostSelector, Temperature: 1},
+			wantName: "Softmax",
+		},
+		{
+			name:     "top-k",
+			cfg:      &config.HostSelector{Type: config.TopKHostSelector, K: 3},
+			wantName: "TopK",
+		},
+		{
+			name:     "epsilon-greedy",
+			cfg:      &config.HostSelector{Type: config.EpsilonGreedyHostSelector, Epsilon: 0.1},
+			wantName: "EpsilonGreedy",
+		},
+		{
+			name:    "softmax without temperature",
+			cfg:     &config.HostSelector{Type: config.SoftmaxHostSelector},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			cfg:     &config.HostSelector{Type: "Unknown"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.Name() != tt.wantName {
+				t.Errorf("New() returned %q, want %q", s.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestMaxScore(t *testing.T) {
+	tests := []struct {
+		name          string
+		list          framework.NodeScoreList
+		possibleHosts sets.String
+		expectsErr    bool
+	}{
+		{
+			name: "unique properly ordered scores",
+			list: []framework.NodeScore{
+				{Name: "machine1.1", Score: 1},
+				{Name: "machine2.1", Score: 2},
+			},
+			possibleHosts: sets.NewString("machine2.1"),
+			expectsErr:    false,
+		},
+		{
+			name: "equal scores",
+			list: []framework.NodeScore{
+				{Name: "machine1.1", Score: 1},
+				{Name: "machine1.2", Score: 2},
+				{Name: "machine1.3", Score: 2},
+				{Name: "machine2.1", Score: 2},
+			},
+			possibleHosts: sets.NewString("machine1.2", "machine1.3", "machine2.1"),
+			expectsErr:    false,
+		},
+		{
+			name: "out of order scores",
+			list: []framework.NodeScore{
+				{Name: "machine1.1", Score: 3},
+				{Name: "machine1.2", Score: 3},
+				{Name: "machine2.1", Score: 2},
+				{Name: "machine3.1", Score: 1},
+				{Name: "machine1.3", Score: 3},
+			},
+			possibleHosts: sets.NewString("machine1.1", "machine1.2", "machine1.3"),
+			expectsErr:    false,
+		},
+		{
+			name:          "empty priority list",
+			list:          []framework.NodeScore{},
+			possibleHosts: sets.NewString(),
+			expectsErr:    true,
+		},
+	}
+
+	s := &MaxScore{}
+	r := rand.New(rand.NewSource(1))
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// increase the randomness
+			for i := 0; i < 10; i++ {
+				got, err := s.Select(r, test.list)
+				if test.expectsErr {
+					if err == nil {
+						t.Error("Unexpected non-error")
+					}
+				} else {
+					if err != nil {
+						t.Errorf("Unexpected error: %v", err)
+					}
+					if !test.possibleHosts.Has(got) {
+						t.Errorf("got %s is not in the possible map %v", got, test.possibleHosts)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSelectDistribution(t *testing.T) {
+	list := framework.NodeScoreList{
+		{Name: "node-a", Score: 0},
+		{Name: "node-b", Score: 1},
+		{Name: "node-c", Score: 2},
+		{Name: "node-d", Score: 2},
+	}
+	softmaxNorm := 1 + math.E + 2*math.E*math.E
+	tests := []struct {
+		name     string
+		selector framework.HostSelector
+		want     map[string]float64
+	}{
+		{
+			name:     "max score splits ties uniformly",
+			selector: &MaxScore{},
+			want:     map[string]float64{"node-c": 0.5, "node-d": 0.5},
+		},
+		{
+			name:     "softmax follows exp(score/temperature)",
+			selector: &Softmax{Temperature: 1},
+			want: map[string]float64{
+				"node-a": 1 / softmaxNorm,
+				"node-b": math.E / softmaxNorm,
+				"node-c": math.E * math.E / softmaxNorm,
+				"node-d": math.E * math.E / softmaxNorm,
+			},
+		},
+		{
+			name:     "softmax with a high temperature is close to uniform",
+			selector: &Softmax{Temperature: 1000},
+			want:     map[string]float64{"node-a": 0.25, "node-b": 0.25, "node-c": 0.25, "node-d": 0.25},
+		},
+		{
+			name:     "top-k picks uniformly among the best k",
+			selector: &TopK{K: 3},
+			want:     map[string]float64{"node-b": 1.0 / 3, "node-c": 1.0 / 3, "node-d": 1.0 / 3},
+		},
+		{
+			name:     "top-k larger than the list picks among all nodes",
+			selector: &TopK{K: 10},
+			want:     map[string]float64{"node-a": 0.25, "node-b": 0.25, "node-c": 0.25, "node-d": 0.25},
+		},
+		{
+			name:     "epsilon-greedy explores with probability epsilon",
+			selector: &EpsilonGreedy{Epsilon: 0.2},
+			want: map[string]float64{
+				"node-a": 0.05,
+				"node-b": 0.05,
+				"node-c": 0.4 + 0.05,
+				"node-d": 0.4 + 0.05,
+			},
+		},
+		{
+			name:     "epsilon-greedy without exploration is max score",
+			selector: &EpsilonGreedy{Epsilon: 0},
+			want:     map[string]float64{"node-c": 0.5, "node-d": 0.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(42))
+			counts := make(map[string]int)
+			for i := 0; i < numDraws; i++ {
+				host, err := tt.selector.Select(r, list)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				counts[host]++
+			}
+			for _, ns := range list {
+				got := float64(counts[ns.Name]) / numDraws
+				if math.Abs(got-tt.want[ns.Name]) > tolerance {
+					t.Errorf("node %s selected with frequency %.3f, want %.3f", ns.Name, got, tt.want[ns.Name])
+				}
+			}
+		})
+	}
+}
+
+func TestSelectIsReproducible(t *testing.T) {
+	list := framework.NodeScoreList{
+		{Name: "node-a", Score: 10},
+		{Name: "node-b", Score: 20},
+		{Name: "node-c", Score: 20},
+		{Name: "node-d", Score: 5},
+	}
+	for _, selector := range []framework.HostSelector{
+		&MaxScore{},
+		&Softmax{Temperature: 10},
+		&TopK{K: 2},
+		&EpsilonGreedy{Epsilon: 0.5},
+	} {
+		t.Run(selector.Name(), func(t *testing.T) {
+			pick := func(seed int64) []string {
+				r := rand.New(rand.NewSource(seed))
+				var hosts []string
+				for i := 0; i < 50; i++ {
+					host, err := selector.Select(r, list)
+					if err != nil {
+						t.Fatalf("Unexpected error: %v", err)
+					}
+					hosts = append(hosts, host)
+				}
+				return hosts
+			}
+			if diff := cmp.Diff(pick(7), pick(7)); diff != "" {
+				t.Errorf("Same seed selected different hosts (-first, +second):\n%s", diff)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"context"
 	"errors"
 	"math"
+	"math/rand"
 	"strings"
 	"sync"
 	"time"
@@ -130,6 +131,33 @@ func NewPodsToActivate() *PodsToActivate {
 	return &PodsToActivate{Map: make(map[string]*v1.Pod)}
 }
 
+// HostFallbackDisabledKey is a reserved state key that plugins write to keep
+// the pod from falling back to the next-best ranked nodes when the node it was
+// assumed on rejects it. Plugins whose Unreserve affects other pods than the
+// one unreserved, e.g. by rejecting the pods waiting with it, must write it.
+var HostFallbackDisabledKey StateKey = "kubernetes.io/host-fallback-disabled"
+
+// hostFallbackDisabled is the state stored at HostFallbackDisabledKey.
+type hostFallbackDisabled struct{}
+
+// Clone just returns the same state.
+func (s hostFallbackDisabled) Clone() StateData {
+	return s
+}
+
+// DisableHostFallback keeps the pod of the scheduling cycle from falling back
+// to the next-best ranked nodes.
+func DisableHostFallback(state *CycleState) {
+	state.Write(HostFallbackDisabledKey, hostFallbackDisabled{})
+}
+
+// IsHostFallbackDisabled returns true if a plugin disabled the host fallback
+// of the pod of the scheduling cycle.
+func IsHostFallbackDisabled(state *CycleState) bool {
+	_, err := state.Read(HostFallbackDisabledKey)
+	return err == nil
+}
+
 // Status indicates the result of running a plugin. It consists of a code, a
 // message, (optionally) an error, and a plugin name it fails by.
 // When the status code is not Success, the reasons should explain why.
@@ -307,6 +335,42 @@ type QueueSortPlugin interface {
 	Less(*QueuedPodInfo, *QueuedPodInfo) bool
 }
 
+// PreEnqueuePlugin is an interface that must be implemented by "PreEnqueue" plugins.
+// These plugins are called before a Pod is added to activeQ, so that Pods can
+// be held out of scheduling until some external condition is met. Every enabled
+// plugin implementing the interface runs, in the order of the plugin names.
+type PreEnqueuePlugin interface {
+	Plugin
+	// PreEnqueue is called when a Pod is added or updated in the scheduling
+	// queue, before it enters activeQ. If it doesn't return "Success", the Pod
+	// is gated: it's parked in the queue until an update of the Pod, or one of
+	// the events registered by the plugin, lets it through.
+	PreEnqueue(ctx context.Context, p *v1.Pod) *Status
+}
+
+// ResyncPlugin is an interface for plugins that keep a copy of some external
+// state, such as a ConfigMap, that they need to refresh even when no pod is
+// scheduled. Every enabled plugin implementing the interface is resynced
+// periodically by the scheduler.
+type ResyncPlugin interface {
+	Plugin
+	// Resync refreshes the state of the plugin. A plugin seeing a change that
+	// may make pods schedulable reports it with Handle.ReportClusterEvent.
+	Resync(ctx context.Context)
+}
+
+// PopPolicy picks which of the pods at the head of the scheduling queue is
+// scheduled next, so that the dispatch order can account for the state of
+// the cluster rather than only for the order of the QueueSort plugin.
+type PopPolicy interface {
+	// Pick returns the index in candidates of the pod to schedule next. The
+	// candidates are copies of the pods sorted by the QueueSort plugin, and
+	// nodes is a snapshot of the cluster, nil if unavailable. Pick is called
+	// without holding the lock of the scheduling queue, but the scheduling
+	// of the next pod waits for it.
+	Pick(candidates []*QueuedPodInfo, nodes NodeInfoLister) (int, error)
+}
+
 // EnqueueExtensions is an optional interface that plugins can implement to efficiently
 // move unschedulable Pods in internal scheduling queues. Plugins
 // that fail pod scheduling (e.g., Filter plugins) are expected to implement this interface.
@@ -320,6 +384,35 @@ type EnqueueExtensions interface {
 	EventsToRegister() []ClusterEvent
 }
 
+// QueueingHint tells whether an event may make a Pod schedulable.
+type QueueingHint int
+
+const (
+	// QueueSkip means the event can't make the Pod schedulable, so the Pod
+	// stays in the unschedulable queue.
+	QueueSkip QueueingHint = iota
+	// Queue means the event may make the Pod schedulable, so the Pod is moved
+	// to the active or backoff queue.
+	Queue
+)
+
+// QueueingHintFn tells whether an event may make a Pod failed by a plugin
+// schedulable. oldObj and newObj are the objects of the event before and after
+// it happened; oldObj is nil for Add events and newObj is nil for Delete events,
+// and both are nil when the objects are unknown.
+type QueueingHintFn func(pod *v1.Pod, oldObj, newObj interface{}) QueueingHint
+
+// QueueingHintExtensions is an optional interface that EnqueueExtensions plugins
+// can implement to filter out the events that can't make a Pod failed by them
+// schedulable, based on the objects of the events.
+type QueueingHintExtensions interface {
+	EnqueueExtensions
+	// QueueingHints returns the hint functions of the plugin, keyed by events
+	// returned by EventsToRegister. Pods failed by the plugin are always moved
+	// on events without hint function.
+	QueueingHints() map[ClusterEvent]QueueingHintFn
+}
+
 // PreFilterExtensions is an interface that is included in plugins that allow specifying
 // callbacks to make incremental updates to its supposedly pre-calculated
 // state.
@@ -494,12 +587,21 @@ type Framework interface {
 	// QueueSortFunc returns the function to sort pods in scheduling queue
 	QueueSortFunc() LessFunc
 
+	// PreEnqueuePlugins returns the PreEnqueue plugins of the profile.
+	PreEnqueuePlugins() []PreEnqueuePlugin
+
+	// ResyncPlugins returns the plugins of the profile to resync periodically.
+	ResyncPlugins() []ResyncPlugin
+
 	// RunPreFilterPlugins runs the set of configured PreFilter plugins. It returns
 	// *Status and its code is set to non-success if any of the plugins returns
 	// anything but Success. If a non-success status is returned, then the scheduling
 	// cycle is aborted.
 	RunPreFilterPlugins(ctx context.Context, state *CycleState, pod *v1.Pod) *Status
 
+	// PostFilterPlugins returns the PostFilter plugins of the profile.
+	PostFilterPlugins() []PostFilterPlugin
+
 	// RunPostFilterPlugins runs the set of configured PostFilter plugins.
 	// PostFilter plugins can either be informational, in which case should be configured
 	// to execute first and return Unschedulable status, or ones that try to change the
@@ -558,6 +660,31 @@ type Framework interface {
 
 	// ProfileName returns the profile name associated to this framework.
 	ProfileName() string
+
+	// HostSelector returns the strategy used to pick the node a pod is assigned to
+	// among the scored feasible nodes.
+	HostSelector() HostSelector
+
+	// ScorePluginWeights returns the current weights of the Score plugins, by
+	// plugin name.
+	ScorePluginWeights() map[string]int
+
+	// SetScorePluginWeights replaces the weights of the given Score plugins for
+	// the scoring that starts afterwards. The weights are left unchanged if a
+	// plugin isn't a Score plugin of the framework, a weight is lower than 1 or
+	// the total score could overflow.
+	SetScorePluginWeights(weights map[string]int) error
+}
+
+// HostSelector picks the node a pod is assigned to among the scored feasible nodes.
+type HostSelector interface {
+	// Name returns the name of the selection strategy. It is reported in the
+	// scheduling decision logs.
+	Name() string
+	// Select returns the name of the selected node. r is the scheduler's source of
+	// randomness, implementations must use it for every random draw so that seeded
+	// schedulers remain reproducible.
+	Select(r *rand.Rand, nodeScoreList NodeScoreList) (string, error)
 }
 
 // Handle provides data and some tools that plugins can use. It is
@@ -606,6 +733,21 @@ type Handle interface {
 
 	// Parallelizer returns a parallelizer holding parallelism for scheduler.
 	Parallelizer() parallelize.Parallelizer
+
+	// Rand returns the source of randomness shared by the scheduler. Plugins that
+	// need randomness should use it instead of the global math/rand functions, so
+	// that a seeded scheduler produces reproducible decisions. It is safe for
+	// concurrent use.
+	Rand() *rand.Rand
+
+	// NetworkTopology returns the latency and bandwidth between nodes measured
+	// by the node telemetry, as reported through the scheduler.
+	NetworkTopology() *NetworkTopology
+
+	// ReportClusterEvent moves the pods that failed scheduling because of the
+	// plugins registering the event to activeQ or backoffQ. It is meant for the
+	// events of synthetic resources, which no informer raises.
+	ReportClusterEvent(evt ClusterEvent)
 }
 
 type NominatingMode int
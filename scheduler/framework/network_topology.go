@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"sync"
+	"time"
+)
+
+// NodeLink is the network link from a node to another.
+type NodeLink struct {
+	// Latency is the round-trip latency between the nodes.
+	Latency time.Duration
+	// Bandwidth is the bandwidth between the nodes, in bytes per second.
+	Bandwidth int64
+}
+
+// NetworkTopology holds the links between nodes measured by the node
+// telemetry. It is safe for concurrent use.
+type NetworkTopology struct {
+	mu sync.RWMutex
+	// links holds the links keyed by source and destination node.
+	links map[string]map[string]NodeLink
+}
+
+// NewNetworkTopology returns an empty NetworkTopology.
+func NewNetworkTopology() *NetworkTopology {
+	return &NetworkTopology{links: make(map[string]map[string]NodeLink)}
+}
+
+// SetLink records the link from a node to another, returning whether it is
+// new or its latency decreased.
+func (t *NetworkTopology) SetLink(from, to string, link NodeLink) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	links, ok := t.links[from]
+	if !ok {
+		links = make(map[string]NodeLink)
+		t.links[from] = links
+	}
+	old, ok := links[to]
+	links[to] = link
+	return !ok || link.Latency < old.Latency
+}
+
+// Link returns the link from a node to another. The link in the opposite
+// direction is returned if only that one was measured.
+func (t *NetworkTopology) Link(from, to string) (NodeLink, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if link, ok := t.links[from][to]; ok {
+		return link, true
+	}
+	link, ok := t.links[to][from]
+	return link, ok
+}
+
+// RemoveNode forgets the links from and to the node.
+func (t *NetworkTopology) RemoveNode(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.links, name)
+	for _, links := range t.links {
+		delete(links, name)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNetworkTopology(t *testing.T) {
+	topo := NewNetworkTopology()
+	fast := NodeLink{Latency: time.Millisecond, Bandwidth: 1000}
+	slow := NodeLink{Latency: 10 * time.Millisecond, Bandwidth: 10}
+	if !topo.SetLink("n1", "n2", slow) {
+		t.Error("Expected a new link to be reported as improved")
+	}
+	if !topo.SetLink("n2", "n3", slow) {
+		t.Error("Expected a new link to be reported as improved")
+	}
+	if topo.SetLink("n1", "n2", slow) {
+		t.Error("Expected an unchanged link not to be reported as improved")
+	}
+	if !topo.SetLink("n1", "n2", fast) {
+		t.Error("Expected a lower latency to be reported as improved")
+	}
+
+	tests := []struct {
+		from, to string
+		want     NodeLink
+		wantOK   bool
+	}{
+		{from: "n1", to: "n2", want: fast, wantOK: true},
+		{from: "n2", to: "n1", want: fast, wantOK: true},
+		{from: "n3", to: "n2", want: slow, wantOK: true},
+		{from: "n1", to: "n3"},
+	}
+	for _, tt := range tests {
+		if got, ok := topo.Link(tt.from, tt.to); got != tt.want || ok != tt.wantOK {
+			t.Errorf("Got link %v (%v) from %q to %q, want %v (%v)", got, ok, tt.from, tt.to, tt.want, tt.wantOK)
+		}
+	}
+
+	topo.RemoveNode("n2")
+	for _, l := range [][2]string{{"n1", "n2"}, {"n2", "n3"}} {
+		if _, ok := topo.Link(l[0], l[1]); ok {
+			t.Errorf("Got link from %q to %q after removing n2", l[0], l[1])
+		}
+	}
+}
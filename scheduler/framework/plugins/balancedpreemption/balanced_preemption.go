@@ -0,0 +1,377 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancedpreemption
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/dqn"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
+)
+
+const (
+	// Name is the name of the plugin used in the plugin registry and configurations.
+	Name = names.BalancedPreemption
+
+	// DefaultMinCandidateNodesPercentage and DefaultMinCandidateNodesAbsolute
+	// are the defaults of the args of the same name.
+	DefaultMinCandidateNodesPercentage = 10
+	DefaultMinCandidateNodesAbsolute   = 100
+	// DefaultWeight is the default weight of the disruption and imbalance costs.
+	DefaultWeight = 1
+	// DefaultAgentTimeout is the default timeout of the requests to the agent.
+	DefaultAgentTimeout = 100 * time.Millisecond
+)
+
+// BalancedPreemption is a PostFilter plugin that finds preemption candidates
+// like DefaultPreemption, but nominates the one ranked first by the RL agent or,
+// without agent, the one with the lowest weighted cost of disruption and
+// cluster load imbalance after preemption.
+type BalancedPreemption struct {
+	*defaultpreemption.DefaultPreemption
+	fh        framework.Handle
+	podLister corelisters.PodLister
+	pdbLister policylisters.PodDisruptionBudgetLister
+
+	disruptionWeight float64
+	balanceWeight    float64
+	agentURL         string
+	client           *http.Client
+}
+
+var _ framework.PostFilterPlugin = &BalancedPreemption{}
+var _ preemption.CandidateRanker = &BalancedPreemption{}
+var _ preemption.Simulator = &BalancedPreemption{}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *BalancedPreemption) Name() string {
+	return Name
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, fh framework.Handle, fts feature.Features) (framework.Plugin, error) {
+	args := config.BalancedPreemptionArgs{}
+	if obj != nil {
+		a, ok := obj.(*config.BalancedPreemptionArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type BalancedPreemptionArgs, got %T", obj)
+		}
+		args = *a
+	}
+	if args.MinCandidateNodesPercentage == 0 && args.MinCandidateNodesAbsolute == 0 {
+		args.MinCandidateNodesPercentage = DefaultMinCandidateNodesPercentage
+		args.MinCandidateNodesAbsolute = DefaultMinCandidateNodesAbsolute
+	}
+	if args.DisruptionWeight == 0 && args.BalanceWeight == 0 {
+		args.DisruptionWeight = DefaultWeight
+		args.BalanceWeight = DefaultWeight
+	}
+	if args.AgentTimeout.Duration == 0 {
+		args.AgentTimeout.Duration = DefaultAgentTimeout
+	}
+	if err := validation.ValidateBalancedPreemptionArgs(nil, &args); err != nil {
+		return nil, err
+	}
+	dp, err := defaultpreemption.New(&config.DefaultPreemptionArgs{
+		MinCandidateNodesPercentage: args.MinCandidateNodesPercentage,
+		MinCandidateNodesAbsolute:   args.MinCandidateNodesAbsolute,
+	}, fh, fts)
+	if err != nil {
+		return nil, err
+	}
+	pl := &BalancedPreemption{
+		DefaultPreemption: dp.(*defaultpreemption.DefaultPreemption),
+		fh:                fh,
+		podLister:         fh.SharedInformerFactory().Core().V1().Pods().Lister(),
+		disruptionWeight:  float64(args.DisruptionWeight),
+		balanceWeight:     float64(args.BalanceWeight),
+		agentURL:          args.AgentURL,
+		client:            &http.Client{Timeout: args.AgentTimeout.Duration},
+	}
+	if fts.EnablePodDisruptionBudget {
+		pl.pdbLister = fh.SharedInformerFactory().Policy().V1().PodDisruptionBudgets().Lister()
+	}
+	return pl, nil
+}
+
+// PostFilter invoked at the postFilter extension point.
+func (pl *BalancedPreemption) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, m framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	defer func() {
+		metrics.PreemptionAttempts.Inc()
+	}()
+
+	return pl.evaluator(state).Preempt(ctx, pod, m)
+}
+
+// SimulatePreemption implements preemption.Simulator.
+func (pl *BalancedPreemption) SimulatePreemption(ctx context.Context, state *framework.CycleState, pod *v1.Pod, m framework.NodeToStatusMap) (*preemption.Simulation, *framework.Status) {
+	return pl.evaluator(state).Simulate(ctx, pod, m)
+}
+
+// evaluator returns the preemption evaluator of the given scheduling cycle.
+func (pl *BalancedPreemption) evaluator(state *framework.CycleState) *preemption.Evaluator {
+	return &preemption.Evaluator{
+		PluginName: Name,
+		Handler:    pl.fh,
+		PodLister:  pl.podLister,
+		PdbLister:  pl.pdbLister,
+		State:      state,
+		Interface:  pl,
+	}
+}
+
+// candidateCost is the cost of nominating a candidate.
+type candidateCost struct {
+	// disruption is the number of victims plus the number of PDB violations.
+	disruption float64
+	// imbalance is the standard deviation of the utilization of the nodes
+	// once the victims are preempted and the preemptor runs on the candidate.
+	imbalance float64
+}
+
+// RankCandidates ranks the candidates by their weighted cost, and then asks
+// the agent, if any, to rank them.
+func (pl *BalancedPreemption) RankCandidates(ctx context.Context, pod *v1.Pod, candidates []preemption.Candidate) ([]preemption.Candidate, error) {
+	nodeInfos, err := pl.fh.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, err
+	}
+	costs := candidateCosts(pod, candidates, nodeInfos)
+	var maxDisruption, maxImbalance float64
+	for _, c := range costs {
+		maxDisruption = math.Max(maxDisruption, c.disruption)
+		maxImbalance = math.Max(maxImbalance, c.imbalance)
+	}
+	// The costs are normalized, so that the weights don't depend on their scale.
+	weighted := make([]float64, len(costs))
+	for i, c := range costs {
+		if maxDisruption > 0 {
+			weighted[i] += pl.disruptionWeight * c.disruption / maxDisruption
+		}
+		if maxImbalance > 0 {
+			weighted[i] += pl.balanceWeight * c.imbalance / maxImbalance
+		}
+	}
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return weighted[order[i]] < weighted[order[j]]
+	})
+	ranked := make([]preemption.Candidate, len(candidates))
+	for i, o := range order {
+		ranked[i] = candidates[o]
+		klog.V(5).InfoS("Ranked preemption candidate", "pod", klog.KObj(pod), "node", candidates[o].Name(), "rank", i,
+			"disruption", costs[o].disruption, "imbalance", costs[o].imbalance, "cost", weighted[o])
+	}
+
+	if len(pl.agentURL) == 0 {
+		return ranked, nil
+	}
+	agentRanked, err := pl.rankByAgent(ctx, pod, ranked, costs, order, nodeInfos)
+	if err != nil {
+		klog.ErrorS(err, "Agent failed to rank preemption candidates, ranking them by cost", "pod", klog.KObj(pod), "agent", pl.agentURL)
+		return ranked, nil
+	}
+	return agentRanked, nil
+}
+
+// candidateCosts returns the costs of the candidates, given the nodes of the
+// cluster.
+func candidateCosts(pod *v1.Pod, candidates []preemption.Candidate, nodeInfos []*framework.NodeInfo) []candidateCost {
+	// The imbalance is computed from the sum and the sum of squares of the node
+	// utilizations, adjusted for the node of each candidate.
+	utilizations := make(map[string]float64, len(nodeInfos))
+	var sum, sumSquares float64
+	for _, n := range nodeInfos {
+		if n.Node() == nil {
+			continue
+		}
+		u := utilization(n.Requested.MilliCPU, n.Requested.Memory, n.Allocatable)
+		utilizations[n.Node().Name] = u
+		sum += u
+		sumSquares += u * u
+	}
+	count := float64(len(utilizations))
+	nodeInfoByName := make(map[string]*framework.NodeInfo, len(nodeInfos))
+	for _, n := range nodeInfos {
+		if n.Node() != nil {
+			nodeInfoByName[n.Node().Name] = n
+		}
+	}
+	podCPU, podMemory := requests(pod)
+
+	costs := make([]candidateCost, len(candidates))
+	for i, c := range candidates {
+		victims := c.Victims()
+		costs[i].disruption = float64(len(victims.Pods)) + float64(victims.NumPDBViolations)
+		n, ok := nodeInfoByName[c.Name()]
+		if !ok || count == 0 {
+			continue
+		}
+		cpu, memory := n.Requested.MilliCPU+podCPU, n.Requested.Memory+podMemory
+		for _, v := range victims.Pods {
+			victimCPU, victimMemory := requests(v)
+			cpu -= victimCPU
+			memory -= victimMemory
+		}
+		before, after := utilizations[c.Name()], utilization(cpu, memory, n.Allocatable)
+		s, sq := sum-before+after, sumSquares-before*before+after*after
+		costs[i].imbalance = math.Sqrt(math.Max(0, sq/count-(s/count)*(s/count)))
+	}
+	return costs
+}
+
+// utilization returns the mean of the CPU and memory utilizations of a node.
+func utilization(milliCPU, memory int64, allocatable *framework.Resource) float64 {
+	var u float64
+	var n int
+	if allocatable.MilliCPU > 0 {
+		u += float64(milliCPU) / float64(allocatable.MilliCPU)
+		n++
+	}
+	if allocatable.Memory > 0 {
+		u += float64(memory) / float64(allocatable.Memory)
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return u / float64(n)
+}
+
+// requests returns the CPU and memory requests of a pod.
+func requests(pod *v1.Pod) (int64, int64) {
+	reqs, _ := resourcehelper.PodRequestsAndLimits(pod)
+	return reqs.Cpu().MilliValue(), reqs.Memory().Value()
+}
+
+// RankRequest is the request sent to the agent to rank the preemption
+// candidates.
+type RankRequest struct {
+	// Pod is the preemptor.
+	Pod dqn.PickPod `json:"pod"`
+	// Candidates are the preemption candidates, ranked by cost.
+	Candidates []RankCandidate `json:"candidates"`
+	// Nodes is the state of the cluster as of the current scheduling cycle.
+	Nodes []dqn.PickNode `json:"nodes"`
+}
+
+// RankCandidate describes a preemption candidate to the agent.
+type RankCandidate struct {
+	Node string `json:"node"`
+	// Victims are the namespaced names of the pods to preempt.
+	Victims          []string `json:"victims"`
+	NumPDBViolations int64    `json:"numPDBViolations"`
+	// Disruption and Imbalance are the costs of the candidate.
+	Disruption float64 `json:"disruption"`
+	Imbalance  float64 `json:"imbalance"`
+}
+
+// rankByAgent sends the candidates ranked by cost to the agent. The agent
+// replies with node names from the most to the least preferred one, separated
+// by commas or new lines; the candidates it leaves out keep their ranking by
+// cost after the ones it lists.
+func (pl *BalancedPreemption) rankByAgent(ctx context.Context, pod *v1.Pod, ranked []preemption.Candidate, costs []candidateCost, order []int, nodeInfos []*framework.NodeInfo) ([]preemption.Candidate, error) {
+	req := RankRequest{
+		Pod:        dqn.NewPickPod(pod, 0),
+		Candidates: make([]RankCandidate, 0, len(ranked)),
+		Nodes:      dqn.NewPickNodes(nodeInfos),
+	}
+	for i, c := range ranked {
+		rc := RankCandidate{
+			Node:             c.Name(),
+			NumPDBViolations: c.Victims().NumPDBViolations,
+			Disruption:       costs[order[i]].disruption,
+			Imbalance:        costs[order[i]].imbalance,
+		}
+		for _, v := range c.Victims().Pods {
+			rc.Victims = append(rc.Victims, v.Namespace+"/"+v.Name)
+		}
+		req.Candidates = append(req.Candidates, rc)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, pl.agentURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := pl.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("agent %v replied with status %v", pl.agentURL, resp.Status)
+	}
+	reply, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]preemption.Candidate, len(ranked))
+	for _, c := range ranked {
+		byName[c.Name()] = c
+	}
+	agentRanked := make([]preemption.Candidate, 0, len(ranked))
+	for _, name := range strings.FieldsFunc(string(reply), func(r rune) bool { return r == ',' || r == '\n' }) {
+		name = strings.TrimSpace(name)
+		c, ok := byName[name]
+		if !ok {
+			if len(name) != 0 {
+				klog.V(4).InfoS("Agent ranked unknown preemption candidate", "pod", klog.KObj(pod), "node", name)
+			}
+			continue
+		}
+		agentRanked = append(agentRanked, c)
+		delete(byName, name)
+	}
+	if len(agentRanked) == 0 {
+		return nil, fmt.Errorf("agent %v ranked none of the candidates", pl.agentURL)
+	}
+	for _, c := range ranked {
+		if _, ok := byName[c.Name()]; ok {
+			agentRanked = append(agentRanked, c)
+		}
+	}
+	return agentRanked, nil
+}
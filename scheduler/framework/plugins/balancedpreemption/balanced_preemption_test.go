@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancedpreemption
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+type fakeCandidate struct {
+	name    string
+	victims *extenderv1.Victims
+}
+
+func (c *fakeCandidate) Victims() *extenderv1.Victims {
+	return c.victims
+}
+
+func (c *fakeCandidate) Name() string {
+	return c.name
+}
+
+func resources(milli string) map[v1.ResourceName]string {
+	return map[v1.ResourceName]string{v1.ResourceCPU: milli + "m", v1.ResourceMemory: milli}
+}
+
+// rankingFixture returns a cluster of three nodes, a preemptor and two
+// candidates: preempting on node1 evicts fewer pods, while preempting on node2
+// leaves the cluster more balanced.
+func rankingFixture() ([]*v1.Node, []*v1.Pod, *v1.Pod, []preemption.Candidate) {
+	var nodes []*v1.Node
+	for _, name := range []string{"node1", "node2", "node3"} {
+		nodes = append(nodes, st.MakeNode().Name(name).Capacity(resources("1000")).Obj())
+	}
+	victim1 := st.MakePod().Name("v1").UID("v1").Node("node1").Priority(10).Req(resources("400")).Obj()
+	victim2 := st.MakePod().Name("v2").UID("v2").Node("node2").Priority(10).Req(resources("300")).Obj()
+	victim3 := st.MakePod().Name("v3").UID("v3").Node("node2").Priority(10).Req(resources("300")).Obj()
+	pods := []*v1.Pod{
+		st.MakePod().Name("p1").UID("p1").Node("node1").Priority(100).Req(resources("500")).Obj(),
+		st.MakePod().Name("p3").UID("p3").Node("node3").Priority(100).Req(resources("100")).Obj(),
+		victim1, victim2, victim3,
+	}
+	preemptor := st.MakePod().Name("preemptor").UID("preemptor").Priority(100).Req(resources("500")).Obj()
+	candidates := []preemption.Candidate{
+		&fakeCandidate{name: "node2", victims: &extenderv1.Victims{Pods: []*v1.Pod{victim2, victim3}}},
+		&fakeCandidate{name: "node1", victims: &extenderv1.Victims{Pods: []*v1.Pod{victim1}}},
+	}
+	return nodes, pods, preemptor, candidates
+}
+
+func newPlugin(t *testing.T, args *config.BalancedPreemptionArgs, nodes []*v1.Node, pods []*v1.Pod) *BalancedPreemption {
+	fh, err := frameworkruntime.NewFramework(nil, nil,
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)),
+		frameworkruntime.WithSnapshotSharedLister(internalcache.NewSnapshot(pods, nodes)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := New(args, fh, feature.Features{})
+	if err != nil {
+		t.Fatalf("Creating plugin: %v", err)
+	}
+	return p.(*BalancedPreemption)
+}
+
+func candidateNames(candidates []preemption.Candidate) []string {
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+func TestRankCandidatesByCost(t *testing.T) {
+	tests := []struct {
+		name string
+		args config.BalancedPreemptionArgs
+		want []string
+	}{
+		{
+			name: "default weights",
+			want: []string{"node1", "node2"},
+		},
+		{
+			name: "disruption only",
+			args: config.BalancedPreemptionArgs{DisruptionWeight: 1},
+			want: []string{"node1", "node2"},
+		},
+		{
+			name: "balance only",
+			args: config.BalancedPreemptionArgs{BalanceWeight: 1},
+			want: []string{"node2", "node1"},
+		},
+		{
+			name: "balance outweighs disruption",
+			args: config.BalancedPreemptionArgs{DisruptionWeight: 1, BalanceWeight: 10},
+			want: []string{"node2", "node1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, pods, preemptor, candidates := rankingFixture()
+			pl := newPlugin(t, &tt.args, nodes, pods)
+			ranked, err := pl.RankCandidates(context.Background(), preemptor, candidates)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, candidateNames(ranked)); diff != "" {
+				t.Errorf("Unexpected ranking (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRankCandidatesByAgent(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		reply  string
+		want   []string
+	}{
+		{
+			name:   "agent ranking",
+			status: http.StatusOK,
+			reply:  "node2\nnode1\n",
+			want:   []string{"node2", "node1"},
+		},
+		{
+			name:   "candidates left out by the agent are ranked last",
+			status: http.StatusOK,
+			reply:  "node2",
+			want:   []string{"node2", "node1"},
+		},
+		{
+			name:   "unknown candidates fall back to cost",
+			status: http.StatusOK,
+			reply:  "node3",
+			want:   []string{"node1", "node2"},
+		},
+		{
+			name:   "agent error falls back to cost",
+			status: http.StatusInternalServerError,
+			want:   []string{"node1", "node2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got RankRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("Decoding request: %v", err)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.reply))
+			}))
+			defer server.Close()
+
+			nodes, pods, preemptor, candidates := rankingFixture()
+			pl := newPlugin(t, &config.BalancedPreemptionArgs{AgentURL: server.URL}, nodes, pods)
+			ranked, err := pl.RankCandidates(context.Background(), preemptor, candidates)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, candidateNames(ranked)); diff != "" {
+				t.Errorf("Unexpected ranking (-want,+got):\n%s", diff)
+			}
+			if len(got.Candidates) != 2 || got.Candidates[0].Node != "node1" || len(got.Nodes) != 3 {
+				t.Errorf("Unexpected request to the agent: %+v", got)
+			}
+		})
+	}
+}
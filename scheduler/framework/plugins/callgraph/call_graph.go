@@ -0,0 +1,301 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package callgraph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/helper"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Name is the name of the plugin used in the plugin registry and configurations.
+	Name = names.CallGraph
+
+	// DefaultServiceLabel is the default value of CallGraphArgs.ServiceLabel.
+	DefaultServiceLabel = "app"
+
+	// UpstreamServicesAnnotation is the annotation listing the services sending
+	// traffic to the pod, as comma-separated "service[:weight]" entries. The
+	// weight defaults to 1.
+	UpstreamServicesAnnotation = "scheduling.alpha.kubernetes.io/upstream-services"
+	// DownstreamServicesAnnotation is the annotation listing the services the
+	// pod sends traffic to, in the format of UpstreamServicesAnnotation.
+	DownstreamServicesAnnotation = "scheduling.alpha.kubernetes.io/downstream-services"
+
+	// preScoreStateKey is the key in CycleState to CallGraph pre-computed data for Scoring.
+	preScoreStateKey = "PreScore" + Name
+)
+
+// CallGraph is a plugin that favors nodes running the services the pod
+// exchanges traffic with, so that the traffic of service chains stays
+// node-local. The service dependency graph is read from the args, a ConfigMap
+// and the annotations of the pods.
+type CallGraph struct {
+	handle          framework.Handle
+	configMapGetter *helper.ConfigMapGetter
+	args            config.CallGraphArgs
+	// dependencies are the edges of the args.
+	dependencies map[edge]int64
+
+	mu sync.Mutex
+	// configMap and configMapDependencies cache the edges parsed from the last
+	// seen version of the dependency ConfigMap.
+	configMap             *v1.ConfigMap
+	configMapDependencies map[edge]int64
+}
+
+var _ framework.PreScorePlugin = &CallGraph{}
+var _ framework.ScorePlugin = &CallGraph{}
+
+// edge is a directed edge of the service dependency graph.
+type edge struct {
+	upstream, downstream string
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *CallGraph) Name() string {
+	return Name
+}
+
+// preScoreState computed at PreScore and used at Score.
+type preScoreState struct {
+	// service is the service of the pod, if any.
+	service string
+	// downstream and upstream are the weights of the traffic the pod sends to
+	// and receives from each service, according to the graph and to the
+	// annotations of the pod.
+	downstream map[string]int64
+	upstream   map[string]int64
+}
+
+// Clone implements the mandatory Clone interface. We don't really copy the data since
+// there is no need for that.
+func (s *preScoreState) Clone() framework.StateData {
+	return s
+}
+
+// PreScore builds and writes cycle state used by Score.
+func (pl *CallGraph) PreScore(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodes []*v1.Node) *framework.Status {
+	s := &preScoreState{
+		service:    pod.Labels[pl.args.ServiceLabel],
+		downstream: parseServices(pod, DownstreamServicesAnnotation),
+		upstream:   parseServices(pod, UpstreamServicesAnnotation),
+	}
+	if len(s.service) != 0 {
+		for e, w := range pl.graph(ctx) {
+			if e.upstream == s.service {
+				setMax(s.downstream, e.downstream, w)
+			}
+			if e.downstream == s.service {
+				setMax(s.upstream, e.upstream, w)
+			}
+		}
+	}
+	cycleState.Write(preScoreStateKey, s)
+	return nil
+}
+
+func getPreScoreState(cycleState *framework.CycleState) (*preScoreState, error) {
+	c, err := cycleState.Read(preScoreStateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q from cycleState: %w", preScoreStateKey, err)
+	}
+
+	s, ok := c.(*preScoreState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to callgraph.preScoreState error", c)
+	}
+	return s, nil
+}
+
+// Score invoked at the Score extension point. The score of a node is the
+// weight of the traffic between the pod and the pods already on the node.
+func (pl *CallGraph) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreScoreState(state)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	if len(s.service) == 0 && len(s.downstream) == 0 && len(s.upstream) == 0 {
+		return 0, nil
+	}
+	nodeInfo, err := pl.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+	}
+
+	var score int64
+	for _, p := range nodeInfo.Pods {
+		score += pl.traffic(s, p.Pod)
+	}
+	return score, nil
+}
+
+// traffic returns the weight of the traffic between the pod of the state and
+// the other pod. The weight of each direction is the highest of the ones
+// declared by the graph and the annotations of both pods.
+func (pl *CallGraph) traffic(s *preScoreState, other *v1.Pod) int64 {
+	service := other.Labels[pl.args.ServiceLabel]
+	var downstream, upstream int64
+	if len(service) != 0 {
+		downstream, upstream = s.downstream[service], s.upstream[service]
+	}
+	if len(s.service) != 0 && len(other.Annotations) != 0 {
+		if w := parseServices(other, UpstreamServicesAnnotation)[s.service]; w > downstream {
+			downstream = w
+		}
+		if w := parseServices(other, DownstreamServicesAnnotation)[s.service]; w > upstream {
+			upstream = w
+		}
+	}
+	return downstream + upstream
+}
+
+// NormalizeScore invoked after scoring all nodes.
+func (pl *CallGraph) NormalizeScore(ctx context.Context, _ *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	return helper.DefaultNormalizeScore(framework.MaxNodeScore, false, scores)
+}
+
+// ScoreExtensions of the Score plugin.
+func (pl *CallGraph) ScoreExtensions() framework.ScoreExtensions {
+	return pl
+}
+
+// graph returns the edges of the service dependency graph. The edges of the
+// ConfigMap take precedence over the ones of the args.
+func (pl *CallGraph) graph(ctx context.Context) map[edge]int64 {
+	if pl.configMapGetter == nil {
+		return pl.dependencies
+	}
+	cm, err := pl.configMapGetter.Get(ctx)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get the service dependency ConfigMap", "configMap", klog.KRef(pl.args.DependencyConfigMapNamespace, pl.args.DependencyConfigMapName))
+		}
+		return pl.dependencies
+	}
+
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	// The getter returns the same object until the ConfigMap is updated.
+	if cm != pl.configMap {
+		pl.configMap = cm
+		pl.configMapDependencies = make(map[edge]int64, len(pl.dependencies))
+		for e, w := range pl.dependencies {
+			pl.configMapDependencies[e] = w
+		}
+		for e, w := range parseDependencies(cm) {
+			pl.configMapDependencies[e] = w
+		}
+	}
+	return pl.configMapDependencies
+}
+
+// parseDependencies returns the valid edges of the ConfigMap. Invalid edges
+// are logged and ignored.
+func parseDependencies(cm *v1.ConfigMap) map[edge]int64 {
+	edges := make(map[edge]int64)
+	for upstream, data := range cm.Data {
+		var downstreams map[string]int64
+		if err := yaml.UnmarshalStrict([]byte(data), &downstreams); err != nil {
+			klog.ErrorS(err, "Ignoring invalid service dependencies", "configMap", klog.KObj(cm), "service", upstream)
+			continue
+		}
+		for downstream, w := range downstreams {
+			d := &config.ServiceDependency{Upstream: upstream, Downstream: downstream, Weight: w}
+			if errs := validation.ValidateServiceDependency(field.NewPath("data").Key(upstream), d); len(errs) > 0 {
+				klog.ErrorS(errs.ToAggregate(), "Ignoring invalid service dependency", "configMap", klog.KObj(cm), "upstream", upstream, "downstream", downstream)
+				continue
+			}
+			edges[edge{upstream: upstream, downstream: downstream}] = w
+		}
+	}
+	return edges
+}
+
+// parseServices returns the weights of the services listed in the annotation
+// of the pod. Malformed entries are ignored.
+func parseServices(pod *v1.Pod, annotation string) map[string]int64 {
+	services := make(map[string]int64)
+	v, ok := pod.Annotations[annotation]
+	if !ok {
+		return services
+	}
+	for _, entry := range strings.Split(v, ",") {
+		service, weight := strings.TrimSpace(entry), "1"
+		if i := strings.LastIndex(service, ":"); i >= 0 {
+			service, weight = strings.TrimSpace(service[:i]), strings.TrimSpace(service[i+1:])
+		}
+		w, err := strconv.ParseInt(weight, 10, 64)
+		if len(service) == 0 || err != nil || w <= 0 {
+			klog.V(5).InfoS("Ignoring malformed service dependency", "pod", klog.KObj(pod), "annotation", annotation, "entry", entry)
+			continue
+		}
+		setMax(services, service, w)
+	}
+	return services
+}
+
+func setMax(weights map[string]int64, service string, w int64) {
+	if w > weights[service] {
+		weights[service] = w
+	}
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	args := config.CallGraphArgs{}
+	if obj != nil {
+		a, ok := obj.(*config.CallGraphArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type CallGraphArgs, got %T", obj)
+		}
+		args = *a
+	}
+	if err := validation.ValidateCallGraphArgs(nil, &args); err != nil {
+		return nil, err
+	}
+	if len(args.ServiceLabel) == 0 {
+		args.ServiceLabel = DefaultServiceLabel
+	}
+	pl := &CallGraph{
+		handle:       h,
+		args:         args,
+		dependencies: make(map[edge]int64, len(args.Dependencies)),
+	}
+	for _, d := range args.Dependencies {
+		pl.dependencies[edge{upstream: d.Upstream, downstream: d.Downstream}] = d.Weight
+	}
+	if len(args.DependencyConfigMapName) != 0 {
+		pl.configMapGetter = helper.NewConfigMapGetter(h.ClientSet(), args.DependencyConfigMapNamespace, args.DependencyConfigMapName, helper.ConfigMapRefreshInterval)
+	}
+	return pl, nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package callgraph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+var defaultDependencies = []config.ServiceDependency{
+	{Upstream: "frontend", Downstream: "backend", Weight: 10},
+	{Upstream: "backend", Downstream: "db", Weight: 5},
+}
+
+func TestScore(t *testing.T) {
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node1").Obj(),
+		st.MakeNode().Name("node2").Obj(),
+		st.MakeNode().Name("node3").Obj(),
+	}
+	pods := []*v1.Pod{
+		st.MakePod().Name("frontend").Node("node1").Label("app", "frontend").Obj(),
+		st.MakePod().Name("db").Node("node2").Label("app", "db").Annotation(UpstreamServicesAnnotation, "backend:8").Obj(),
+		st.MakePod().Name("web").Node("node3").Label("app", "web").Obj(),
+		st.MakePod().Name("unlabeled").Node("node3").Obj(),
+	}
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want framework.NodeScoreList
+	}{
+		{
+			name: "graph and annotations of the other pods",
+			pod:  st.MakePod().Name("p").Label("app", "backend").Obj(),
+			want: []framework.NodeScore{{Name: "node1", Score: 100}, {Name: "node2", Score: 80}, {Name: "node3", Score: 0}},
+		},
+		{
+			name: "annotations of the pod",
+			pod:  st.MakePod().Name("p").Annotation(DownstreamServicesAnnotation, "db:3, web, frontend:bad").Obj(),
+			want: []framework.NodeScore{{Name: "node1", Score: 0}, {Name: "node2", Score: 100}, {Name: "node3", Score: 33}},
+		},
+		{
+			name: "no dependencies",
+			pod:  st.MakePod().Name("p").Obj(),
+			want: []framework.NodeScore{{Name: "node1", Score: 0}, {Name: "node2", Score: 0}, {Name: "node3", Score: 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			state := framework.NewCycleState()
+			fh, _ := frameworkruntime.NewFramework(nil, nil, frameworkruntime.WithSnapshotSharedLister(cache.NewSnapshot(pods, nodes)))
+			p, err := New(&config.CallGraphArgs{Dependencies: defaultDependencies}, fh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pl := p.(*CallGraph)
+			if status := pl.PreScore(ctx, state, tt.pod, nodes); !status.IsSuccess() {
+				t.Fatalf("Unexpected PreScore status: %v", status)
+			}
+			var got framework.NodeScoreList
+			for _, n := range nodes {
+				score, status := pl.Score(ctx, state, tt.pod, n.Name)
+				if !status.IsSuccess() {
+					t.Fatalf("Unexpected Score status: %v", status)
+				}
+				got = append(got, framework.NodeScore{Name: n.Name, Score: score})
+			}
+			if status := pl.ScoreExtensions().NormalizeScore(ctx, state, tt.pod, got); !status.IsSuccess() {
+				t.Fatalf("Unexpected NormalizeScore status: %v", status)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unexpected scores (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigMapDependencies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "call-graph"},
+		Data: map[string]string{
+			"frontend": "backend: 20\ncache: 2\n",
+			"backend":  "db: 0\n",
+			"cache":    "not a map",
+		},
+	}
+	fh, err := frameworkruntime.NewFramework(nil, nil, frameworkruntime.WithClientSet(clientsetfake.NewSimpleClientset(cm)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := New(&config.CallGraphArgs{
+		Dependencies:                 defaultDependencies,
+		DependencyConfigMapNamespace: "kube-system",
+		DependencyConfigMapName:      "call-graph",
+	}, fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := p.(*CallGraph).graph(ctx)
+	want := map[edge]int64{
+		{upstream: "frontend", downstream: "backend"}: 20,
+		{upstream: "frontend", downstream: "cache"}:   2,
+		{upstream: "backend", downstream: "db"}:       5,
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(edge{})); diff != "" {
+		t.Errorf("Unexpected dependencies (-want,+got):\n%s", diff)
+	}
+}
+
+func TestNew(t *testing.T) {
+	fh, err := frameworkruntime.NewFramework(nil, nil, frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		args    runtime.Object
+		wantErr bool
+	}{
+		{
+			name: "nil args",
+		},
+		{
+			name: "valid args",
+			args: &config.CallGraphArgs{Dependencies: defaultDependencies},
+		},
+		{
+			name:    "invalid args",
+			args:    &config.CallGraphArgs{Dependencies: []config.ServiceDependency{{Upstream: "frontend", Weight: 1}}},
+			wantErr: true,
+		},
+		{
+			name:    "wrong args type",
+			args:    &config.CoschedulingArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.args, fh); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
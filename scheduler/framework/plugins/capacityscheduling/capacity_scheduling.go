@@ -0,0 +1,546 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/helper"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+	"k8s.io/kubernetes/pkg/scheduler/util"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Name is the name of the plugin used in the plugin registry and configurations.
+	Name = names.CapacityScheduling
+
+	// preFilterStateKey is the key in CycleState to CapacityScheduling pre-computed data.
+	preFilterStateKey = "PreFilter" + Name
+)
+
+// elasticQuotaUpdate is the event reported when the quotas of the ConfigMap
+// are updated.
+var elasticQuotaUpdate = framework.ClusterEvent{Resource: framework.ElasticQuota, ActionType: framework.Update, Label: "ElasticQuotaUpdate"}
+
+// CapacityScheduling is a plugin that enforces elastic capacity quotas per
+// namespace. A namespace can always use up to its min quota, and can borrow the
+// unused min quota of other namespaces up to its max quota. Pods borrowing
+// beyond the min quota of their namespace are the ones preempted to give the
+// borrowed resources back.
+type CapacityScheduling struct {
+	fh              framework.Handle
+	podLister       corelisters.PodLister
+	configMapGetter *helper.ConfigMapGetter
+	args            config.CapacitySchedulingArgs
+
+	mu sync.Mutex
+	// reserved is the ledger of the pods of quota namespaces that were reserved
+	// but may not be in the snapshot yet, keyed by pod UID. Entries are removed
+	// once their pod is in the snapshot, unreserved or deleted.
+	reserved map[types.UID]*reservation
+	// configMap and configMapQuotas cache the quotas parsed from the last seen
+	// version of the quota ConfigMap.
+	configMap       *v1.ConfigMap
+	configMapQuotas map[string]*config.ElasticQuota
+}
+
+// reservation is an entry of the ledger of reserved pods.
+type reservation struct {
+	namespace, name string
+	request         v1.ResourceList
+}
+
+var _ framework.PreFilterPlugin = &CapacityScheduling{}
+var _ framework.PostFilterPlugin = &CapacityScheduling{}
+var _ framework.ReservePlugin = &CapacityScheduling{}
+var _ framework.EnqueueExtensions = &CapacityScheduling{}
+var _ framework.ResyncPlugin = &CapacityScheduling{}
+var _ preemption.Interface = &CapacityScheduling{}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *CapacityScheduling) Name() string {
+	return Name
+}
+
+// quotaUsage is the elastic quota of a namespace along with its current usage.
+type quotaUsage struct {
+	min  v1.ResourceList
+	max  v1.ResourceList
+	used v1.ResourceList
+}
+
+// borrowing returns whether the namespace uses more than its min quota.
+func (q *quotaUsage) borrowing() bool {
+	ok, _ := quota.LessThanOrEqual(q.used, q.min)
+	return !ok
+}
+
+// preFilterState computed at PreFilter and used at PostFilter.
+type preFilterState struct {
+	podReq v1.ResourceList
+	// quotas holds the quota usage of every namespace with a quota.
+	quotas map[string]*quotaUsage
+}
+
+// Clone the prefilter state.
+func (s *preFilterState) Clone() framework.StateData {
+	quotas := make(map[string]*quotaUsage, len(s.quotas))
+	for ns, q := range s.quotas {
+		quotas[ns] = &quotaUsage{min: q.min, max: q.max, used: q.used.DeepCopy()}
+	}
+	return &preFilterState{podReq: s.podReq, quotas: quotas}
+}
+
+// fits returns whether a pod of the given namespace requesting podReq fits in
+// the quotas.
+func (s *preFilterState) fits(namespace string) *framework.Status {
+	q := s.quotas[namespace]
+	used := quota.Add(q.used, s.podReq)
+	if ok, exceeded := quota.LessThanOrEqual(used, q.max); !ok {
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("namespace %s would exceed its max quota of %v", namespace, exceeded))
+	}
+	if ok, _ := quota.LessThanOrEqual(used, q.min); ok {
+		return nil
+	}
+	// The pod borrows from the unused min quota of other namespaces.
+	totalUsed, totalMin := s.podReq, v1.ResourceList{}
+	for _, q := range s.quotas {
+		totalUsed = quota.Add(totalUsed, q.used)
+		totalMin = quota.Add(totalMin, q.min)
+	}
+	if ok, exceeded := quota.LessThanOrEqual(totalUsed, totalMin); !ok {
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("namespace %s would exceed its min quota of %v with no unused quota left to borrow", namespace, exceeded))
+	}
+	return nil
+}
+
+func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, error) {
+	c, err := cycleState.Read(preFilterStateKey)
+	if err != nil {
+		// preFilterState doesn't exist, likely PreFilter wasn't invoked.
+		return nil, fmt.Errorf("reading %q from cycleState: %w", preFilterStateKey, err)
+	}
+
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v  convert to capacityscheduling.preFilterState error", c)
+	}
+	return s, nil
+}
+
+// PreFilter rejects the pod if its namespace has an elastic quota that the pod
+// doesn't fit in. The usage of the namespaces is computed from the snapshot and
+// the ledger of reserved pods.
+func (pl *CapacityScheduling) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod) *framework.Status {
+	quotas := pl.quotas(ctx)
+	if _, ok := quotas[pod.Namespace]; !ok {
+		return nil
+	}
+	s, err := pl.computeUsage(pod, quotas)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	cycleState.Write(preFilterStateKey, s)
+	return s.fits(pod.Namespace)
+}
+
+// computeUsage returns the quota usage of the namespaces with a quota, not
+// counting the given pod.
+func (pl *CapacityScheduling) computeUsage(pod *v1.Pod, quotas map[string]*config.ElasticQuota) (*preFilterState, error) {
+	nodeInfos, err := pl.fh.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, err
+	}
+	s := &preFilterState{
+		podReq: podRequest(pod),
+		quotas: make(map[string]*quotaUsage, len(quotas)),
+	}
+	for ns, q := range quotas {
+		s.quotas[ns] = &quotaUsage{min: q.Min, max: q.Max, used: v1.ResourceList{}}
+	}
+	inSnapshot := make(map[types.UID]bool)
+	for _, nodeInfo := range nodeInfos {
+		for _, pInfo := range nodeInfo.Pods {
+			p := pInfo.Pod
+			if q, ok := s.quotas[p.Namespace]; ok && p.UID != pod.UID {
+				q.used = quota.Add(q.used, podRequest(p))
+				inSnapshot[p.UID] = true
+			}
+		}
+	}
+
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	for uid, r := range pl.reserved {
+		if inSnapshot[uid] {
+			// The snapshot accounts for the pod from now on.
+			delete(pl.reserved, uid)
+			continue
+		}
+		if p, err := pl.podLister.Pods(r.namespace).Get(r.name); apierrors.IsNotFound(err) || (err == nil && p.UID != uid) {
+			// The pod was deleted before it made it to the snapshot, without
+			// being unreserved.
+			delete(pl.reserved, uid)
+			continue
+		}
+		if q, ok := s.quotas[r.namespace]; ok && uid != pod.UID {
+			q.used = quota.Add(q.used, r.request)
+		}
+	}
+	return s, nil
+}
+
+// PreFilterExtensions returns prefilter extensions, pod add and remove.
+func (pl *CapacityScheduling) PreFilterExtensions() framework.PreFilterExtensions {
+	return pl
+}
+
+// AddPod from pre-computed data in cycleState.
+func (pl *CapacityScheduling) AddPod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *v1.Pod, podInfoToAdd *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		// The namespace of the pod has no quota.
+		return nil
+	}
+	if q, ok := s.quotas[podInfoToAdd.Pod.Namespace]; ok {
+		q.used = quota.Add(q.used, podRequest(podInfoToAdd.Pod))
+	}
+	return nil
+}
+
+// RemovePod from pre-computed data in cycleState.
+func (pl *CapacityScheduling) RemovePod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *v1.Pod, podInfoToRemove *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		// The namespace of the pod has no quota.
+		return nil
+	}
+	if q, ok := s.quotas[podInfoToRemove.Pod.Namespace]; ok {
+		q.used = quota.Subtract(q.used, podRequest(podInfoToRemove.Pod))
+	}
+	return nil
+}
+
+// Reserve records the pod in the ledger until it shows up in the snapshot.
+func (pl *CapacityScheduling) Reserve(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		// The namespace of the pod has no quota.
+		return nil
+	}
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.reserved[pod.UID] = &reservation{namespace: pod.Namespace, name: pod.Name, request: s.podReq}
+	return nil
+}
+
+// Unreserve removes the pod from the ledger.
+func (pl *CapacityScheduling) Unreserve(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	delete(pl.reserved, pod.UID)
+}
+
+// PostFilter preempts pods borrowing beyond the min quota of their namespace to
+// make room for the pod. It does nothing for pods of namespaces without quota.
+func (pl *CapacityScheduling) PostFilter(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, m framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	if _, err := getPreFilterState(cycleState); err != nil {
+		return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("namespace %s has no elastic quota", pod.Namespace))
+	}
+	pe := preemption.Evaluator{
+		PluginName: Name,
+		Handler:    pl.fh,
+		PodLister:  pl.podLister,
+		State:      cycleState,
+		Interface:  pl,
+	}
+	return pe.Preempt(ctx, pod, m)
+}
+
+// GetOffsetAndNumCandidates chooses a random offset and shortlists all the
+// nodes for dry running preemption.
+func (pl *CapacityScheduling) GetOffsetAndNumCandidates(numNodes int32) (int32, int32) {
+	return pl.fh.Rand().Int31n(numNodes), numNodes
+}
+
+// CandidatesToVictimsMap builds a map from the target node to a list of
+// to-be-preempted Pods.
+func (pl *CapacityScheduling) CandidatesToVictimsMap(candidates []preemption.Candidate) map[string]*extenderv1.Victims {
+	m := make(map[string]*extenderv1.Victims)
+	for _, c := range candidates {
+		m[c.Name()] = c.Victims()
+	}
+	return m
+}
+
+// PodEligibleToPreemptOthers determines whether this pod should be considered
+// for preempting other pods or not. A pod that already preempted pods that are
+// still terminating on its nominated node isn't.
+func (pl *CapacityScheduling) PodEligibleToPreemptOthers(pod *v1.Pod, nominatedNodeStatus *framework.Status) bool {
+	if pod.Spec.PreemptionPolicy != nil && *pod.Spec.PreemptionPolicy == v1.PreemptNever {
+		klog.V(5).InfoS("Pod is not eligible for preemption because it has a preemptionPolicy of Never", "pod", klog.KObj(pod))
+		return false
+	}
+	nomNodeName := pod.Status.NominatedNodeName
+	if len(nomNodeName) == 0 || nominatedNodeStatus.Code() == framework.UnschedulableAndUnresolvable {
+		return true
+	}
+	quotas := pl.quotas(context.TODO())
+	if _, ok := quotas[pod.Namespace]; !ok {
+		return true
+	}
+	s, err := pl.computeUsage(pod, quotas)
+	if err != nil {
+		klog.ErrorS(err, "Failed to compute the quota usage", "pod", klog.KObj(pod))
+		return true
+	}
+	if nodeInfo, _ := pl.fh.SnapshotSharedLister().NodeInfos().Get(nomNodeName); nodeInfo != nil {
+		for _, p := range nodeInfo.Pods {
+			if p.Pod.DeletionTimestamp != nil && pl.canPreempt(pod, p.Pod, s) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// canPreempt returns whether the preemptor can preempt the victim. Only the
+// pods of namespaces borrowing beyond their min quota can be preempted: the
+// lower priority ones of the namespace of the preemptor, and, if the
+// namespace of the preemptor stays within its min quota, any of the other
+// namespaces.
+func (pl *CapacityScheduling) canPreempt(preemptor, victim *v1.Pod, s *preFilterState) bool {
+	q, ok := s.quotas[victim.Namespace]
+	if !ok || !q.borrowing() {
+		return false
+	}
+	if victim.Namespace == preemptor.Namespace {
+		return corev1helpers.PodPriority(victim) < corev1helpers.PodPriority(preemptor)
+	}
+	own := s.quotas[preemptor.Namespace]
+	ok, _ = quota.LessThanOrEqual(quota.Add(own.used, s.podReq), own.min)
+	return ok
+}
+
+// SelectVictimsOnNode finds minimum set of pods on the given node that should be
+// preempted in order to make enough room for "pod" to be scheduled, both on the
+// node and in the quotas. PodDisruptionBudgets are not taken into account.
+func (pl *CapacityScheduling) SelectVictimsOnNode(
+	ctx context.Context,
+	cycleState *framework.CycleState,
+	pod *v1.Pod,
+	nodeInfo *framework.NodeInfo,
+	pdbs []*policy.PodDisruptionBudget) ([]*v1.Pod, int, *framework.Status) {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		return nil, 0, framework.AsStatus(err)
+	}
+	removePod := func(rpi *framework.PodInfo) error {
+		if err := nodeInfo.RemovePod(rpi.Pod); err != nil {
+			return err
+		}
+		status := pl.fh.RunPreFilterExtensionRemovePod(ctx, cycleState, pod, rpi, nodeInfo)
+		if !status.IsSuccess() {
+			return status.AsError()
+		}
+		return nil
+	}
+	addPod := func(api *framework.PodInfo) error {
+		nodeInfo.AddPodInfo(api)
+		status := pl.fh.RunPreFilterExtensionAddPod(ctx, cycleState, pod, api, nodeInfo)
+		if !status.IsSuccess() {
+			return status.AsError()
+		}
+		return nil
+	}
+	fits := func() *framework.Status {
+		if status := pl.fh.RunFilterPluginsWithNominatedPods(ctx, cycleState, pod, nodeInfo); !status.IsSuccess() {
+			return status
+		}
+		return s.fits(pod.Namespace)
+	}
+
+	// Decide the potential victims before removing any pod, as removing pods
+	// changes which namespaces are borrowing.
+	var potentialVictims []*framework.PodInfo
+	for _, pi := range nodeInfo.Pods {
+		if pl.canPreempt(pod, pi.Pod, s) {
+			potentialVictims = append(potentialVictims, pi)
+		}
+	}
+	if len(potentialVictims) == 0 {
+		message := fmt.Sprintf("No victims found on node %v for preemptor pod %v", nodeInfo.Node().Name, pod.Name)
+		return nil, 0, framework.NewStatus(framework.UnschedulableAndUnresolvable, message)
+	}
+	for _, pi := range potentialVictims {
+		if err := removePod(pi); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		}
+	}
+	if status := fits(); !status.IsSuccess() {
+		return nil, 0, status
+	}
+
+	// Try to reprieve as many pods as possible, starting from the highest
+	// priority ones.
+	var victims []*v1.Pod
+	sort.Slice(potentialVictims, func(i, j int) bool { return util.MoreImportantPod(potentialVictims[i].Pod, potentialVictims[j].Pod) })
+	for _, pi := range potentialVictims {
+		if err := addPod(pi); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		}
+		if fits().IsSuccess() {
+			continue
+		}
+		if err := removePod(pi); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		}
+		victims = append(victims, pi.Pod)
+		klog.V(5).InfoS("Pod is a potential preemption victim on node", "pod", klog.KObj(pi.Pod), "node", klog.KObj(nodeInfo.Node()))
+	}
+	return victims, 0, framework.NewStatus(framework.Success)
+}
+
+// EventsToRegister returns the possible events that may make a Pod
+// failed by this plugin schedulable: the deleted and updated pods free quota,
+// and the updated elastic quotas may raise it.
+func (pl *CapacityScheduling) EventsToRegister() []framework.ClusterEvent {
+	return []framework.ClusterEvent{
+		{Resource: framework.Pod, ActionType: framework.Delete | framework.Update},
+		{Resource: framework.ElasticQuota, ActionType: framework.Update},
+	}
+}
+
+// Resync reads the quota ConfigMap, so that an update of the quotas is reported
+// even when no pod is scheduled.
+func (pl *CapacityScheduling) Resync(ctx context.Context) {
+	pl.quotas(ctx)
+}
+
+// quotas returns the elastic quotas keyed by namespace. The quotas of the
+// ConfigMap take precedence over the ones of the args. It reports an
+// ElasticQuota update event when the ConfigMap was updated since last time.
+func (pl *CapacityScheduling) quotas(ctx context.Context) map[string]*config.ElasticQuota {
+	quotas := make(map[string]*config.ElasticQuota, len(pl.args.Quotas))
+	for i := range pl.args.Quotas {
+		q := &pl.args.Quotas[i]
+		quotas[q.Namespace] = q
+	}
+	if pl.configMapGetter == nil {
+		return quotas
+	}
+	cm, err := pl.configMapGetter.Get(ctx)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get the elastic quota ConfigMap", "configMap", klog.KRef(pl.args.QuotaConfigMapNamespace, pl.args.QuotaConfigMapName))
+		}
+		return quotas
+	}
+
+	pl.mu.Lock()
+	// The getter returns the same object until the ConfigMap is updated.
+	updated := pl.configMap != nil && cm != pl.configMap
+	if cm != pl.configMap {
+		pl.configMap = cm
+		pl.configMapQuotas = parseQuotas(cm)
+	}
+	for ns, q := range pl.configMapQuotas {
+		quotas[ns] = q
+	}
+	pl.mu.Unlock()
+	if updated {
+		pl.fh.ReportClusterEvent(elasticQuotaUpdate)
+	}
+	return quotas
+}
+
+// elasticQuotaSpec is the format of the values of the quota ConfigMap.
+type elasticQuotaSpec struct {
+	Min v1.ResourceList `json:"min,omitempty"`
+	Max v1.ResourceList `json:"max,omitempty"`
+}
+
+// parseQuotas returns the valid elastic quotas of the ConfigMap, keyed by
+// namespace. Invalid quotas are logged and ignored.
+func parseQuotas(cm *v1.ConfigMap) map[string]*config.ElasticQuota {
+	quotas := make(map[string]*config.ElasticQuota, len(cm.Data))
+	for ns, data := range cm.Data {
+		var spec elasticQuotaSpec
+		if err := yaml.UnmarshalStrict([]byte(data), &spec); err != nil {
+			klog.ErrorS(err, "Ignoring invalid elastic quota", "configMap", klog.KObj(cm), "namespace", ns)
+			continue
+		}
+		q := &config.ElasticQuota{Namespace: ns, Min: spec.Min, Max: spec.Max}
+		if errs := validation.ValidateElasticQuota(field.NewPath("data").Key(ns), q); len(errs) > 0 {
+			klog.ErrorS(errs.ToAggregate(), "Ignoring invalid elastic quota", "configMap", klog.KObj(cm), "namespace", ns)
+			continue
+		}
+		quotas[ns] = q
+	}
+	return quotas
+}
+
+// podRequest returns the resources requested by the pod.
+func podRequest(pod *v1.Pod) v1.ResourceList {
+	reqs, _ := resourcehelper.PodRequestsAndLimits(pod)
+	return reqs
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+	args := config.CapacitySchedulingArgs{}
+	if obj != nil {
+		a, ok := obj.(*config.CapacitySchedulingArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type CapacitySchedulingArgs, got %T", obj)
+		}
+		args = *a
+	}
+	if err := validation.ValidateCapacitySchedulingArgs(nil, &args); err != nil {
+		return nil, err
+	}
+	pl := &CapacityScheduling{
+		fh:        fh,
+		podLister: fh.SharedInformerFactory().Core().V1().Pods().Lister(),
+		args:      args,
+		reserved:  make(map[types.UID]*reservation),
+	}
+	if len(args.QuotaConfigMapName) != 0 {
+		pl.configMapGetter = helper.NewConfigMapGetter(fh.ClientSet(), args.QuotaConfigMapNamespace, args.QuotaConfigMapName, helper.ConfigMapRefreshInterval)
+	}
+	return pl, nil
+}
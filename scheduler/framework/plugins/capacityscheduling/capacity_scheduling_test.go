@@ -0,0 +1,410 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/helper"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/noderesources"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	internalqueue "k8s.io/kubernetes/pkg/scheduler/internal/queue"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func cpu(n string) v1.ResourceList {
+	return v1.ResourceList{v1.ResourceCPU: resource.MustParse(n)}
+}
+
+func cpuPod(namespace, name, req string) *st.PodWrapper {
+	return st.MakePod().Namespace(namespace).Name(name).UID(namespace + "/" + name).Req(map[v1.ResourceName]string{v1.ResourceCPU: req})
+}
+
+var defaultQuotas = []config.ElasticQuota{
+	{Namespace: "a", Min: cpu("2"), Max: cpu("4")},
+	{Namespace: "b", Min: cpu("2"), Max: cpu("4")},
+}
+
+// newFramework returns a framework running the CapacityScheduling and the
+// NodeResourcesFit plugins against a snapshot of the given pods and nodes. The
+// objects are the ones of the API server, which must include the pods to
+// reserve.
+func newFramework(ctx context.Context, t *testing.T, args *config.CapacitySchedulingArgs, pods []*v1.Pod, nodes []*v1.Node, objs ...runtime.Object) (framework.Framework, *CapacityScheduling) {
+	client := clientsetfake.NewSimpleClientset(objs...)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	var pl *CapacityScheduling
+	fwk, err := st.NewFramework(
+		[]st.RegisterPluginFunc{
+			st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			st.RegisterPluginAsExtensions(noderesources.FitName, frameworkruntime.FactoryAdapter(feature.Features{}, noderesources.NewFit), "Filter", "PreFilter"),
+			st.RegisterPluginAsExtensions(Name, func(_ runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+				p, err := New(args, fh)
+				if err == nil {
+					pl = p.(*CapacityScheduling)
+				}
+				return p, err
+			}, "PreFilter", "Reserve", "PostFilter"),
+			st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		},
+		"",
+		frameworkruntime.WithClientSet(client),
+		frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithPodNominator(internalqueue.NewPodNominator(informerFactory.Core().V1().Pods().Lister())),
+		frameworkruntime.WithSnapshotSharedLister(internalcache.NewSnapshot(pods, nodes)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+	return fwk, pl
+}
+
+func TestPreFilter(t *testing.T) {
+	nodes := []*v1.Node{st.MakeNode().Name("node").Obj()}
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		pods     []*v1.Pod
+		reserved []*v1.Pod
+		wantCode framework.Code
+	}{
+		{
+			name:     "namespace without quota",
+			pod:      cpuPod("c", "p", "10").Obj(),
+			wantCode: framework.Success,
+		},
+		{
+			name: "within min",
+			pod:  cpuPod("a", "p", "1").Obj(),
+			pods: []*v1.Pod{
+				cpuPod("a", "a1", "1").Node("node").Obj(),
+				cpuPod("b", "b1", "2").Node("node").Obj(),
+			},
+			wantCode: framework.Success,
+		},
+		{
+			name: "borrowing unused min of other namespaces",
+			pod:  cpuPod("a", "p", "1").Obj(),
+			pods: []*v1.Pod{
+				cpuPod("a", "a1", "2").Node("node").Obj(),
+				cpuPod("b", "b1", "1").Node("node").Obj(),
+			},
+			wantCode: framework.Success,
+		},
+		{
+			name: "no unused min left to borrow",
+			pod:  cpuPod("a", "p", "1").Obj(),
+			pods: []*v1.Pod{
+				cpuPod("a", "a1", "2").Node("node").Obj(),
+				cpuPod("b", "b1", "2").Node("node").Obj(),
+			},
+			wantCode: framework.Unschedulable,
+		},
+		{
+			name:     "exceeding max",
+			pod:      cpuPod("a", "p", "5").Obj(),
+			wantCode: framework.Unschedulable,
+		},
+		{
+			name: "reserved pods are accounted for",
+			pod:  cpuPod("a", "p", "1").Obj(),
+			pods: []*v1.Pod{
+				cpuPod("a", "a1", "2").Node("node").Obj(),
+			},
+			reserved: []*v1.Pod{
+				cpuPod("b", "b1", "2").Obj(),
+			},
+			wantCode: framework.Unschedulable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var objs []runtime.Object
+			for _, p := range tt.reserved {
+				objs = append(objs, p)
+			}
+			fwk, _ := newFramework(ctx, t, &config.CapacitySchedulingArgs{Quotas: defaultQuotas}, tt.pods, nodes, objs...)
+			for _, p := range tt.reserved {
+				state := framework.NewCycleState()
+				if status := fwk.RunPreFilterPlugins(ctx, state, p); !status.IsSuccess() {
+					t.Fatalf("Unexpected PreFilter status for reserved pod: %v", status)
+				}
+				if status := fwk.RunReservePluginsReserve(ctx, state, p, "node"); !status.IsSuccess() {
+					t.Fatalf("Unexpected Reserve status: %v", status)
+				}
+			}
+			if got := fwk.RunPreFilterPlugins(ctx, framework.NewCycleState(), tt.pod); got.Code() != tt.wantCode {
+				t.Errorf("PreFilter returned %v, want code %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestUnreserve(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	args := &config.CapacitySchedulingArgs{Quotas: []config.ElasticQuota{{Namespace: "a", Max: cpu("2")}}}
+	reserved := cpuPod("a", "reserved", "2").Obj()
+	fwk, _ := newFramework(ctx, t, args, nil, []*v1.Node{st.MakeNode().Name("node").Obj()}, reserved)
+
+	state := framework.NewCycleState()
+	if status := fwk.RunPreFilterPlugins(ctx, state, reserved); !status.IsSuccess() {
+		t.Fatalf("Unexpected PreFilter status: %v", status)
+	}
+	if status := fwk.RunReservePluginsReserve(ctx, state, reserved, "node"); !status.IsSuccess() {
+		t.Fatalf("Unexpected Reserve status: %v", status)
+	}
+	pod := cpuPod("a", "p", "1").Obj()
+	if status := fwk.RunPreFilterPlugins(ctx, framework.NewCycleState(), pod); status.Code() != framework.Unschedulable {
+		t.Errorf("Expected the pod to exceed the quota while the other pod is reserved, got %v", status)
+	}
+	fwk.RunReservePluginsUnreserve(ctx, state, reserved, "node")
+	if status := fwk.RunPreFilterPlugins(ctx, framework.NewCycleState(), pod); !status.IsSuccess() {
+		t.Errorf("Expected the pod to fit in the quota once the other pod is unreserved, got %v", status)
+	}
+}
+
+func TestDeletedReservedPod(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	args := &config.CapacitySchedulingArgs{Quotas: []config.ElasticQuota{{Namespace: "a", Max: cpu("2")}}}
+	reserved := cpuPod("a", "reserved", "2").Obj()
+	fwk, pl := newFramework(ctx, t, args, nil, []*v1.Node{st.MakeNode().Name("node").Obj()}, reserved)
+
+	state := framework.NewCycleState()
+	if status := fwk.RunPreFilterPlugins(ctx, state, reserved); !status.IsSuccess() {
+		t.Fatalf("Unexpected PreFilter status: %v", status)
+	}
+	if status := fwk.RunReservePluginsReserve(ctx, state, reserved, "node"); !status.IsSuccess() {
+		t.Fatalf("Unexpected Reserve status: %v", status)
+	}
+	// The pod is deleted before it is bound, without being unreserved.
+	if err := fwk.ClientSet().CoreV1().Pods("a").Delete(ctx, reserved.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.Poll(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		_, err := pl.podLister.Pods("a").Get(reserved.Name)
+		return apierrors.IsNotFound(err), nil
+	}); err != nil {
+		t.Fatalf("The pod lister didn't observe the deletion: %v", err)
+	}
+
+	pod := cpuPod("a", "p", "1").Obj()
+	if status := fwk.RunPreFilterPlugins(ctx, framework.NewCycleState(), pod); !status.IsSuccess() {
+		t.Errorf("Expected the pod to fit in the quota once the reserved pod is deleted, got %v", status)
+	}
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if len(pl.reserved) != 0 {
+		t.Errorf("Got %d pods in the ledger, want none", len(pl.reserved))
+	}
+}
+
+func TestConfigMapQuotas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "quotas", ResourceVersion: "1"},
+		Data: map[string]string{
+			"a": "max:\n  cpu: 8\n",
+			"c": "min:\n  cpu: 1\nmax:\n  cpu: 2\n",
+			"d": "min:\n  cpu: 4\nmax:\n  cpu: 2\n",
+			"e": "unknown: field\n",
+		},
+	}
+	args := &config.CapacitySchedulingArgs{
+		Quotas:                  defaultQuotas,
+		QuotaConfigMapNamespace: "kube-system",
+		QuotaConfigMapName:      "quotas",
+	}
+	client := clientsetfake.NewSimpleClientset(cm)
+	var events []framework.ClusterEvent
+	fh, err := frameworkruntime.NewFramework(nil, nil,
+		frameworkruntime.WithClientSet(client),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(client, 0)),
+		frameworkruntime.WithClusterEventHandler(func(evt framework.ClusterEvent) { events = append(events, evt) }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := New(args, fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pl := p.(*CapacityScheduling)
+	// Get the ConfigMap every time.
+	pl.configMapGetter = helper.NewConfigMapGetter(client, "kube-system", "quotas", 0)
+	cmpQuantity := cmp.Comparer(func(x, y resource.Quantity) bool { return x.Cmp(y) == 0 })
+
+	got := pl.quotas(ctx)
+	want := map[string]*config.ElasticQuota{
+		"a": {Namespace: "a", Max: cpu("8")},
+		"b": {Namespace: "b", Min: cpu("2"), Max: cpu("4")},
+		"c": {Namespace: "c", Min: cpu("1"), Max: cpu("2")},
+	}
+	if diff := cmp.Diff(want, got, cmpQuantity); diff != "" {
+		t.Errorf("Unexpected quotas (-want, +got):\n%s", diff)
+	}
+	pl.Resync(ctx)
+	if len(events) != 0 {
+		t.Errorf("Got events %v for an unchanged ConfigMap, want none", events)
+	}
+
+	cm.ResourceVersion = "2"
+	cm.Data = map[string]string{"c": "max:\n  cpu: 4\n"}
+	if _, err := client.CoreV1().ConfigMaps("kube-system").Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	// The resync reports the update without any pod being scheduled.
+	pl.Resync(ctx)
+	if diff := cmp.Diff([]framework.ClusterEvent{elasticQuotaUpdate}, events); diff != "" {
+		t.Errorf("Unexpected events after the update (-want, +got):\n%s", diff)
+	}
+	got = pl.quotas(ctx)
+	want = map[string]*config.ElasticQuota{
+		"a": {Namespace: "a", Min: cpu("2"), Max: cpu("4")},
+		"b": {Namespace: "b", Min: cpu("2"), Max: cpu("4")},
+		"c": {Namespace: "c", Max: cpu("4")},
+	}
+	if diff := cmp.Diff(want, got, cmpQuantity); diff != "" {
+		t.Errorf("Unexpected quotas after the update (-want, +got):\n%s", diff)
+	}
+	if len(events) != 1 {
+		t.Errorf("Got events %v, want the update to be reported once", events)
+	}
+}
+
+func TestSelectVictimsOnNode(t *testing.T) {
+	// The node is full. Namespace "b" borrows 1 CPU from namespace "a".
+	node := st.MakeNode().Name("node").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj()
+	pods := []*v1.Pod{
+		cpuPod("a", "a1", "1").Node("node").Priority(1).Obj(),
+		cpuPod("b", "b1", "1").Node("node").Priority(30).Obj(),
+		cpuPod("b", "b2", "1").Node("node").Priority(20).Obj(),
+		cpuPod("b", "b3", "1").Node("node").Priority(10).Obj(),
+	}
+	tests := []struct {
+		name        string
+		pod         *v1.Pod
+		wantVictims []string
+		wantCode    framework.Code
+	}{
+		{
+			name:        "pod within min preempts pods borrowing in other namespaces",
+			pod:         cpuPod("a", "p", "1").Obj(),
+			wantVictims: []string{"b3"},
+		},
+		{
+			name:        "borrowing pod preempts lower priority pods of its namespace",
+			pod:         cpuPod("b", "p", "1").Priority(25).Obj(),
+			wantVictims: []string{"b3"},
+		},
+		{
+			name:     "borrowing pod can't preempt other namespaces",
+			pod:      cpuPod("b", "p", "1").Priority(5).Obj(),
+			wantCode: framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:     "pod can't preempt its namespace within min",
+			pod:      cpuPod("a", "p", "2").Priority(5).Obj(),
+			wantCode: framework.UnschedulableAndUnresolvable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			fwk, pl := newFramework(ctx, t, &config.CapacitySchedulingArgs{Quotas: defaultQuotas}, pods, []*v1.Node{node})
+			state := framework.NewCycleState()
+			// The status is ignored, as the pod may not fit in its quota yet.
+			fwk.RunPreFilterPlugins(ctx, state, tt.pod)
+			nodeInfo, err := fwk.SnapshotSharedLister().NodeInfos().Get("node")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			victims, _, status := pl.SelectVictimsOnNode(ctx, state.Clone(), tt.pod, nodeInfo.Clone(), nil)
+			if status.Code() != tt.wantCode {
+				t.Fatalf("SelectVictimsOnNode returned %v, want code %v", status, tt.wantCode)
+			}
+			var got []string
+			for _, v := range victims {
+				got = append(got, v.Name)
+			}
+			if diff := cmp.Diff(tt.wantVictims, got); diff != "" {
+				t.Errorf("Unexpected victims (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	fh, err := frameworkruntime.NewFramework(nil, nil, frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		args    runtime.Object
+		wantErr bool
+	}{
+		{
+			name: "nil args",
+		},
+		{
+			name: "valid args",
+			args: &config.CapacitySchedulingArgs{Quotas: defaultQuotas},
+		},
+		{
+			name:    "invalid args",
+			args:    &config.CapacitySchedulingArgs{Quotas: []config.ElasticQuota{{Min: cpu("1")}}},
+			wantErr: true,
+		},
+		{
+			name:    "wrong args type",
+			args:    &config.CoschedulingArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.args, fh); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
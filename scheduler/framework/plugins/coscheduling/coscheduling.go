@@ -0,0 +1,345 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+)
+
+const (
+	// Name is the name of the plugin used in the plugin registry and configurations.
+	Name = names.Coscheduling
+
+	// PodGroupLabel is the label holding the name of the pod group a pod belongs
+	// to. Pod groups are namespaced.
+	PodGroupLabel = "pod-group.scheduling.sigs.k8s.io/name"
+	// PodGroupMinMemberLabel is the label holding the minimum number of pods of
+	// the group that must be placed for any of them to be bound.
+	PodGroupMinMemberLabel = "pod-group.scheduling.sigs.k8s.io/min-member"
+
+	// DefaultPermitWaitingTimeSeconds is the default value of
+	// CoschedulingArgs.PermitWaitingTimeSeconds.
+	DefaultPermitWaitingTimeSeconds = 60
+
+	// podGroupGCInterval is how often the enqueue times of the pod groups that
+	// have no pending members left are garbage collected.
+	podGroupGCInterval = time.Minute
+)
+
+// Coscheduling is a plugin that schedules the pods of a pod group all together,
+// or none of them. The members of a group are sorted next to each other in the
+// scheduling queue, and wait at Permit until at least minMember of them have
+// been placed, when they are all released together. The plugin must also be
+// enabled at PostBind, for the pod groups to be forgotten once scheduled.
+type Coscheduling struct {
+	handle            framework.Handle
+	podLister         corelisters.PodLister
+	permitWaitingTime time.Duration
+
+	// podGroupTimestamps holds the time the first member of each pod group was
+	// enqueued, keyed by namespaced pod group name. Less only adds entries, so
+	// that the order of the queued pods never changes. The entries of the
+	// groups with no pending members left are removed by Unreserve and
+	// PostBind.
+	podGroupTimestamps sync.Map
+
+	// mu guards lastGC.
+	mu     sync.Mutex
+	lastGC time.Time
+}
+
+// podGroupInfo is the queue ordering information of a pod group.
+type podGroupInfo struct {
+	// key is the namespaced name of the pod group.
+	key string
+	// timestamp is the time the first member of the group was enqueued. All the
+	// members of the group are sorted as if they had been enqueued at this time.
+	timestamp time.Time
+}
+
+var _ framework.QueueSortPlugin = &Coscheduling{}
+var _ framework.PreFilterPlugin = &Coscheduling{}
+var _ framework.ReservePlugin = &Coscheduling{}
+var _ framework.PermitPlugin = &Coscheduling{}
+var _ framework.PostBindPlugin = &Coscheduling{}
+var _ framework.EnqueueExtensions = &Coscheduling{}
+
+// Name returns name of the plugin.
+func (cs *Coscheduling) Name() string {
+	return Name
+}
+
+// Less sorts pods by priority, then by the time their pod group was enqueued,
+// and then by pod group name, so that the members of a pod group are popped one
+// after the other. Pods that don't belong to a pod group are their own group.
+func (cs *Coscheduling) Less(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+	p1 := corev1helpers.PodPriority(pInfo1.Pod)
+	p2 := corev1helpers.PodPriority(pInfo2.Pod)
+	if p1 != p2 {
+		return p1 > p2
+	}
+	g1 := cs.podGroupInfo(pInfo1)
+	g2 := cs.podGroupInfo(pInfo2)
+	if !g1.timestamp.Equal(g2.timestamp) {
+		return g1.timestamp.Before(g2.timestamp)
+	}
+	return g1.key < g2.key
+}
+
+// podGroupInfo returns the queue ordering information of the pod group of the
+// given pod, recording it if the pod is the first member of its group seen.
+func (cs *Coscheduling) podGroupInfo(pInfo *framework.QueuedPodInfo) podGroupInfo {
+	pod := pInfo.Pod
+	group, _, err := podGroup(pod)
+	if err != nil || group == "" {
+		return podGroupInfo{key: pod.Namespace + "/" + pod.Name, timestamp: pInfo.Timestamp}
+	}
+	key := pod.Namespace + "/" + group
+	timestamp, _ := cs.podGroupTimestamps.LoadOrStore(key, pInfo.InitialAttemptTimestamp)
+	return podGroupInfo{key: key, timestamp: timestamp.(time.Time)}
+}
+
+// gcPodGroups forgets the enqueue time of the pod groups that have no pending
+// members left, at most once every podGroupGCInterval.
+func (cs *Coscheduling) gcPodGroups() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	now := time.Now()
+	if now.Sub(cs.lastGC) < podGroupGCInterval {
+		return
+	}
+	cs.lastGC = now
+	cs.podGroupTimestamps.Range(func(k, _ interface{}) bool {
+		key := k.(string)
+		namespace, group, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return true
+		}
+		pods, err := cs.podLister.Pods(namespace).List(labels.SelectorFromSet(labels.Set{PodGroupLabel: group}))
+		if err != nil {
+			klog.ErrorS(err, "Failed to list pods of pod group", "podGroup", key)
+			return true
+		}
+		for _, p := range pods {
+			if p.Spec.NodeName == "" && p.DeletionTimestamp == nil {
+				return true
+			}
+		}
+		cs.podGroupTimestamps.Delete(key)
+		return true
+	})
+}
+
+// PreFilter rejects the members of pod groups that don't have enough pods yet
+// to ever be placed, and disables their host fallback.
+func (cs *Coscheduling) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+	group, minMember, err := podGroup(pod)
+	if err != nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+	}
+	if group == "" {
+		return nil
+	}
+	// Unreserving the pod rejects the other members of its group, which must
+	// be scheduled again, so the pod doesn't carry on alone on another node.
+	framework.DisableHostFallback(state)
+	pods, err := cs.podLister.Pods(pod.Namespace).List(labels.SelectorFromSet(labels.Set{PodGroupLabel: group}))
+	if err != nil {
+		return framework.AsStatus(fmt.Errorf("listing pods of pod group %q: %w", group, err))
+	}
+	total := 0
+	for _, p := range pods {
+		if p.DeletionTimestamp == nil {
+			total++
+		}
+	}
+	if total < minMember {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+			fmt.Sprintf("pod group %q has %d pods, fewer than its minimum of %d", group, total, minMember))
+	}
+	return nil
+}
+
+// PreFilterExtensions returns nil as the plugin doesn't need them.
+func (cs *Coscheduling) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Reserve is a no-op. It's only implemented so that Unreserve gets called.
+func (cs *Coscheduling) Reserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	return nil
+}
+
+// Unreserve rejects the members of the pod group that are waiting at Permit, so
+// that the group is either scheduled all together or not at all.
+func (cs *Coscheduling) Unreserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	cs.gcPodGroups()
+	group, _, err := podGroup(pod)
+	if err != nil || group == "" {
+		return
+	}
+	cs.handle.IterateOverWaitingPods(func(wp framework.WaitingPod) {
+		if p := wp.GetPod(); p.UID != pod.UID && inPodGroup(p, pod.Namespace, group) {
+			klog.V(3).InfoS("Rejecting waiting pod of pod group", "pod", klog.KObj(p), "podGroup", group, "rejectedPod", klog.KObj(pod))
+			wp.Reject(cs.Name(), fmt.Sprintf("pod %v of pod group %q was rejected", pod.Name, group))
+		}
+	})
+}
+
+// Permit holds the members of a pod group until at least minMember of them have
+// been placed, and then releases them all. While waiting, the pending members
+// of the group are moved to the active queue so that they're scheduled next.
+func (cs *Coscheduling) Permit(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (*framework.Status, time.Duration) {
+	group, minMember, err := podGroup(pod)
+	if err != nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error()), 0
+	}
+	if group == "" {
+		return nil, 0
+	}
+	placed, err := cs.countPlaced(pod, group)
+	if err != nil {
+		return framework.AsStatus(err), 0
+	}
+	// The pod itself is not in the snapshot yet.
+	if placed+1 < minMember {
+		klog.V(3).InfoS("Pod is waiting for the rest of its pod group", "pod", klog.KObj(pod), "podGroup", group, "placed", placed+1, "minMember", minMember)
+		cs.activateSiblings(state, pod, group)
+		return framework.NewStatus(framework.Wait, ""), cs.permitWaitingTime
+	}
+	cs.handle.IterateOverWaitingPods(func(wp framework.WaitingPod) {
+		if p := wp.GetPod(); inPodGroup(p, pod.Namespace, group) {
+			klog.V(3).InfoS("Allowing waiting pod of pod group", "pod", klog.KObj(p), "podGroup", group)
+			wp.Allow(cs.Name())
+		}
+	})
+	return nil, 0
+}
+
+// PostBind garbage collects the pod groups that are done scheduling.
+func (cs *Coscheduling) PostBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	cs.gcPodGroups()
+}
+
+// countPlaced returns the number of members of the pod group, other than the
+// given pod, that are bound or assumed in the snapshot.
+func (cs *Coscheduling) countPlaced(pod *v1.Pod, group string) (int, error) {
+	nodeInfos, err := cs.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return 0, err
+	}
+	placed := 0
+	for _, nodeInfo := range nodeInfos {
+		for _, pInfo := range nodeInfo.Pods {
+			if p := pInfo.Pod; p.UID != pod.UID && p.DeletionTimestamp == nil && inPodGroup(p, pod.Namespace, group) {
+				placed++
+			}
+		}
+	}
+	return placed, nil
+}
+
+// activateSiblings requests the pending members of the pod group to be moved
+// to the active queue at the end of the scheduling cycle.
+func (cs *Coscheduling) activateSiblings(state *framework.CycleState, pod *v1.Pod, group string) {
+	c, err := state.Read(framework.PodsToActivateKey)
+	if err != nil {
+		return
+	}
+	podsToActivate, ok := c.(*framework.PodsToActivate)
+	if !ok {
+		return
+	}
+	pods, err := cs.podLister.Pods(pod.Namespace).List(labels.SelectorFromSet(labels.Set{PodGroupLabel: group}))
+	if err != nil {
+		klog.ErrorS(err, "Failed to list pods of pod group", "podGroup", group)
+		return
+	}
+	podsToActivate.Lock()
+	defer podsToActivate.Unlock()
+	for _, p := range pods {
+		if p.UID != pod.UID && p.Spec.NodeName == "" && p.DeletionTimestamp == nil {
+			podsToActivate.Map[p.Namespace+"/"+p.Name] = p
+		}
+	}
+}
+
+// EventsToRegister returns the possible events that may make a pod rejected by
+// this plugin schedulable.
+func (cs *Coscheduling) EventsToRegister() []framework.ClusterEvent {
+	return []framework.ClusterEvent{
+		{Resource: framework.Pod, ActionType: framework.Add},
+	}
+}
+
+// podGroup returns the name and the minimum number of members of the pod group
+// of the given pod. The name is empty if the pod doesn't belong to a group.
+func podGroup(pod *v1.Pod) (string, int, error) {
+	group := pod.Labels[PodGroupLabel]
+	if group == "" {
+		return "", 0, nil
+	}
+	minMember, err := strconv.Atoi(pod.Labels[PodGroupMinMemberLabel])
+	if err != nil || minMember < 1 {
+		return "", 0, fmt.Errorf("invalid label %s=%q of pod group %q: must be a positive integer", PodGroupMinMemberLabel, pod.Labels[PodGroupMinMemberLabel], group)
+	}
+	return group, minMember, nil
+}
+
+// inPodGroup returns whether the pod belongs to the given pod group.
+func inPodGroup(pod *v1.Pod, namespace, group string) bool {
+	return pod.Namespace == namespace && pod.Labels[PodGroupLabel] == group
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args := config.CoschedulingArgs{}
+	if obj != nil {
+		a, ok := obj.(*config.CoschedulingArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type CoschedulingArgs, got %T", obj)
+		}
+		args = *a
+	}
+	if args.PermitWaitingTimeSeconds == 0 {
+		args.PermitWaitingTimeSeconds = DefaultPermitWaitingTimeSeconds
+	}
+	if err := validation.ValidateCoschedulingArgs(nil, &args); err != nil {
+		return nil, err
+	}
+	return &Coscheduling{
+		handle:            handle,
+		podLister:         handle.SharedInformerFactory().Core().V1().Pods().Lister(),
+		permitWaitingTime: time.Duration(args.PermitWaitingTimeSeconds) * time.Second,
+	}, nil
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coscheduling
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func groupPod(name, group, minMember string) *st.PodWrapper {
+	return st.MakePod().Namespace("ns").Name(name).UID(name).Label(PodGroupLabel, group).Label(PodGroupMinMemberLabel, minMember)
+}
+
+// newFramework returns a framework running the Coscheduling plugin against a
+// snapshot of the bound pods among the given ones. All of them are known to
+// the pod lister.
+func newFramework(ctx context.Context, t *testing.T, pods []*v1.Pod) (framework.Framework, *Coscheduling, *internalcache.Snapshot) {
+	var objs []runtime.Object
+	var boundPods []*v1.Pod
+	for _, p := range pods {
+		objs = append(objs, p)
+		if p.Spec.NodeName != "" {
+			boundPods = append(boundPods, p)
+		}
+	}
+	informerFactory := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(objs...), 0)
+	snapshot := internalcache.NewSnapshot(boundPods, []*v1.Node{st.MakeNode().Name("node").Obj()})
+	var pl *Coscheduling
+	fwk, err := st.NewFramework(
+		[]st.RegisterPluginFunc{
+			st.RegisterPluginAsExtensions(Name, func(obj runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+				p, err := New(obj, fh)
+				if err == nil {
+					pl = p.(*Coscheduling)
+				}
+				return p, err
+			}, "QueueSort", "PreFilter", "Reserve", "Permit", "PostBind"),
+			st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		},
+		"",
+		frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithSnapshotSharedLister(snapshot),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+	return fwk, pl, snapshot
+}
+
+func TestNew(t *testing.T) {
+	fh, err := frameworkruntime.NewFramework(nil, nil, frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		args     runtime.Object
+		wantWait time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			wantWait: DefaultPermitWaitingTimeSeconds * time.Second,
+		},
+		{
+			name:     "custom waiting time",
+			args:     &config.CoschedulingArgs{PermitWaitingTimeSeconds: 10},
+			wantWait: 10 * time.Second,
+		},
+		{
+			name:    "negative waiting time",
+			args:    &config.CoschedulingArgs{PermitWaitingTimeSeconds: -1},
+			wantErr: true,
+		},
+		{
+			name:    "wrong args type",
+			args:    &config.InterPodAffinityArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.args, fh)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && p.(*Coscheduling).permitWaitingTime != tt.wantWait {
+				t.Errorf("Got permit waiting time %v, want %v", p.(*Coscheduling).permitWaitingTime, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestLess(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, pl, _ := newFramework(ctx, t, nil)
+
+	now := time.Now()
+	queued := func(pod *v1.Pod, enqueued time.Time) *framework.QueuedPodInfo {
+		return &framework.QueuedPodInfo{PodInfo: framework.NewPodInfo(pod), Timestamp: enqueued, InitialAttemptTimestamp: enqueued}
+	}
+	pInfos := []*framework.QueuedPodInfo{
+		queued(st.MakePod().Namespace("ns").Name("single-1").Obj(), now.Add(time.Second)),
+		queued(groupPod("a-1", "a", "2").Obj(), now),
+		queued(groupPod("b-1", "b", "2").Obj(), now.Add(2*time.Second)),
+		queued(st.MakePod().Namespace("ns").Name("high").Priority(100).Obj(), now.Add(5*time.Second)),
+		queued(groupPod("a-2", "a", "2").Obj(), now.Add(3*time.Second)),
+		queued(groupPod("b-2", "b", "2").Obj(), now.Add(4*time.Second)),
+	}
+	// Record the groups in the order their first member was enqueued.
+	for _, pInfo := range pInfos {
+		pl.podGroupInfo(pInfo)
+	}
+	sort.SliceStable(pInfos, func(i, j int) bool {
+		return pl.Less(pInfos[i], pInfos[j])
+	})
+	var got []string
+	for _, pInfo := range pInfos {
+		got = append(got, pInfo.Pod.Name)
+	}
+	want := []string{"high", "a-1", "a-2", "single-1", "b-1", "b-2"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected queue order (-want, +got):\n%s", diff)
+	}
+}
+
+func TestPostBindForgetsScheduledPodGroups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduled := groupPod("a-1", "a", "1").Node("node").Obj()
+	pending := groupPod("b-1", "b", "1").Obj()
+	fwk, pl, _ := newFramework(ctx, t, []*v1.Pod{scheduled, pending})
+
+	now := time.Now()
+	for _, pod := range []*v1.Pod{scheduled, pending} {
+		pl.podGroupInfo(&framework.QueuedPodInfo{PodInfo: framework.NewPodInfo(pod), Timestamp: now, InitialAttemptTimestamp: now})
+	}
+	fwk.RunPostBindPlugins(ctx, framework.NewCycleState(), scheduled, "node")
+	if _, ok := pl.podGroupTimestamps.Load("ns/a"); ok {
+		t.Error("Expected the scheduled pod group to be forgotten")
+	}
+	if _, ok := pl.podGroupTimestamps.Load("ns/b"); !ok {
+		t.Error("Expected the pod group with a pending member to be kept")
+	}
+}
+
+func TestPreFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		pods     []*v1.Pod
+		wantCode framework.Code
+		// wantNoFallback is true if the pod must not fall back to other nodes.
+		wantNoFallback bool
+	}{
+		{
+			name:     "pod without group",
+			pod:      st.MakePod().Namespace("ns").Name("p").Obj(),
+			wantCode: framework.Success,
+		},
+		{
+			name: "group with enough pods",
+			pod:  groupPod("p1", "g", "2").Obj(),
+			pods: []*v1.Pod{
+				groupPod("p1", "g", "2").Obj(),
+				groupPod("p2", "g", "2").Obj(),
+			},
+			wantCode:       framework.Success,
+			wantNoFallback: true,
+		},
+		{
+			name: "group with too few pods",
+			pod:  groupPod("p1", "g", "3").Obj(),
+			pods: []*v1.Pod{
+				groupPod("p1", "g", "3").Obj(),
+				groupPod("p2", "g", "3").Obj(),
+				groupPod("p3", "g", "3").Terminating().Obj(),
+				st.MakePod().Namespace("other").Name("p4").Label(PodGroupLabel, "g").Obj(),
+			},
+			wantCode:       framework.UnschedulableAndUnresolvable,
+			wantNoFallback: true,
+		},
+		{
+			name:     "invalid min member",
+			pod:      groupPod("p1", "g", "zero").Obj(),
+			pods:     []*v1.Pod{groupPod("p1", "g", "zero").Obj()},
+			wantCode: framework.UnschedulableAndUnresolvable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			_, pl, _ := newFramework(ctx, t, tt.pods)
+			state := framework.NewCycleState()
+			if got := pl.PreFilter(ctx, state, tt.pod); got.Code() != tt.wantCode {
+				t.Errorf("PreFilter() returned %v, want code %v", got, tt.wantCode)
+			}
+			if got := framework.IsHostFallbackDisabled(state); got != tt.wantNoFallback {
+				t.Errorf("Got host fallback disabled %v, want %v", got, tt.wantNoFallback)
+			}
+		})
+	}
+}
+
+func TestPermit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bound := groupPod("p1", "g", "3").Node("node").Obj()
+	waiting := groupPod("p2", "g", "3").Obj()
+	last := groupPod("p3", "g", "3").Obj()
+	fwk, _, snapshot := newFramework(ctx, t, []*v1.Pod{bound, waiting, last})
+
+	// The second member waits for the third one, which is activated.
+	state := framework.NewCycleState()
+	podsToActivate := framework.NewPodsToActivate()
+	state.Write(framework.PodsToActivateKey, podsToActivate)
+	if status := fwk.RunPermitPlugins(ctx, state, waiting, "node"); status.Code() != framework.Wait {
+		t.Fatalf("Expected the second member to wait, got %v", status)
+	}
+	if diff := cmp.Diff(map[string]*v1.Pod{"ns/p3": last}, podsToActivate.Map); diff != "" {
+		t.Errorf("Unexpected pods to activate (-want, +got):\n%s", diff)
+	}
+
+	// Once the third member is placed, all of them are released.
+	assumed := waiting.DeepCopy()
+	assumed.Spec.NodeName = "node"
+	if err := snapshot.AssumePod(assumed); err != nil {
+		t.Fatal(err)
+	}
+	if status := fwk.RunPermitPlugins(ctx, framework.NewCycleState(), last, "node"); !status.IsSuccess() {
+		t.Fatalf("Expected the last member to be permitted, got %v", status)
+	}
+	if status := fwk.WaitOnPermit(ctx, waiting); !status.IsSuccess() {
+		t.Errorf("Expected the waiting member to be allowed, got %v", status)
+	}
+}
+
+func TestUnreserveRejectsWaitingMembers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	waiting := groupPod("p1", "g", "3").Obj()
+	rejected := groupPod("p2", "g", "3").Obj()
+	other := groupPod("p3", "other", "2").Obj()
+	fwk, _, _ := newFramework(ctx, t, []*v1.Pod{waiting, rejected, other})
+
+	for _, pod := range []*v1.Pod{waiting, other} {
+		if status := fwk.RunPermitPlugins(ctx, framework.NewCycleState(), pod, "node"); status.Code() != framework.Wait {
+			t.Fatalf("Expected pod %s to wait, got %v", pod.Name, status)
+		}
+	}
+	fwk.RunReservePluginsUnreserve(ctx, framework.NewCycleState(), rejected, "node")
+	if status := fwk.WaitOnPermit(ctx, waiting); status.Code() != framework.Unschedulable {
+		t.Errorf("Expected the waiting member to be rejected, got %v", status)
+	}
+	if wp := fwk.GetWaitingPod(other.UID); wp == nil {
+		t.Error("Expected the member of another group to keep waiting")
+	}
+}
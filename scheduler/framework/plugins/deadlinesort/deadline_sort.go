@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadlinesort
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
+)
+
+const (
+	// Name is the name of the plugin used in the plugin registry and configurations.
+	Name = names.DeadlineSort
+
+	// DeadlineAnnotation is the annotation holding the time, in RFC 3339
+	// format, by which the pod should be scheduled.
+	DeadlineAnnotation = "scheduling.alpha.kubernetes.io/deadline"
+	// SLOClassAnnotation is the annotation holding the name of the scheduling
+	// SLO class of the pod. It's ignored if the pod has a DeadlineAnnotation.
+	SLOClassAnnotation = "scheduling.alpha.kubernetes.io/slo-class"
+
+	// gcInterval is how often the pods reported to have missed their deadline
+	// are garbage collected.
+	gcInterval = time.Minute
+	// reportedExpiration is how long a pod is remembered to have been reported
+	// after the last time it was seen missing its deadline.
+	reportedExpiration = 15 * time.Minute
+)
+
+// DeadlineSort is a plugin that sorts pods by their scheduling deadline, so
+// that latency-critical pods are scheduled first, and reports the pods that
+// are still pending past their deadline.
+type DeadlineSort struct {
+	handle  framework.Handle
+	budgets map[string]time.Duration
+
+	mu sync.Mutex
+	// reported holds the pods that were reported to have missed their deadline,
+	// with the last time they were seen missing it.
+	reported map[types.UID]time.Time
+	lastGC   time.Time
+}
+
+var _ framework.QueueSortPlugin = &DeadlineSort{}
+var _ framework.PreFilterPlugin = &DeadlineSort{}
+
+// Name returns name of the plugin.
+func (pl *DeadlineSort) Name() string {
+	return Name
+}
+
+// Less sorts pods with a deadline before pods without one, and then by
+// earliest deadline, priority and the time they were queued at.
+func (pl *DeadlineSort) Less(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+	d1, _, ok1 := pl.deadline(pInfo1.Pod)
+	d2, _, ok2 := pl.deadline(pInfo2.Pod)
+	if ok1 != ok2 {
+		return ok1
+	}
+	if ok1 && !d1.Equal(d2) {
+		return d1.Before(d2)
+	}
+	p1 := corev1helpers.PodPriority(pInfo1.Pod)
+	p2 := corev1helpers.PodPriority(pInfo2.Pod)
+	return (p1 > p2) || (p1 == p2 && pInfo1.Timestamp.Before(pInfo2.Timestamp))
+}
+
+// PreFilter reports the pod the first time it's seen past its deadline outside
+// of dry runs.
+func (pl *DeadlineSort) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+	deadline, class, ok := pl.deadline(pod)
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	if !now.After(deadline) {
+		return nil
+	}
+	if state.IsDryRun() || pl.markReported(pod.UID, now) {
+		return nil
+	}
+	klog.V(4).InfoS("Pod missed its scheduling deadline", "pod", klog.KObj(pod), "deadline", deadline, "sloClass", class)
+	metrics.PodSchedulingDeadlineMissed.WithLabelValues(class).Inc()
+	if pl.handle != nil {
+		pl.handle.EventRecorder().Eventf(pod, nil, v1.EventTypeWarning, "SchedulingDeadlineMissed", "Scheduling",
+			"Pod is still pending %v after its scheduling deadline %v", now.Sub(deadline).Round(time.Second), deadline.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// PreFilterExtensions returns nil as the plugin doesn't depend on other pods.
+func (pl *DeadlineSort) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// markReported marks the pod as reported, returning whether it already was.
+func (pl *DeadlineSort) markReported(uid types.UID, now time.Time) bool {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if now.Sub(pl.lastGC) >= gcInterval {
+		pl.lastGC = now
+		for u, t := range pl.reported {
+			if now.Sub(t) > reportedExpiration {
+				delete(pl.reported, u)
+			}
+		}
+	}
+	_, ok := pl.reported[uid]
+	pl.reported[uid] = now
+	return ok
+}
+
+// deadline returns the scheduling deadline of the pod and its SLO class, if
+// any. Malformed deadlines and unknown classes are ignored.
+func (pl *DeadlineSort) deadline(pod *v1.Pod) (time.Time, string, bool) {
+	if v, ok := pod.Annotations[DeadlineAnnotation]; ok {
+		if d, err := time.Parse(time.RFC3339, v); err == nil {
+			return d, "", true
+		}
+		klog.V(5).InfoS("Ignoring malformed scheduling deadline", "pod", klog.KObj(pod), "deadline", v)
+	}
+	if class, ok := pod.Annotations[SLOClassAnnotation]; ok {
+		if budget, ok := pl.budgets[class]; ok {
+			return pod.CreationTimestamp.Add(budget), class, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	args := config.DeadlineSortArgs{}
+	if obj != nil {
+		a, ok := obj.(*config.DeadlineSortArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type DeadlineSortArgs, got %T", obj)
+		}
+		args = *a
+	}
+	if err := validation.ValidateDeadlineSortArgs(nil, &args); err != nil {
+		return nil, err
+	}
+	budgets := make(map[string]time.Duration, len(args.SLOClasses))
+	for _, c := range args.SLOClasses {
+		budgets[c.Name] = c.Budget.Duration
+	}
+	return &DeadlineSort{
+		handle:   h,
+		budgets:  budgets,
+		reported: make(map[types.UID]time.Time),
+	}, nil
+}
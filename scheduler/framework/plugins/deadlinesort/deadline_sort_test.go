@@ -0,0 +1,230 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadlinesort
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	internalqueue "k8s.io/kubernetes/pkg/scheduler/internal/queue"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var testArgs = &config.DeadlineSortArgs{
+	SLOClasses: []config.SLOClass{
+		{Name: "interactive", Budget: metav1.Duration{Duration: 10 * time.Second}},
+		{Name: "batch", Budget: metav1.Duration{Duration: time.Hour}},
+	},
+}
+
+func withDeadline(p *st.PodWrapper, deadline time.Time) *st.PodWrapper {
+	return p.Annotation(DeadlineAnnotation, deadline.Format(time.RFC3339))
+}
+
+func TestDeadlineQueueOrder(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	created := metav1.NewTime(now)
+	pod := func(name string) *st.PodWrapper {
+		return st.MakePod().Namespace("default").Name(name).UID(name)
+	}
+	tests := []struct {
+		name string
+		pods []*v1.Pod
+		want []string
+	}{
+		{
+			name: "earliest deadline first",
+			pods: []*v1.Pod{
+				withDeadline(pod("late"), now.Add(time.Minute)).Obj(),
+				withDeadline(pod("early"), now.Add(time.Second)).Obj(),
+				withDeadline(pod("middle"), now.Add(30*time.Second)).Obj(),
+			},
+			want: []string{"early", "middle", "late"},
+		},
+		{
+			name: "pods without deadline come last",
+			pods: []*v1.Pod{
+				pod("none").Priority(100).Obj(),
+				withDeadline(pod("deadline"), now.Add(time.Hour)).Obj(),
+				pod("malformed").Annotation(DeadlineAnnotation, "tomorrow").Obj(),
+				pod("unknown-class").Annotation(SLOClassAnnotation, "unknown").Obj(),
+			},
+			want: []string{"deadline", "none", "malformed", "unknown-class"},
+		},
+		{
+			name: "SLO classes and explicit deadlines",
+			pods: []*v1.Pod{
+				pod("batch").Annotation(SLOClassAnnotation, "batch").CreationTimestamp(created).Obj(),
+				pod("interactive").Annotation(SLOClassAnnotation, "interactive").CreationTimestamp(created).Obj(),
+				withDeadline(pod("explicit"), now.Add(time.Minute)).Annotation(SLOClassAnnotation, "interactive").Obj(),
+			},
+			want: []string{"interactive", "explicit", "batch"},
+		},
+		{
+			name: "priority breaks ties",
+			pods: []*v1.Pod{
+				withDeadline(pod("low"), now.Add(time.Minute)).Priority(1).Obj(),
+				withDeadline(pod("high"), now.Add(time.Minute)).Priority(10).Obj(),
+				withDeadline(pod("low2"), now.Add(time.Minute)).Priority(1).Obj(),
+			},
+			want: []string{"high", "low", "low2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			pl, err := New(testArgs, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := testingclock.NewFakeClock(now)
+			q := internalqueue.NewTestQueue(ctx, pl.(framework.QueueSortPlugin).Less, internalqueue.WithClock(c))
+			for _, p := range tt.pods {
+				c.Step(time.Second)
+				if err := q.Add(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var got []string
+			for range tt.pods {
+				pInfo, err := q.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, pInfo.Pod.Name)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unexpected pop order (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// fakeHandle is a framework.Handle that only provides an event recorder.
+type fakeHandle struct {
+	framework.Handle
+	recorder events.EventRecorder
+}
+
+func (h *fakeHandle) EventRecorder() events.EventRecorder {
+	return h.recorder
+}
+
+func TestPreFilter(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		pod       *v1.Pod
+		dryRun    bool
+		wantEvent bool
+	}{
+		{
+			name: "no deadline",
+			pod:  st.MakePod().Name("p").UID("p").Obj(),
+		},
+		{
+			name: "deadline ahead",
+			pod:  withDeadline(st.MakePod().Name("p").UID("p"), now.Add(time.Hour)).Obj(),
+		},
+		{
+			name:      "explicit deadline missed",
+			pod:       withDeadline(st.MakePod().Name("p").UID("p"), now.Add(-time.Minute)).Obj(),
+			wantEvent: true,
+		},
+		{
+			name: "SLO class deadline missed",
+			pod: st.MakePod().Name("p").UID("p").Annotation(SLOClassAnnotation, "interactive").
+				CreationTimestamp(metav1.NewTime(now.Add(-time.Minute))).Obj(),
+			wantEvent: true,
+		},
+		{
+			name:   "deadline missed in a dry run",
+			pod:    withDeadline(st.MakePod().Name("p").UID("p"), now.Add(-time.Minute)).Obj(),
+			dryRun: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := events.NewFakeRecorder(10)
+			pl, err := New(testArgs, &fakeHandle{recorder: recorder})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ds := pl.(*DeadlineSort)
+			// Run two scheduling attempts, the pod is only reported once.
+			for i := 0; i < 2; i++ {
+				state := framework.NewCycleState()
+				state.SetDryRun(tt.dryRun)
+				if s := ds.PreFilter(context.Background(), state, tt.pod); !s.IsSuccess() {
+					t.Fatalf("Unexpected status: %v", s)
+				}
+			}
+			wantEvents := 0
+			if tt.wantEvent {
+				wantEvents = 1
+			}
+			if got := len(recorder.Events); got != wantEvents {
+				t.Errorf("Got %d events, want %d", got, wantEvents)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    runtime.Object
+		wantErr bool
+	}{
+		{
+			name: "nil args",
+		},
+		{
+			name: "valid args",
+			args: testArgs,
+		},
+		{
+			name: "invalid args",
+			args: &config.DeadlineSortArgs{
+				SLOClasses: []config.SLOClass{{Name: "interactive"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "wrong args type",
+			args:    &config.FairShareArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.args, nil); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -19,7 +19,6 @@ package defaultpreemption
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"sort"
 
 	v1 "k8s.io/api/core/v1"
@@ -57,6 +56,7 @@ type DefaultPreemption struct {
 }
 
 var _ framework.PostFilterPlugin = &DefaultPreemption{}
+var _ preemption.Simulator = &DefaultPreemption{}
 
 // Name returns name of the plugin. It is used in logs, etc.
 func (pl *DefaultPreemption) Name() string {
@@ -87,7 +87,17 @@ func (pl *DefaultPreemption) PostFilter(ctx context.Context, state *framework.Cy
 		metrics.PreemptionAttempts.Inc()
 	}()
 
-	pe := preemption.Evaluator{
+	return pl.evaluator(state).Preempt(ctx, pod, m)
+}
+
+// SimulatePreemption implements preemption.Simulator.
+func (pl *DefaultPreemption) SimulatePreemption(ctx context.Context, state *framework.CycleState, pod *v1.Pod, m framework.NodeToStatusMap) (*preemption.Simulation, *framework.Status) {
+	return pl.evaluator(state).Simulate(ctx, pod, m)
+}
+
+// evaluator returns the preemption evaluator of the given scheduling cycle.
+func (pl *DefaultPreemption) evaluator(state *framework.CycleState) *preemption.Evaluator {
+	pe := &preemption.Evaluator{
 		PluginName: names.DefaultPreemption,
 		Handler:    pl.fh,
 		PodLister:  pl.podLister,
@@ -95,7 +105,7 @@ func (pl *DefaultPreemption) PostFilter(ctx context.Context, state *framework.Cy
 		State:      state,
 		Interface:  pl,
 	}
-	return pe.Preempt(ctx, pod, m)
+	return pe
 }
 
 // calculateNumCandidates returns the number of candidates the FindCandidates
@@ -114,9 +124,11 @@ func (pl *DefaultPreemption) calculateNumCandidates(numNodes int32) int32 {
 }
 
 // GetOffsetAndNumCandidates chooses a random offset and calculates the number
-// of candidates that should be shortlisted for dry running preemption.
+// of candidates that should be shortlisted for dry running preemption. The
+// offset is drawn from the framework's source of randomness so that seeded
+// schedulers pick the same candidates for identical inputs.
 func (pl *DefaultPreemption) GetOffsetAndNumCandidates(numNodes int32) (int32, int32) {
-	return rand.Int31n(numNodes), pl.calculateNumCandidates(numNodes)
+	return pl.fh.Rand().Int31n(numNodes), pl.calculateNumCandidates(numNodes)
 }
 
 // This function is not applicable for out-of-tree preemption plugins that exercise
@@ -1084,6 +1084,8 @@ func TestDryRunPreemption(t *testing.T) {
 				frameworkruntime.WithSnapshotSharedLister(snapshot),
 				frameworkruntime.WithInformerFactory(informerFactory),
 				frameworkruntime.WithParallelism(parallelism),
+				// Using 4 as a seed source to test getOffsetAndNumCandidates() deterministically.
+				frameworkruntime.WithRand(rand.New(rand.NewSource(4))),
 			)
 			if err != nil {
 				t.Fatal(err)
@@ -1112,10 +1114,6 @@ func TestDryRunPreemption(t *testing.T) {
 				args:      *tt.args,
 			}
 
-			// Using 4 as a seed source to test getOffsetAndNumCandidates() deterministically.
-			// However, we need to do it after informerFactory.WaitforCacheSync() which might
-			// set a seed.
-			rand.Seed(4)
 			var prevNumFilterCalled int32
 			for cycle, pod := range tt.testPods {
 				state := framework.NewCycleState()
@@ -1315,7 +1313,6 @@ func TestSelectBestCandidate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rand.Seed(4)
 			nodes := make([]*v1.Node, len(tt.nodeNames))
 			for i, nodeName := range tt.nodeNames {
 				nodes[i] = st.MakeNode().Name(nodeName).Capacity(veryLargeRes).Obj()
@@ -1338,6 +1335,7 @@ func TestSelectBestCandidate(t *testing.T) {
 				"",
 				frameworkruntime.WithPodNominator(internalqueue.NewPodNominator(informerFactory.Core().V1().Pods().Lister())),
 				frameworkruntime.WithSnapshotSharedLister(snapshot),
+				frameworkruntime.WithRand(rand.New(rand.NewSource(4))),
 			)
 			if err != nil {
 				t.Fatal(err)
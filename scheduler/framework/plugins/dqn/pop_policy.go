@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dqn
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// DefaultPickTimeout is the default timeout of the requests to the agent.
+const DefaultPickTimeout = 100 * time.Millisecond
+
+// PickRequest is the request sent to the agent to pick the pod to schedule
+// next.
+type PickRequest struct {
+	// Pods are the candidate pods, in the order of the QueueSort plugin.
+	Pods []PickPod `json:"pods"`
+	// Nodes is the state of the cluster as of the last scheduling cycle.
+	Nodes []PickNode `json:"nodes"`
+}
+
+// PickPod describes a candidate pod to the agent.
+type PickPod struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// MilliCPU, Memory and EphemeralStorage are the requests of the pod.
+	MilliCPU         int64 `json:"milliCPU"`
+	Memory           int64 `json:"memory"`
+	EphemeralStorage int64 `json:"ephemeralStorage"`
+	// Attempts is the number of times the pod was tried to be scheduled.
+	Attempts int `json:"attempts"`
+}
+
+// PickNode describes a node to the agent.
+type PickNode struct {
+	Name        string             `json:"name"`
+	Allocatable framework.Resource `json:"allocatable"`
+	Requested   framework.Resource `json:"requested"`
+	Pods        int                `json:"pods"`
+}
+
+// NewPickPod describes the pod to the agent.
+func NewPickPod(pod *v1.Pod, attempts int) PickPod {
+	reqs, _ := resourcehelper.PodRequestsAndLimits(pod)
+	return PickPod{
+		Name:             pod.Name,
+		Namespace:        pod.Namespace,
+		MilliCPU:         reqs.Cpu().MilliValue(),
+		Memory:           reqs.Memory().Value(),
+		EphemeralStorage: reqs.StorageEphemeral().Value(),
+		Attempts:         attempts,
+	}
+}
+
+// NewPickNodes describes the nodes to the agent.
+func NewPickNodes(nodeInfos []*framework.NodeInfo) []PickNode {
+	nodes := make([]PickNode, 0, len(nodeInfos))
+	for _, n := range nodeInfos {
+		if n.Node() == nil {
+			continue
+		}
+		nodes = append(nodes, PickNode{
+			Name:        n.Node().Name,
+			Allocatable: *n.Allocatable,
+			Requested:   *n.Requested,
+			Pods:        len(n.Pods),
+		})
+	}
+	return nodes
+}
+
+// PopPolicy is a framework.PopPolicy that lets the RL agent pick which pod is
+// scheduled next. Following the convention of the agent's other endpoints, the
+// agent replies with the name of the picked pod, optionally prefixed by its
+// namespace.
+type PopPolicy struct {
+	url    string
+	client *http.Client
+}
+
+var _ framework.PopPolicy = &PopPolicy{}
+
+// NewPopPolicy returns a PopPolicy sending requests to the agent at url, such
+// as "http://agent:1234/pick", with the given timeout, or DefaultPickTimeout
+// if it is 0.
+func NewPopPolicy(url string, timeout time.Duration) *PopPolicy {
+	if timeout == 0 {
+		timeout = DefaultPickTimeout
+	}
+	return &PopPolicy{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Pick sends the candidates and the state of the cluster to the agent and
+// returns the index of the pod it picked.
+func (pp *PopPolicy) Pick(candidates []*framework.QueuedPodInfo, nodes framework.NodeInfoLister) (int, error) {
+	req := PickRequest{Pods: make([]PickPod, 0, len(candidates))}
+	for _, pInfo := range candidates {
+		req.Pods = append(req.Pods, NewPickPod(pInfo.Pod, pInfo.Attempts))
+	}
+	if nodes != nil {
+		nodeInfos, err := nodes.List()
+		if err != nil {
+			return 0, fmt.Errorf("listing nodes: %w", err)
+		}
+		req.Nodes = NewPickNodes(nodeInfos)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := pp.client.Post(pp.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("agent %v replied with status %v", pp.url, resp.Status)
+	}
+	picked, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	name := strings.TrimSpace(string(picked))
+	for i, pInfo := range candidates {
+		if name == pInfo.Pod.Name || name == pInfo.Pod.Namespace+"/"+pInfo.Pod.Name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("agent %v picked unknown pod %q", pp.url, name)
+}
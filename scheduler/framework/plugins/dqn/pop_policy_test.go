@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dqn
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func TestPopPolicyPick(t *testing.T) {
+	candidates := []*framework.QueuedPodInfo{
+		{PodInfo: framework.NewPodInfo(st.MakePod().Namespace("ns").Name("p0").Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj())},
+		{PodInfo: framework.NewPodInfo(st.MakePod().Namespace("ns").Name("p1").Obj()), Attempts: 2},
+	}
+	nodes := []*v1.Node{st.MakeNode().Name("n1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj()}
+	pods := []*v1.Pod{st.MakePod().Name("running").Node("n1").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj()}
+	snapshot := internalcache.NewSnapshot(pods, nodes)
+
+	tests := []struct {
+		name    string
+		reply   string
+		status  int
+		want    int
+		wantErr bool
+	}{
+		{
+			name:  "pod name",
+			reply: "p1",
+			want:  1,
+		},
+		{
+			name:  "namespaced pod name",
+			reply: "ns/p0\n",
+			want:  0,
+		},
+		{
+			name:    "unknown pod",
+			reply:   "p2",
+			wantErr: true,
+		},
+		{
+			name:    "agent error",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got PickRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("Decoding request: %v", err)
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				fmt.Fprint(w, tt.reply)
+			}))
+			defer server.Close()
+
+			i, err := NewPopPolicy(server.URL, time.Second).Pick(candidates, snapshot.NodeInfos())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Pick() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && i != tt.want {
+				t.Errorf("Pick() = %v, want %v", i, tt.want)
+			}
+			wantPods := []PickPod{
+				{Name: "p0", Namespace: "ns", MilliCPU: 500},
+				{Name: "p1", Namespace: "ns", Attempts: 2},
+			}
+			if diff := cmp.Diff(wantPods, got.Pods); diff != "" {
+				t.Errorf("Unexpected pods sent to the agent (-want, +got):\n%s", diff)
+			}
+			if len(got.Nodes) != 1 || got.Nodes[0].Requested.MilliCPU != 1000 || got.Nodes[0].Allocatable.MilliCPU != 4000 {
+				t.Errorf("Unexpected nodes sent to the agent: %+v", got.Nodes)
+			}
+		})
+	}
+}
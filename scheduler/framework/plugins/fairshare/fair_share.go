@@ -0,0 +1,272 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fairshare
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// Name is the name of the plugin used in the plugin registry and configurations.
+	Name = names.FairShare
+
+	// DefaultWeight is the default value of FairShareArgs.DefaultWeight.
+	DefaultWeight = 1
+	// DefaultHalfLifeSeconds is the default value of FairShareArgs.HalfLifeSeconds.
+	DefaultHalfLifeSeconds = 300
+
+	// maxHalfLives is the number of half-lives after which the counters are
+	// renormalized, so that they don't overflow.
+	maxHalfLives = 256
+	// minCounter is the value under which the counter of a tenant is forgotten.
+	minCounter = 1e-3
+
+	// gcInterval is how often the counters of tenants are garbage collected.
+	gcInterval = time.Minute
+)
+
+// FairShare is a plugin that sorts pods by priority, and then shares the
+// scheduled pods between tenants with weighted fair queuing.
+//
+// Each tenant has an exponentially decaying counter of the pods scheduled for
+// it: a pod is charged to its tenant when it's reserved on a node, and refunded
+// if it's unreserved. Pods of the same priority are sorted by the share of
+// their tenant, that is its counter divided by its weight, so that a burst of
+// pods of one tenant is interleaved with the pods of the other tenants. The
+// shares are only updated by Reserve and Unreserve, and Less just reads them,
+// so the plugin must also be enabled at Reserve. As the queue
+// isn't sorted again when the shares change, the order of the pods already
+// queued follows the shares only approximately.
+type FairShare struct {
+	tenantLabel   string
+	weights       map[string]float64
+	defaultWeight float64
+	halfLife      time.Duration
+	clock         clock.PassiveClock
+
+	// shares holds the shares of the tenants, as a map[string]float64 that is
+	// replaced, never modified, when a counter changes.
+	shares atomic.Value
+
+	mu sync.Mutex
+	// epoch is the time the counters are scaled relative to. A counter of
+	// value v at time t is stored as v*2^((t-epoch)/halfLife), so that stored
+	// values keep their order without being updated as they decay.
+	epoch    time.Time
+	counters map[string]float64
+	// charges are the amounts the reserved pods were charged to their tenant,
+	// until they are bound or unreserved, or the charges decayed enough to be
+	// forgotten.
+	charges map[types.UID]charge
+	lastGC  time.Time
+}
+
+// charge is the amount a pod was charged to its tenant.
+type charge struct {
+	tenant string
+	amount float64
+}
+
+var _ framework.QueueSortPlugin = &FairShare{}
+var _ framework.ReservePlugin = &FairShare{}
+var _ framework.PostBindPlugin = &FairShare{}
+
+// Name returns name of the plugin.
+func (fs *FairShare) Name() string {
+	return Name
+}
+
+// Less sorts pods by priority, then by the share of their tenant, and then by
+// the time they were queued at.
+func (fs *FairShare) Less(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+	p1 := corev1helpers.PodPriority(pInfo1.Pod)
+	p2 := corev1helpers.PodPriority(pInfo2.Pod)
+	if p1 != p2 {
+		return p1 > p2
+	}
+	shares := fs.shares.Load().(map[string]float64)
+	s1 := fs.share(shares, fs.tenant(pInfo1.Pod))
+	s2 := fs.share(shares, fs.tenant(pInfo2.Pod))
+	if s1 != s2 {
+		return s1 < s2
+	}
+	return pInfo1.Timestamp.Before(pInfo2.Timestamp)
+}
+
+// share returns the share of the tenant among the given counters, relative to
+// the current epoch.
+func (fs *FairShare) share(counters map[string]float64, tenant string) float64 {
+	return counters[tenant] / fs.weight(tenant)
+}
+
+// Reserve charges the pod to its tenant.
+func (fs *FairShare) Reserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	now := fs.clock.Now()
+	fs.gc(now)
+	if fs.epoch.IsZero() {
+		fs.epoch = now
+	}
+	halfLives := float64(now.Sub(fs.epoch)) / float64(fs.halfLife)
+	if halfLives > maxHalfLives {
+		fs.renormalize(halfLives)
+		halfLives = 0
+	}
+	c := charge{tenant: fs.tenant(pod), amount: math.Exp2(halfLives)}
+	fs.counters[c.tenant] += c.amount
+	fs.charges[pod.UID] = c
+	fs.publish()
+	return nil
+}
+
+// Unreserve refunds the tenant of the pod.
+func (fs *FairShare) Unreserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	c, ok := fs.charges[pod.UID]
+	if !ok {
+		return
+	}
+	delete(fs.charges, pod.UID)
+	fs.counters[c.tenant] = math.Max(fs.counters[c.tenant]-c.amount, 0)
+	fs.publish()
+}
+
+// PostBind forgets the charge of the pod, which can't be unreserved anymore.
+// Without it, the charges of bound pods are only forgotten once decayed.
+func (fs *FairShare) PostBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	delete(fs.charges, pod.UID)
+}
+
+// publish makes the current counters the shares read by Less. fs.mu must be
+// held.
+func (fs *FairShare) publish() {
+	shares := make(map[string]float64, len(fs.counters))
+	for tenant, c := range fs.counters {
+		shares[tenant] = c
+	}
+	fs.shares.Store(shares)
+}
+
+// renormalize moves the epoch forward by the given number of half-lives,
+// scaling the counters down accordingly.
+func (fs *FairShare) renormalize(halfLives float64) {
+	scale := math.Exp2(-halfLives)
+	fs.epoch = fs.epoch.Add(time.Duration(halfLives * float64(fs.halfLife)))
+	for tenant, c := range fs.counters {
+		fs.counters[tenant] = c * scale
+	}
+	for uid, c := range fs.charges {
+		c.amount *= scale
+		fs.charges[uid] = c
+	}
+}
+
+// gc forgets the counters of the tenants that had no pod scheduled for long,
+// and the charges too small to matter if refunded.
+func (fs *FairShare) gc(now time.Time) {
+	if now.Sub(fs.lastGC) < gcInterval {
+		return
+	}
+	fs.lastGC = now
+	if fs.epoch.IsZero() {
+		return
+	}
+	scale := math.Exp2(-float64(now.Sub(fs.epoch)) / float64(fs.halfLife))
+	for tenant, c := range fs.counters {
+		if c*scale < minCounter {
+			delete(fs.counters, tenant)
+		}
+	}
+	for uid, c := range fs.charges {
+		if c.amount*scale < minCounter {
+			delete(fs.charges, uid)
+		}
+	}
+}
+
+// tenant returns the tenant of the pod.
+func (fs *FairShare) tenant(pod *v1.Pod) string {
+	if len(fs.tenantLabel) != 0 {
+		if tenant, ok := pod.Labels[fs.tenantLabel]; ok {
+			return tenant
+		}
+	}
+	return pod.Namespace
+}
+
+// weight returns the weight of the tenant.
+func (fs *FairShare) weight(tenant string) float64 {
+	if w, ok := fs.weights[tenant]; ok {
+		return w
+	}
+	return fs.defaultWeight
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+	args := config.FairShareArgs{}
+	if obj != nil {
+		a, ok := obj.(*config.FairShareArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type FairShareArgs, got %T", obj)
+		}
+		args = *a
+	}
+	if args.DefaultWeight == 0 {
+		args.DefaultWeight = DefaultWeight
+	}
+	if args.HalfLifeSeconds == 0 {
+		args.HalfLifeSeconds = DefaultHalfLifeSeconds
+	}
+	if err := validation.ValidateFairShareArgs(nil, &args); err != nil {
+		return nil, err
+	}
+	weights := make(map[string]float64, len(args.Weights))
+	for _, w := range args.Weights {
+		weights[w.Tenant] = float64(w.Weight)
+	}
+	fs := &FairShare{
+		tenantLabel:   args.TenantLabel,
+		weights:       weights,
+		defaultWeight: float64(args.DefaultWeight),
+		halfLife:      time.Duration(args.HalfLifeSeconds) * time.Second,
+		clock:         clock.RealClock{},
+		counters:      make(map[string]float64),
+		charges:       make(map[types.UID]charge),
+	}
+	fs.publish()
+	return fs, nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fairshare
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	internalqueue "k8s.io/kubernetes/pkg/scheduler/internal/queue"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestFairShareQueueOrder(t *testing.T) {
+	type timedPod struct {
+		pod *v1.Pod
+		// wait is the time to wait before reserving or queuing the pod.
+		wait time.Duration
+	}
+	pod := func(namespace, name string) *st.PodWrapper {
+		return st.MakePod().Namespace(namespace).Name(name).UID(namespace + "/" + name)
+	}
+	tests := []struct {
+		name string
+		args *config.FairShareArgs
+		// reserved are the pods reserved before queuing pods.
+		reserved []timedPod
+		queued   []timedPod
+		want     []string
+	}{
+		{
+			name: "tenants with smaller shares first",
+			args: &config.FairShareArgs{
+				Weights: []config.TenantWeight{{Tenant: "c", Weight: 2}},
+			},
+			reserved: []timedPod{
+				{pod: pod("a", "a0").Obj()},
+				{pod: pod("a", "a00").Obj()},
+				{pod: pod("a", "a000").Obj()},
+				{pod: pod("b", "b0").Obj()},
+				{pod: pod("c", "c0").Obj()},
+				{pod: pod("c", "c00").Obj()},
+			},
+			queued: []timedPod{
+				{pod: pod("a", "a1").Obj()},
+				{pod: pod("c", "c1").Obj(), wait: time.Second},
+				{pod: pod("b", "b1").Obj(), wait: time.Second},
+				{pod: pod("d", "d1").Obj(), wait: time.Second},
+			},
+			want: []string{"d1", "c1", "b1", "a1"},
+		},
+		{
+			name: "priority is honored",
+			args: &config.FairShareArgs{},
+			reserved: []timedPod{
+				{pod: pod("a", "a0").Obj()},
+			},
+			queued: []timedPod{
+				{pod: pod("a", "a1").Obj()},
+				{pod: pod("b", "b1").Obj(), wait: time.Second},
+				{pod: pod("a", "a2").Priority(10).Obj(), wait: time.Second},
+			},
+			want: []string{"a2", "b1", "a1"},
+		},
+		{
+			name: "old pods are forgotten",
+			args: &config.FairShareArgs{HalfLifeSeconds: 60},
+			reserved: []timedPod{
+				{pod: pod("a", "a0").Obj()},
+				{pod: pod("a", "a00").Obj()},
+				{pod: pod("a", "a000").Obj()},
+				{pod: pod("b", "b0").Obj(), wait: 5 * time.Minute},
+			},
+			queued: []timedPod{
+				{pod: pod("b", "b1").Obj()},
+				{pod: pod("a", "a1").Obj(), wait: time.Second},
+			},
+			want: []string{"a1", "b1"},
+		},
+		{
+			name: "tenants from label",
+			args: &config.FairShareArgs{TenantLabel: "workload-class"},
+			reserved: []timedPod{
+				{pod: pod("a", "batch0").Label("workload-class", "batch").Obj()},
+				{pod: pod("b", "batch00").Label("workload-class", "batch").Obj()},
+				{pod: pod("a", "unlabeled0").Obj()},
+			},
+			queued: []timedPod{
+				{pod: pod("a", "batch1").Label("workload-class", "batch").Obj()},
+				{pod: pod("a", "unlabeled1").Obj(), wait: time.Second},
+				{pod: pod("a", "interactive1").Label("workload-class", "interactive").Obj(), wait: time.Second},
+			},
+			want: []string{"interactive1", "unlabeled1", "batch1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			pl, err := New(tt.args, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fs := pl.(*FairShare)
+			c := testingclock.NewFakeClock(time.Now())
+			fs.clock = c
+			for _, p := range tt.reserved {
+				c.Step(p.wait)
+				if s := fs.Reserve(ctx, nil, p.pod, "node"); !s.IsSuccess() {
+					t.Fatalf("Unexpected status: %v", s)
+				}
+			}
+			q := internalqueue.NewTestQueue(ctx, fs.Less, internalqueue.WithClock(c))
+			for _, p := range tt.queued {
+				c.Step(p.wait)
+				if err := q.Add(p.pod); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var got []string
+			for range tt.queued {
+				pInfo, err := q.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, pInfo.Pod.Name)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unexpected pop order (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnreserveRefundsTenant(t *testing.T) {
+	ctx := context.Background()
+	pl, err := New(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := pl.(*FairShare)
+	now := time.Now()
+	queued := func(pod *v1.Pod, timestamp time.Time) *framework.QueuedPodInfo {
+		return &framework.QueuedPodInfo{PodInfo: framework.NewPodInfo(pod), Timestamp: timestamp}
+	}
+	a0 := st.MakePod().Namespace("a").Name("a0").UID("a0").Obj()
+	a1 := queued(st.MakePod().Namespace("a").Name("a1").UID("a1").Obj(), now)
+	b1 := queued(st.MakePod().Namespace("b").Name("b1").UID("b1").Obj(), now.Add(time.Second))
+
+	fs.Reserve(ctx, nil, a0, "node")
+	if !fs.Less(b1, a1) {
+		t.Error("Expected b1 to be sorted before a1 once a0 is reserved")
+	}
+	fs.Unreserve(ctx, nil, a0, "node")
+	if !fs.Less(a1, b1) {
+		t.Error("Expected a1 to be sorted before b1 once a0 is unreserved")
+	}
+
+	// Bound pods are not refunded.
+	fs.Reserve(ctx, nil, a0, "node")
+	fs.PostBind(ctx, nil, a0, "node")
+	fs.Unreserve(ctx, nil, a0, "node")
+	if !fs.Less(b1, a1) {
+		t.Error("Expected b1 to be sorted before a1 once a0 is bound")
+	}
+}
+
+func TestChargesForgottenWithoutPostBind(t *testing.T) {
+	ctx := context.Background()
+	pl, err := New(&config.FairShareArgs{HalfLifeSeconds: 60}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := pl.(*FairShare)
+	clock := testingclock.NewFakeClock(time.Now())
+	fs.clock = clock
+
+	for _, name := range []string{"a0", "a1", "a2"} {
+		fs.Reserve(ctx, nil, st.MakePod().Namespace("a").Name(name).UID(name).Obj(), "node")
+	}
+	if len(fs.charges) != 3 {
+		t.Fatalf("Got %d charges, want 3", len(fs.charges))
+	}
+	// Decay the charges below minCounter.
+	clock.Step(20 * time.Minute)
+	fs.Reserve(ctx, nil, st.MakePod().Namespace("b").Name("b0").UID("b0").Obj(), "node")
+	if diff := cmp.Diff([]types.UID{"b0"}, chargedPods(fs)); diff != "" {
+		t.Errorf("Unexpected charged pods (-want, +got):\n%s", diff)
+	}
+}
+
+func chargedPods(fs *FairShare) []types.UID {
+	var uids []types.UID
+	for uid := range fs.charges {
+		uids = append(uids, uid)
+	}
+	return uids
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    runtime.Object
+		wantErr bool
+	}{
+		{
+			name: "nil args",
+		},
+		{
+			name: "valid args",
+			args: &config.FairShareArgs{Weights: []config.TenantWeight{{Tenant: "a", Weight: 2}}},
+		},
+		{
+			name:    "invalid args",
+			args:    &config.FairShareArgs{HalfLifeSeconds: -1},
+			wantErr: true,
+		},
+		{
+			name:    "wrong args type",
+			args:    &config.CoschedulingArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.args, nil); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConfigMapRefreshInterval is how long a ConfigMapGetter caches a ConfigMap
+// before getting it again.
+const ConfigMapRefreshInterval = 30 * time.Second
+
+// ConfigMapGetter gets a single ConfigMap from the API server and caches it for
+// a refresh interval. Unlike a ConfigMap lister, it only needs the scheduler to
+// be allowed to get that ConfigMap, rather than to list and watch the
+// ConfigMaps of the whole cluster, and it doesn't run any goroutine.
+type ConfigMapGetter struct {
+	client          kubernetes.Interface
+	namespace, name string
+	refreshInterval time.Duration
+	// now returns the current time, it's overridden by tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	lastGet time.Time
+	cm      *v1.ConfigMap
+	err     error
+}
+
+// NewConfigMapGetter returns a ConfigMapGetter for the ConfigMap with the given
+// namespace and name.
+func NewConfigMapGetter(client kubernetes.Interface, namespace, name string, refreshInterval time.Duration) *ConfigMapGetter {
+	return &ConfigMapGetter{
+		client:          client,
+		namespace:       namespace,
+		name:            name,
+		refreshInterval: refreshInterval,
+		now:             time.Now,
+	}
+}
+
+// Get returns the ConfigMap, or the error getting it, getting it again if it
+// was last got more than the refresh interval ago. It returns the same object
+// until the resource version of the ConfigMap changes.
+func (g *ConfigMapGetter) Get(ctx context.Context) (*v1.ConfigMap, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	if !g.lastGet.IsZero() && now.Sub(g.lastGet) < g.refreshInterval {
+		return g.cm, g.err
+	}
+	g.lastGet = now
+	cm, err := g.client.CoreV1().ConfigMaps(g.namespace).Get(ctx, g.name, metav1.GetOptions{})
+	if err != nil {
+		g.cm, g.err = nil, err
+		return nil, err
+	}
+	if g.cm == nil || g.cm.ResourceVersion != cm.ResourceVersion {
+		g.cm = cm
+	}
+	g.err = nil
+	return g.cm, nil
+}
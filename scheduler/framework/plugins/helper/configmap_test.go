@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigMapGetter(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	now := time.Now()
+	g := NewConfigMapGetter(client, "kube-system", "cm", time.Minute)
+	g.now = func() time.Time { return now }
+
+	if _, err := g.Get(ctx); !apierrors.IsNotFound(err) {
+		t.Fatalf("Got error %v, want NotFound", err)
+	}
+	cm := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "cm", ResourceVersion: "1"}}
+	if _, err := client.CoreV1().ConfigMaps("kube-system").Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	// The error is cached until the refresh interval elapses.
+	if _, err := g.Get(ctx); !apierrors.IsNotFound(err) {
+		t.Fatalf("Got error %v before the refresh interval elapsed, want NotFound", err)
+	}
+
+	now = now.Add(time.Minute)
+	got, err := g.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	again, err := g.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != got {
+		t.Error("Got a different object for an unchanged ConfigMap")
+	}
+	if n := len(client.Actions()); n != 4 {
+		t.Errorf("Got %d API calls, want 4", n)
+	}
+
+	cm.ResourceVersion = "2"
+	if _, err := client.CoreV1().ConfigMaps("kube-system").Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	updated, err := g.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated == got || updated.ResourceVersion != "2" {
+		t.Errorf("Got ConfigMap with resource version %q, want the updated one", updated.ResourceVersion)
+	}
+}
@@ -18,10 +18,17 @@ package names
 
 const (
 	PrioritySort                    = "PrioritySort"
+	BalancedPreemption              = "BalancedPreemption"
+	CallGraph                       = "CallGraph"
+	CapacityScheduling              = "CapacityScheduling"
+	Coscheduling                    = "Coscheduling"
+	DeadlineSort                    = "DeadlineSort"
 	DefaultBinder                   = "DefaultBinder"
 	DefaultPreemption               = "DefaultPreemption"
+	FairShare                       = "FairShare"
 	ImageLocality                   = "ImageLocality"
 	InterPodAffinity                = "InterPodAffinity"
+	NetworkTopology                 = "NetworkTopology"
 	NodeAffinity                    = "NodeAffinity"
 	NodeName                        = "NodeName"
 	NodePorts                       = "NodePorts"
@@ -34,6 +41,7 @@ const (
 	EBSLimits                       = "EBSLimits"
 	GCEPDLimits                     = "GCEPDLimits"
 	PodTopologySpread               = "PodTopologySpread"
+	SchedulingGates                 = "SchedulingGates"
 	SelectorSpread                  = "SelectorSpread"
 	ServiceAffinity                 = "ServiceAffinity"
 	TaintToleration                 = "TaintToleration"
@@ -0,0 +1,443 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networktopology
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/helper"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Name is the name of the plugin used in the plugin registry and configurations.
+	Name = names.NetworkTopology
+
+	// DefaultServiceLabel is the default value of NetworkTopologyArgs.ServiceLabel.
+	DefaultServiceLabel = "app"
+
+	// PeerServicesAnnotation is the annotation listing, comma-separated, the
+	// services the pod exchanges traffic with.
+	PeerServicesAnnotation = "scheduling.alpha.kubernetes.io/peer-services"
+	// MaxPeerLatencyAnnotation is the annotation holding the maximum latency,
+	// as a Go duration, from the node of the pod to the nodes running its peer
+	// services. It overrides NetworkTopologyArgs.MaxPeerLatency.
+	MaxPeerLatencyAnnotation = "scheduling.alpha.kubernetes.io/max-peer-latency"
+
+	// ErrReasonPeerLatency is the Filter reason status when the latency to a
+	// peer service is too high.
+	ErrReasonPeerLatency = "node(s) exceeded the maximum latency to the pod's peer services"
+
+	// preFilterStateKey is the key in CycleState to NetworkTopology pre-computed data.
+	preFilterStateKey = "PreFilter" + Name
+	// preScoreStateKey is the key in CycleState to NetworkTopology pre-computed data for Scoring.
+	preScoreStateKey = "PreScore" + Name
+)
+
+// NetworkTopology is a plugin that places pods close to the pods of their peer
+// services on the network. It filters out the nodes whose latency to the
+// nodes running peer pods exceeds a maximum, and favors the nodes with the
+// highest bandwidth to them. The links between nodes are measured by the node
+// telemetry, or read from a ConfigMap.
+type NetworkTopology struct {
+	handle          framework.Handle
+	configMapGetter *helper.ConfigMapGetter
+	args            config.NetworkTopologyArgs
+
+	mu sync.Mutex
+	// configMap and configMapLinks cache the links parsed from the last seen
+	// version of the link ConfigMap.
+	configMap      *v1.ConfigMap
+	configMapLinks map[string]map[string]framework.NodeLink
+}
+
+var _ framework.PreFilterPlugin = &NetworkTopology{}
+var _ framework.FilterPlugin = &NetworkTopology{}
+var _ framework.PreScorePlugin = &NetworkTopology{}
+var _ framework.ScorePlugin = &NetworkTopology{}
+var _ framework.EnqueueExtensions = &NetworkTopology{}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *NetworkTopology) Name() string {
+	return Name
+}
+
+// EventsToRegister returns the possible events that may make a Pod
+// failed by this plugin schedulable.
+func (pl *NetworkTopology) EventsToRegister() []framework.ClusterEvent {
+	return []framework.ClusterEvent{
+		{Resource: framework.Pod, ActionType: framework.Delete},
+		{Resource: framework.Node, ActionType: framework.Add},
+		{Resource: framework.NetworkLink, ActionType: framework.Update},
+	}
+}
+
+// links looks up the links between nodes, first in the ones measured by the
+// node telemetry, then in the ones of the ConfigMap.
+type links struct {
+	telemetry *framework.NetworkTopology
+	configMap map[string]map[string]framework.NodeLink
+}
+
+func (l *links) link(from, to string) (framework.NodeLink, bool) {
+	if l.telemetry != nil {
+		if link, ok := l.telemetry.Link(from, to); ok {
+			return link, true
+		}
+	}
+	if link, ok := l.configMap[from][to]; ok {
+		return link, true
+	}
+	link, ok := l.configMap[to][from]
+	return link, ok
+}
+
+// preFilterState computed at PreFilter and used at Filter and PreScore.
+type preFilterState struct {
+	// peers are the peer services of the pod.
+	peers sets.String
+	// peerNodes holds the number of pods of the peer services on each node.
+	peerNodes map[string]int
+	// maxLatency is the maximum latency to the peer nodes, if positive.
+	maxLatency time.Duration
+	links      *links
+}
+
+// Clone the prefilter state.
+func (s *preFilterState) Clone() framework.StateData {
+	c := *s
+	c.peerNodes = make(map[string]int, len(s.peerNodes))
+	for node, n := range s.peerNodes {
+		c.peerNodes[node] = n
+	}
+	return &c
+}
+
+func (s *preFilterState) updateWithPod(serviceLabel string, pod *v1.Pod, nodeName string, delta int) {
+	if !s.peers.Has(pod.Labels[serviceLabel]) {
+		return
+	}
+	s.peerNodes[nodeName] += delta
+	if s.peerNodes[nodeName] <= 0 {
+		delete(s.peerNodes, nodeName)
+	}
+}
+
+// PreFilter invoked at the prefilter extension point. It counts the pods of
+// the peer services on each node, so that Filter only looks up the links to
+// the nodes running them.
+func (pl *NetworkTopology) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod) *framework.Status {
+	s, err := pl.computePreFilterState(ctx, pod)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	cycleState.Write(preFilterStateKey, s)
+	return nil
+}
+
+func (pl *NetworkTopology) computePreFilterState(ctx context.Context, pod *v1.Pod) (*preFilterState, error) {
+	s := &preFilterState{
+		peers:      parsePeers(pod),
+		peerNodes:  make(map[string]int),
+		maxLatency: pl.args.MaxPeerLatency.Duration,
+		links:      pl.links(ctx),
+	}
+	if v, ok := pod.Annotations[MaxPeerLatencyAnnotation]; ok {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			s.maxLatency = d
+		} else {
+			klog.V(5).InfoS("Ignoring malformed maximum peer latency", "pod", klog.KObj(pod), "maxPeerLatency", v)
+		}
+	}
+	if s.peers.Len() == 0 {
+		return s, nil
+	}
+	nodeInfos, err := pl.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes from Snapshot: %w", err)
+	}
+	for _, nodeInfo := range nodeInfos {
+		for _, p := range nodeInfo.Pods {
+			s.updateWithPod(pl.args.ServiceLabel, p.Pod, nodeInfo.Node().Name, 1)
+		}
+	}
+	return s, nil
+}
+
+// PreFilterExtensions returns prefilter extensions, pod add and remove.
+func (pl *NetworkTopology) PreFilterExtensions() framework.PreFilterExtensions {
+	return pl
+}
+
+// AddPod from pre-computed data in cycleState.
+func (pl *NetworkTopology) AddPod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *v1.Pod, podInfoToAdd *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	s.updateWithPod(pl.args.ServiceLabel, podInfoToAdd.Pod, nodeInfo.Node().Name, 1)
+	return nil
+}
+
+// RemovePod from pre-computed data in cycleState.
+func (pl *NetworkTopology) RemovePod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *v1.Pod, podInfoToRemove *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	s.updateWithPod(pl.args.ServiceLabel, podInfoToRemove.Pod, nodeInfo.Node().Name, -1)
+	return nil
+}
+
+func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, error) {
+	c, err := cycleState.Read(preFilterStateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q from cycleState: %w", preFilterStateKey, err)
+	}
+
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to networktopology.preFilterState error", c)
+	}
+	return s, nil
+}
+
+// Filter invoked at the filter extension point. Links that were never
+// measured don't filter nodes out.
+func (pl *NetworkTopology) Filter(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if s.maxLatency <= 0 {
+		return nil
+	}
+	for peerNode := range s.peerNodes {
+		if peerNode == node.Name {
+			continue
+		}
+		if link, ok := s.links.link(node.Name, peerNode); ok && link.Latency > s.maxLatency {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonPeerLatency)
+		}
+	}
+	return nil
+}
+
+// preScoreState computed at PreScore and used at Score.
+type preScoreState struct {
+	// scores holds the raw scores of the nodes.
+	scores map[string]int64
+}
+
+// Clone implements the mandatory Clone interface. We don't really copy the data since
+// there is no need for that.
+func (s *preScoreState) Clone() framework.StateData {
+	return s
+}
+
+// PreScore invoked at the preScore extension point. It computes the scores of
+// all the nodes, as the bandwidth to the pods of the peer services summed
+// over them. Peer pods on the node itself count as much as the best link.
+func (pl *NetworkTopology) PreScore(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodes []*v1.Node) *framework.Status {
+	s, err := getPreFilterState(cycleState)
+	if err != nil {
+		// The plugin may be enabled at PreScore without PreFilter.
+		if s, err = pl.computePreFilterState(ctx, pod); err != nil {
+			return framework.AsStatus(err)
+		}
+	}
+	state := &preScoreState{scores: make(map[string]int64, len(nodes))}
+	cycleState.Write(preScoreStateKey, state)
+	if len(s.peerNodes) == 0 {
+		return nil
+	}
+
+	remote := make([]int64, len(nodes))
+	local := make([]int64, len(nodes))
+	best := make([]int64, len(nodes))
+	pl.handle.Parallelizer().Until(ctx, len(nodes), func(i int) {
+		for peerNode, n := range s.peerNodes {
+			if peerNode == nodes[i].Name {
+				local[i] = int64(n)
+				continue
+			}
+			if link, ok := s.links.link(nodes[i].Name, peerNode); ok {
+				remote[i] += int64(n) * link.Bandwidth
+				if link.Bandwidth > best[i] {
+					best[i] = link.Bandwidth
+				}
+			}
+		}
+	})
+	var bestBandwidth int64 = 1
+	for _, b := range best {
+		if b > bestBandwidth {
+			bestBandwidth = b
+		}
+	}
+	for i, node := range nodes {
+		state.scores[node.Name] = remote[i] + local[i]*bestBandwidth
+	}
+	return nil
+}
+
+func getPreScoreState(cycleState *framework.CycleState) (*preScoreState, error) {
+	c, err := cycleState.Read(preScoreStateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q from cycleState: %w", preScoreStateKey, err)
+	}
+
+	s, ok := c.(*preScoreState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to networktopology.preScoreState error", c)
+	}
+	return s, nil
+}
+
+// Score invoked at the Score extension point.
+func (pl *NetworkTopology) Score(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreScoreState(cycleState)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	return s.scores[nodeName], nil
+}
+
+// NormalizeScore invoked after scoring all nodes.
+func (pl *NetworkTopology) NormalizeScore(ctx context.Context, _ *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	return helper.DefaultNormalizeScore(framework.MaxNodeScore, false, scores)
+}
+
+// ScoreExtensions of the Score plugin.
+func (pl *NetworkTopology) ScoreExtensions() framework.ScoreExtensions {
+	return pl
+}
+
+// links returns the links between nodes measured by the node telemetry and
+// the ones of the ConfigMap.
+func (pl *NetworkTopology) links(ctx context.Context) *links {
+	l := &links{telemetry: pl.handle.NetworkTopology()}
+	if pl.configMapGetter == nil {
+		return l
+	}
+	cm, err := pl.configMapGetter.Get(ctx)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get the network link ConfigMap", "configMap", klog.KRef(pl.args.LinkConfigMapNamespace, pl.args.LinkConfigMapName))
+		}
+		return l
+	}
+
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	// The getter returns the same object until the ConfigMap is updated.
+	if cm != pl.configMap {
+		pl.configMap = cm
+		pl.configMapLinks = parseLinks(cm)
+	}
+	l.configMap = pl.configMapLinks
+	return l
+}
+
+// linkSpec is the format of the links of the link ConfigMap.
+type linkSpec struct {
+	Latency metav1.Duration `json:"latency"`
+	// Bandwidth is in bytes per second.
+	Bandwidth resource.Quantity `json:"bandwidth"`
+}
+
+// parseLinks returns the valid links of the ConfigMap, keyed by source and
+// destination node. Invalid links are logged and ignored.
+func parseLinks(cm *v1.ConfigMap) map[string]map[string]framework.NodeLink {
+	links := make(map[string]map[string]framework.NodeLink, len(cm.Data))
+	for from, data := range cm.Data {
+		var specs map[string]linkSpec
+		if err := yaml.UnmarshalStrict([]byte(data), &specs); err != nil {
+			klog.ErrorS(err, "Ignoring invalid network links", "configMap", klog.KObj(cm), "node", from)
+			continue
+		}
+		links[from] = make(map[string]framework.NodeLink, len(specs))
+		for to, spec := range specs {
+			if spec.Latency.Duration < 0 || spec.Bandwidth.Sign() < 0 {
+				klog.ErrorS(nil, "Ignoring network link with negative latency or bandwidth", "configMap", klog.KObj(cm), "from", from, "to", to)
+				continue
+			}
+			links[from][to] = framework.NodeLink{Latency: spec.Latency.Duration, Bandwidth: spec.Bandwidth.Value()}
+		}
+	}
+	return links
+}
+
+// parsePeers returns the peer services listed in the annotation of the pod.
+func parsePeers(pod *v1.Pod) sets.String {
+	peers := sets.NewString()
+	v, ok := pod.Annotations[PeerServicesAnnotation]
+	if !ok {
+		return peers
+	}
+	for _, service := range strings.Split(v, ",") {
+		if service = strings.TrimSpace(service); len(service) != 0 {
+			peers.Insert(service)
+		}
+	}
+	return peers
+}
+
+// New initializes a new plugin and returns it.
+func New(obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	args := config.NetworkTopologyArgs{}
+	if obj != nil {
+		a, ok := obj.(*config.NetworkTopologyArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type NetworkTopologyArgs, got %T", obj)
+		}
+		args = *a
+	}
+	if err := validation.ValidateNetworkTopologyArgs(nil, &args); err != nil {
+		return nil, err
+	}
+	if len(args.ServiceLabel) == 0 {
+		args.ServiceLabel = DefaultServiceLabel
+	}
+	pl := &NetworkTopology{
+		handle: h,
+		args:   args,
+	}
+	if len(args.LinkConfigMapName) != 0 {
+		pl.configMapGetter = helper.NewConfigMapGetter(h.ClientSet(), args.LinkConfigMapNamespace, args.LinkConfigMapName, helper.ConfigMapRefreshInterval)
+	}
+	return pl, nil
+}
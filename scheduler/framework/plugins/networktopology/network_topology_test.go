@@ -0,0 +1,270 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networktopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+const gb = 1000 * 1000 * 1000
+
+var (
+	nodes = []*v1.Node{
+		st.MakeNode().Name("node1").Obj(),
+		st.MakeNode().Name("node2").Obj(),
+		st.MakeNode().Name("node3").Obj(),
+	}
+	pods = []*v1.Pod{
+		st.MakePod().Name("db").Node("node2").Label("app", "db").Obj(),
+		st.MakePod().Name("cache").Node("node3").Label("app", "cache").Obj(),
+		st.MakePod().Name("web").Node("node1").Label("app", "web").Obj(),
+	}
+)
+
+// newTopology returns the links between nodes measured by the telemetry.
+func newTopology() *framework.NetworkTopology {
+	t := framework.NewNetworkTopology()
+	t.SetLink("node1", "node2", framework.NodeLink{Latency: time.Millisecond, Bandwidth: 10 * gb})
+	t.SetLink("node1", "node3", framework.NodeLink{Latency: time.Millisecond, Bandwidth: 1 * gb})
+	t.SetLink("node3", "node2", framework.NodeLink{Latency: 10 * time.Millisecond, Bandwidth: 5 * gb})
+	return t
+}
+
+func newPlugin(t *testing.T, args *config.NetworkTopologyArgs, opts ...frameworkruntime.Option) *NetworkTopology {
+	opts = append([]frameworkruntime.Option{
+		frameworkruntime.WithSnapshotSharedLister(cache.NewSnapshot(pods, nodes)),
+		frameworkruntime.WithNetworkTopology(newTopology()),
+	}, opts...)
+	fh, err := frameworkruntime.NewFramework(nil, nil, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := New(args, fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.(*NetworkTopology)
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		args config.NetworkTopologyArgs
+		pod  *v1.Pod
+		// removed is the name of a pod removed through the prefilter
+		// extensions before filtering.
+		removed string
+		want    map[string]*framework.Status
+	}{
+		{
+			name: "maximum latency of the args",
+			args: config.NetworkTopologyArgs{MaxPeerLatency: metav1.Duration{Duration: 2 * time.Millisecond}},
+			pod:  st.MakePod().Name("p").Annotation(PeerServicesAnnotation, "db, cache").Obj(),
+			want: map[string]*framework.Status{
+				"node2": framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonPeerLatency),
+				"node3": framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonPeerLatency),
+			},
+		},
+		{
+			name: "maximum latency of the annotation",
+			pod: st.MakePod().Name("p").Annotation(PeerServicesAnnotation, "db,cache").
+				Annotation(MaxPeerLatencyAnnotation, "20ms").Obj(),
+		},
+		{
+			name:    "removed peer",
+			args:    config.NetworkTopologyArgs{MaxPeerLatency: metav1.Duration{Duration: 2 * time.Millisecond}},
+			pod:     st.MakePod().Name("p").Annotation(PeerServicesAnnotation, "db,cache").Obj(),
+			removed: "cache",
+			want: map[string]*framework.Status{
+				"node3": framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonPeerLatency),
+			},
+		},
+		{
+			name: "no maximum latency",
+			pod:  st.MakePod().Name("p").Annotation(PeerServicesAnnotation, "db,cache").Obj(),
+		},
+		{
+			name: "no peers",
+			args: config.NetworkTopologyArgs{MaxPeerLatency: metav1.Duration{Duration: time.Nanosecond}},
+			pod:  st.MakePod().Name("p").Obj(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			pl := newPlugin(t, &tt.args)
+			state := framework.NewCycleState()
+			if status := pl.PreFilter(ctx, state, tt.pod); !status.IsSuccess() {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			for _, p := range pods {
+				if p.Name == tt.removed {
+					nodeInfo := framework.NewNodeInfo()
+					nodeInfo.SetNode(st.MakeNode().Name(p.Spec.NodeName).Obj())
+					if status := pl.RemovePod(ctx, state, tt.pod, framework.NewPodInfo(p), nodeInfo); !status.IsSuccess() {
+						t.Fatalf("Unexpected RemovePod status: %v", status)
+					}
+				}
+			}
+			for _, n := range nodes {
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(n)
+				if got := pl.Filter(ctx, state, tt.pod, nodeInfo); !reflect.DeepEqual(got, tt.want[n.Name]) {
+					t.Errorf("Got status %v for node %q, want %v", got, n.Name, tt.want[n.Name])
+				}
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want framework.NodeScoreList
+	}{
+		{
+			name: "peers on several nodes",
+			pod:  st.MakePod().Name("p").Annotation(PeerServicesAnnotation, "db,cache").Obj(),
+			// node1 has links of 10G and 1G to the peers, node2 and node3 a
+			// link of 5G and a local peer, counted as the best link of 10G.
+			want: []framework.NodeScore{{Name: "node1", Score: 73}, {Name: "node2", Score: 100}, {Name: "node3", Score: 100}},
+		},
+		{
+			name: "peer on a single node",
+			pod:  st.MakePod().Name("p").Annotation(PeerServicesAnnotation, "cache").Obj(),
+			want: []framework.NodeScore{{Name: "node1", Score: 20}, {Name: "node2", Score: 100}, {Name: "node3", Score: 100}},
+		},
+		{
+			name: "no peers",
+			pod:  st.MakePod().Name("p").Obj(),
+			want: []framework.NodeScore{{Name: "node1", Score: 0}, {Name: "node2", Score: 0}, {Name: "node3", Score: 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			pl := newPlugin(t, &config.NetworkTopologyArgs{})
+			state := framework.NewCycleState()
+			// PreFilter is skipped, PreScore must compute the peers itself.
+			if status := pl.PreScore(ctx, state, tt.pod, nodes); !status.IsSuccess() {
+				t.Fatalf("Unexpected PreScore status: %v", status)
+			}
+			var got framework.NodeScoreList
+			for _, n := range nodes {
+				score, status := pl.Score(ctx, state, tt.pod, n.Name)
+				if !status.IsSuccess() {
+					t.Fatalf("Unexpected Score status: %v", status)
+				}
+				got = append(got, framework.NodeScore{Name: n.Name, Score: score})
+			}
+			if status := pl.ScoreExtensions().NormalizeScore(ctx, state, tt.pod, got); !status.IsSuccess() {
+				t.Fatalf("Unexpected NormalizeScore status: %v", status)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unexpected scores (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigMapLinks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "network-links"},
+		Data: map[string]string{
+			"node1": "node2:\n  latency: 3ms\n  bandwidth: 1G\nnode4:\n  latency: 2ms\n  bandwidth: 25G\n",
+			"node2": "node4:\n  latency: -1ms\n",
+			"node3": "not a map",
+		},
+	}
+	pl := newPlugin(t, &config.NetworkTopologyArgs{
+		LinkConfigMapNamespace: "kube-system",
+		LinkConfigMapName:      "network-links",
+	}, frameworkruntime.WithClientSet(clientsetfake.NewSimpleClientset(cm)))
+
+	l := pl.links(ctx)
+	tests := []struct {
+		from, to string
+		want     framework.NodeLink
+		wantOK   bool
+	}{
+		// The links of the telemetry take precedence.
+		{from: "node1", to: "node2", want: framework.NodeLink{Latency: time.Millisecond, Bandwidth: 10 * gb}, wantOK: true},
+		{from: "node4", to: "node1", want: framework.NodeLink{Latency: 2 * time.Millisecond, Bandwidth: 25 * gb}, wantOK: true},
+		{from: "node2", to: "node4"},
+	}
+	for _, tt := range tests {
+		got, ok := l.link(tt.from, tt.to)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("Got link %v (%v) from %q to %q, want %v (%v)", got, ok, tt.from, tt.to, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	fh, err := frameworkruntime.NewFramework(nil, nil, frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		args    runtime.Object
+		wantErr bool
+	}{
+		{
+			name: "nil args",
+		},
+		{
+			name: "valid args",
+			args: &config.NetworkTopologyArgs{MaxPeerLatency: metav1.Duration{Duration: time.Millisecond}},
+		},
+		{
+			name:    "invalid args",
+			args:    &config.NetworkTopologyArgs{LinkConfigMapName: "network-links"},
+			wantErr: true,
+		},
+		{
+			name:    "wrong args type",
+			args:    &config.CoschedulingArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.args, fh); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
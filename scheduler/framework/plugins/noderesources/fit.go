@@ -35,6 +35,7 @@ import (
 var _ framework.PreFilterPlugin = &Fit{}
 var _ framework.FilterPlugin = &Fit{}
 var _ framework.EnqueueExtensions = &Fit{}
+var _ framework.QueueingHintExtensions = &Fit{}
 var _ framework.ScorePlugin = &Fit{}
 
 const (
@@ -140,6 +141,9 @@ func NewFit(plArgs runtime.Object, h framework.Handle, fts feature.Features) (fr
 // If Pod Overhead is specified and the feature gate is set, the resources defined for Overhead
 // are added to the calculated Resource request sum
 //
+// The network bandwidth requested through annotations is added as well, unless
+// the containers request it as an extended resource.
+//
 // Example:
 //
 // Pod:
@@ -175,6 +179,7 @@ func computePodResourceRequest(pod *v1.Pod, enablePodOverhead bool) *preFilterSt
 		result.Add(pod.Spec.Overhead)
 	}
 
+	framework.AddPodBandwidthRequests(&result.Resource, pod)
 	return result
 }
 
@@ -211,8 +216,31 @@ func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, error
 func (f *Fit) EventsToRegister() []framework.ClusterEvent {
 	return []framework.ClusterEvent{
 		{Resource: framework.Pod, ActionType: framework.Delete},
-		{Resource: framework.Node, ActionType: framework.Add | framework.UpdateNodeAllocatable},
+		{Resource: framework.Node, ActionType: framework.Add | framework.UpdateNodeAllocatable | framework.UpdateNodeLabel},
+	}
+}
+
+// QueueingHints returns the hint functions of the events registered by the
+// plugin.
+func (f *Fit) QueueingHints() map[framework.ClusterEvent]framework.QueueingHintFn {
+	return map[framework.ClusterEvent]framework.QueueingHintFn{
+		{Resource: framework.Node, ActionType: framework.Add | framework.UpdateNodeAllocatable | framework.UpdateNodeLabel}: f.isSchedulableAfterNodeChange,
+	}
+}
+
+// isSchedulableAfterNodeChange skips the added or updated nodes whose
+// allocatable resources can't run the pod, even with no other pods on them.
+func (f *Fit) isSchedulableAfterNodeChange(pod *v1.Pod, _, newObj interface{}) framework.QueueingHint {
+	node, ok := newObj.(*v1.Node)
+	if !ok {
+		return framework.Queue
+	}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+	if len(fitsRequest(computePodResourceRequest(pod, f.enablePodOverhead), nodeInfo, f.ignoredResources, f.ignoredResourceGroups)) != 0 {
+		return framework.QueueSkip
 	}
+	return framework.Queue
 }
 
 // Filter invoked at the filter extension point.
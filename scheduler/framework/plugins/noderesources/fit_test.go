@@ -598,6 +598,58 @@ func TestStorageRequests(t *testing.T) {
 
 }
 
+func TestBandwidthRequests(t *testing.T) {
+	node := st.MakeNode().Name("node").Label(framework.IngressBandwidthKey, "10G").
+		Capacity(map[v1.ResourceName]string{"cpu": "4000", "memory": "10000"}).Obj()
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		existing   *v1.Pod
+		wantStatus *framework.Status
+	}{
+		{
+			name:     "bandwidth annotation fits",
+			pod:      st.MakePod().Annotation(framework.IngressBandwidthKey, "4G").Obj(),
+			existing: st.MakePod().Node("node").Annotation(framework.IngressBandwidthKey, "6G").Obj(),
+		},
+		{
+			name:       "bandwidth annotation does not fit",
+			pod:        st.MakePod().Annotation(framework.IngressBandwidthKey, "5G").Obj(),
+			existing:   st.MakePod().Node("node").Annotation(framework.IngressBandwidthKey, "6G").Obj(),
+			wantStatus: framework.NewStatus(framework.Unschedulable, getErrReason(framework.ResourceIngressBandwidth)),
+		},
+		{
+			name:       "bandwidth not advertised by the node",
+			pod:        st.MakePod().Annotation(framework.EgressBandwidthKey, "1").Obj(),
+			wantStatus: framework.NewStatus(framework.Unschedulable, getErrReason(framework.ResourceEgressBandwidth)),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodeInfo := framework.NewNodeInfo()
+			if test.existing != nil {
+				nodeInfo = framework.NewNodeInfo(test.existing)
+			}
+			nodeInfo.SetNode(node)
+
+			p, err := NewFit(&config.NodeResourcesFitArgs{ScoringStrategy: defaultScoringStrategy}, nil, plfeature.Features{EnablePodOverhead: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			cycleState := framework.NewCycleState()
+			preFilterStatus := p.(framework.PreFilterPlugin).PreFilter(context.Background(), cycleState, test.pod)
+			if !preFilterStatus.IsSuccess() {
+				t.Errorf("prefilter failed with status: %v", preFilterStatus)
+			}
+
+			gotStatus := p.(framework.FilterPlugin).Filter(context.Background(), cycleState, test.pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, test.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, test.wantStatus)
+			}
+		})
+	}
+}
+
 func TestFitScore(t *testing.T) {
 	defaultResources := []config.ResourceSpec{
 		{Name: string(v1.ResourceCPU), Weight: 1},
@@ -708,6 +760,26 @@ func TestFitScore(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "test case for ScoringStrategy LeastAllocated with bandwidth annotations",
+			requestedPod: st.MakePod().
+				Annotation(framework.IngressBandwidthKey, "1G").
+				Obj(),
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node1").Label(framework.IngressBandwidthKey, "10G").Obj(),
+				st.MakeNode().Name("node2").Label(framework.IngressBandwidthKey, "10G").Obj(),
+			},
+			existingPods: []*v1.Pod{
+				st.MakePod().Node("node1").Annotation(framework.IngressBandwidthKey, "5G").Obj(),
+			},
+			expectedPriorities: []framework.NodeScore{{Name: "node1", Score: 40}, {Name: "node2", Score: 90}},
+			nodeResourcesFitArgs: config.NodeResourcesFitArgs{
+				ScoringStrategy: &config.ScoringStrategy{
+					Type:      config.LeastAllocated,
+					Resources: []config.ResourceSpec{{Name: string(framework.ResourceIngressBandwidth), Weight: 1}},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -736,3 +808,40 @@ func TestFitScore(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSchedulableAfterNodeChange(t *testing.T) {
+	pod := st.MakePod().Req(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj()
+	tests := []struct {
+		name   string
+		newObj interface{}
+		want   framework.QueueingHint
+	}{
+		{
+			name:   "node large enough",
+			newObj: st.MakeNode().Name("n").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+			want:   framework.Queue,
+		},
+		{
+			name:   "node too small",
+			newObj: st.MakeNode().Name("n").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj(),
+			want:   framework.QueueSkip,
+		},
+		{
+			name: "unknown node",
+			want: framework.Queue,
+		},
+	}
+	p, err := NewFit(&config.NodeResourcesFitArgs{ScoringStrategy: defaultScoringStrategy}, nil, plfeature.Features{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, fn := range p.(framework.QueueingHintExtensions).QueueingHints() {
+				if got := fn(pod, nil, tt.newObj); got != tt.want {
+					t.Errorf("Got hint %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -139,6 +139,13 @@ func (r *resourceAllocationScorer) calculatePodResourceRequest(pod *v1.Pod, reso
 		}
 	}
 
+	// The bandwidth can also be requested through annotations.
+	if podRequest == 0 {
+		if bandwidth, ok := framework.PodBandwidthRequest(pod, resource); ok {
+			podRequest = bandwidth
+		}
+	}
+
 	return podRequest
 }
 
@@ -19,11 +19,18 @@ package plugins
 import (
 	"k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/kubernetes/pkg/features"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/balancedpreemption"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/callgraph"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/capacityscheduling"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/coscheduling"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/deadlinesort"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/fairshare"
 	plfeature "k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/imagelocality"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/interpodaffinity"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/networktopology"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/nodeaffinity"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/nodename"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/nodeports"
@@ -32,6 +39,7 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/nodevolumelimits"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/podtopologyspread"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/schedulinggates"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/selectorspread"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/tainttoleration"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/volumebinding"
@@ -76,5 +84,13 @@ func NewInTreeRegistry() runtime.Registry {
 		queuesort.Name:                       queuesort.New,
 		defaultbinder.Name:                   defaultbinder.New,
 		defaultpreemption.Name:               runtime.FactoryAdapter(fts, defaultpreemption.New),
+		coscheduling.Name:                    coscheduling.New,
+		deadlinesort.Name:                    deadlinesort.New,
+		capacityscheduling.Name:              capacityscheduling.New,
+		fairshare.Name:                       fairshare.New,
+		schedulinggates.Name:                 schedulinggates.New,
+		balancedpreemption.Name:              runtime.FactoryAdapter(fts, balancedpreemption.New),
+		callgraph.Name:                       callgraph.New,
+		networktopology.Name:                 networktopology.New,
 	}
 }
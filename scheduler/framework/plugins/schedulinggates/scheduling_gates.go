@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulinggates
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+)
+
+const (
+	// Name is the name of the plugin used in the plugin registry and configurations.
+	Name = names.SchedulingGates
+
+	// SchedulingGatesAnnotation is the annotation holding the comma-separated
+	// names of the gates holding the pod out of scheduling. Controllers remove
+	// their gate from the list, or the whole annotation, once the condition
+	// the pod waits for is met.
+	SchedulingGatesAnnotation = "scheduling.alpha.kubernetes.io/scheduling-gates"
+)
+
+// SchedulingGates is a plugin that holds pods out of the active scheduling
+// queue as long as they carry scheduling gates.
+type SchedulingGates struct{}
+
+var _ framework.PreEnqueuePlugin = &SchedulingGates{}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *SchedulingGates) Name() string {
+	return Name
+}
+
+// PreEnqueue invoked at the PreEnqueue extension point. It rejects the pods
+// with scheduling gates.
+func (pl *SchedulingGates) PreEnqueue(_ context.Context, p *v1.Pod) *framework.Status {
+	gates := Gates(p)
+	if len(gates) == 0 {
+		return nil
+	}
+	return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+		fmt.Sprintf("waiting for scheduling gates: %s", strings.Join(gates, ",")))
+}
+
+// Gates returns the names of the scheduling gates of the pod.
+func Gates(p *v1.Pod) []string {
+	var gates []string
+	for _, g := range strings.Split(p.Annotations[SchedulingGatesAnnotation], ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			gates = append(gates, g)
+		}
+	}
+	return gates
+}
+
+// New initializes a new plugin and returns it.
+func New(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+	return &SchedulingGates{}, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulinggates
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func TestPreEnqueue(t *testing.T) {
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		wantStatus *framework.Status
+	}{
+		{
+			name: "pod without annotation",
+			pod:  st.MakePod().Name("p").Obj(),
+		},
+		{
+			name: "pod with empty annotation",
+			pod:  st.MakePod().Name("p").Annotation(SchedulingGatesAnnotation, " , ").Obj(),
+		},
+		{
+			name:       "pod with one gate",
+			pod:        st.MakePod().Name("p").Annotation(SchedulingGatesAnnotation, "telemetry").Obj(),
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "waiting for scheduling gates: telemetry"),
+		},
+		{
+			name:       "pod with several gates",
+			pod:        st.MakePod().Name("p").Annotation(SchedulingGatesAnnotation, "telemetry, model,").Obj(),
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "waiting for scheduling gates: telemetry,model"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotStatus := p.(framework.PreEnqueuePlugin).PreEnqueue(context.Background(), tt.pod)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("Got status %v, want %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -114,6 +114,15 @@ type Interface interface {
 		pod *v1.Pod, nodeInfo *framework.NodeInfo, pdbs []*policy.PodDisruptionBudget) ([]*v1.Pod, int, *framework.Status)
 }
 
+// CandidateRanker is an optional interface that preemption plugins can
+// implement to choose the candidate to nominate with their own criteria,
+// instead of the default ones of SelectCandidate.
+type CandidateRanker interface {
+	// RankCandidates returns the candidates sorted from the most to the least
+	// preferred one. If it fails, the default criteria are used.
+	RankCandidates(ctx context.Context, pod *v1.Pod, candidates []Candidate) ([]Candidate, error)
+}
+
 type Evaluator struct {
 	PluginName string
 	Handler    framework.Handle
@@ -180,7 +189,7 @@ func (ev *Evaluator) Preempt(ctx context.Context, pod *v1.Pod, m framework.NodeT
 	}
 
 	// 4) Find the best candidate.
-	bestCandidate := ev.SelectCandidate(candidates)
+	bestCandidate := ev.selectCandidate(ctx, pod, candidates)
 	if bestCandidate == nil || len(bestCandidate.Name()) == 0 {
 		return nil, framework.NewStatus(framework.Unschedulable)
 	}
@@ -193,6 +202,58 @@ func (ev *Evaluator) Preempt(ctx context.Context, pod *v1.Pod, m framework.NodeT
 	return framework.NewPostFilterResultWithNominatedNode(bestCandidate.Name()), framework.NewStatus(framework.Success)
 }
 
+// Simulation is the outcome of a preemption dry run.
+type Simulation struct {
+	// Candidates are the nodes where preempting some victims makes the pod
+	// schedulable, along with these victims.
+	Candidates []Candidate
+	// NodeToStatusMap holds the status of the nodes that are not candidates.
+	NodeToStatusMap framework.NodeToStatusMap
+	// Selected is the candidate the preemptor would be nominated to, or nil if
+	// there is no candidate.
+	Selected Candidate
+}
+
+// Simulator is implemented by preemption plugins whose preemption can be
+// simulated, e.g. to report what they would preempt before enabling them.
+type Simulator interface {
+	// SimulatePreemption runs the preemption of the plugin without evicting
+	// any victim nor nominating the preemptor.
+	SimulatePreemption(ctx context.Context, state *framework.CycleState, pod *v1.Pod, m framework.NodeToStatusMap) (*Simulation, *framework.Status)
+}
+
+// Simulate runs steps 1) to 4) of Preempt for the given <pod>: it finds the
+// preemption candidates and selects the best one, but evicts no victim and
+// leaves the nominated node name of the pods unchanged. Unlike Preempt, it
+// dry runs the preemption on all the nodes where it might help rather than on
+// a sample of them. Extenders are not called. It returns an Unschedulable status if the pod is not eligible to
+// preempt other pods.
+func (ev *Evaluator) Simulate(ctx context.Context, pod *v1.Pod, m framework.NodeToStatusMap) (*Simulation, *framework.Status) {
+	if !ev.PodEligibleToPreemptOthers(pod, m[pod.Status.NominatedNodeName]) {
+		return nil, framework.NewStatus(framework.Unschedulable, "pod is not eligible to preempt other pods")
+	}
+	allNodes, err := ev.Handler.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	if len(allNodes) == 0 {
+		return nil, framework.AsStatus(errors.New("no nodes available"))
+	}
+	potentialNodes, unschedulableNodeStatus := nodesWherePreemptionMightHelp(allNodes, m)
+	if len(potentialNodes) == 0 {
+		return &Simulation{NodeToStatusMap: unschedulableNodeStatus}, nil
+	}
+	candidates, nodeStatuses, err := ev.candidatesOnNodes(ctx, pod, potentialNodes, unschedulableNodeStatus, 0, int32(len(potentialNodes)))
+	if err != nil && len(candidates) == 0 {
+		return nil, framework.AsStatus(err)
+	}
+	sim := &Simulation{Candidates: candidates, NodeToStatusMap: nodeStatuses}
+	if len(candidates) != 0 {
+		sim.Selected = ev.selectCandidate(ctx, pod, candidates)
+	}
+	return sim, nil
+}
+
 // FindCandidates calculates a slice of preemption candidates.
 // Each candidate is executable to make the given <pod> schedulable.
 func (ev *Evaluator) findCandidates(ctx context.Context, pod *v1.Pod, m framework.NodeToStatusMap) ([]Candidate, framework.NodeToStatusMap, error) {
@@ -213,13 +274,19 @@ func (ev *Evaluator) findCandidates(ctx context.Context, pod *v1.Pod, m framewor
 		}
 		return nil, unschedulableNodeStatus, nil
 	}
+	offset, numCandidates := ev.GetOffsetAndNumCandidates(int32(len(potentialNodes)))
+	return ev.candidatesOnNodes(ctx, pod, potentialNodes, unschedulableNodeStatus, offset, numCandidates)
+}
 
+// candidatesOnNodes dry runs the preemption on the nodes where preemption
+// might help, starting at offset until numCandidates are found, and merges
+// the statuses of the nodes where it can't.
+func (ev *Evaluator) candidatesOnNodes(ctx context.Context, pod *v1.Pod, potentialNodes []*framework.NodeInfo, unschedulableNodeStatus framework.NodeToStatusMap, offset, numCandidates int32) ([]Candidate, framework.NodeToStatusMap, error) {
 	pdbs, err := getPodDisruptionBudgets(ev.PdbLister)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	offset, numCandidates := ev.GetOffsetAndNumCandidates(int32(len(potentialNodes)))
 	if klog.V(5).Enabled() {
 		var sample []string
 		for i := offset; i < offset+10 && i < int32(len(potentialNodes)); i++ {
@@ -296,6 +363,21 @@ func (ev *Evaluator) callExtenders(pod *v1.Pod, candidates []Candidate) ([]Candi
 	return newCandidates, nil
 }
 
+// selectCandidate chooses the candidate ranked first by the plugin if it
+// implements CandidateRanker, or the one chosen by SelectCandidate otherwise.
+func (ev *Evaluator) selectCandidate(ctx context.Context, pod *v1.Pod, candidates []Candidate) Candidate {
+	ranker, ok := ev.Interface.(CandidateRanker)
+	if !ok || len(candidates) < 2 {
+		return ev.SelectCandidate(candidates)
+	}
+	ranked, err := ranker.RankCandidates(ctx, pod, candidates)
+	if err != nil || len(ranked) == 0 {
+		klog.ErrorS(err, "Ranking preemption candidates failed, falling back to the default criteria", "pod", klog.KObj(pod), "plugin", ev.PluginName)
+		return ev.SelectCandidate(candidates)
+	}
+	return ranked[0]
+}
+
 // SelectCandidate chooses the best-fit candidate from given <candidates> and return it.
 // NOTE: This method is exported for easier testing in default preemption.
 func (ev *Evaluator) SelectCandidate(candidates []Candidate) Candidate {
@@ -331,3 +331,65 @@ func TestDryRunPreemption(t *testing.T) {
 		})
 	}
 }
+
+// fakeRankerPlugin ranks the candidates in reverse order, or fails with err.
+type fakeRankerPlugin struct {
+	FakePostFilterPlugin
+	err error
+}
+
+func (pl *fakeRankerPlugin) CandidatesToVictimsMap(candidates []Candidate) map[string]*extenderv1.Victims {
+	m := make(map[string]*extenderv1.Victims)
+	for _, c := range candidates {
+		m[c.Name()] = c.Victims()
+	}
+	return m
+}
+
+func (pl *fakeRankerPlugin) RankCandidates(_ context.Context, _ *v1.Pod, candidates []Candidate) ([]Candidate, error) {
+	if pl.err != nil {
+		return nil, pl.err
+	}
+	ranked := make([]Candidate, 0, len(candidates))
+	for i := len(candidates) - 1; i >= 0; i-- {
+		ranked = append(ranked, candidates[i])
+	}
+	return ranked, nil
+}
+
+func TestSelectCandidateWithRanker(t *testing.T) {
+	lowPriorityPod := st.MakePod().Name("low").Priority(midPriority).Obj()
+	candidates := []Candidate{
+		// node1 is preferred by the default criteria, since its victim has
+		// a lower priority.
+		&candidate{name: "node1", victims: &extenderv1.Victims{Pods: []*v1.Pod{lowPriorityPod}}},
+		&candidate{name: "node2", victims: &extenderv1.Victims{Pods: []*v1.Pod{st.MakePod().Name("high").Priority(highPriority).Obj()}}},
+	}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "candidate ranked first",
+			want: "node2",
+		},
+		{
+			name: "ranking fails",
+			err:  fmt.Errorf("agent unavailable"),
+			want: "node1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pe := Evaluator{
+				PluginName: "FakeRanker",
+				Interface:  &fakeRankerPlugin{err: tt.err},
+			}
+			got := pe.selectCandidate(context.Background(), st.MakePod().Name("p").Obj(), candidates)
+			if got.Name() != tt.want {
+				t.Errorf("Got candidate %v, want %v", got.Name(), tt.want)
+			}
+		})
+	}
+}
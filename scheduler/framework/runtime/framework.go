@@ -19,8 +19,10 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"reflect"
 	"sort"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -35,8 +37,10 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/hostselector"
 	"k8s.io/kubernetes/pkg/scheduler/framework/parallelize"
 	"k8s.io/kubernetes/pkg/scheduler/metrics"
+	"k8s.io/kubernetes/pkg/scheduler/util"
 )
 
 const (
@@ -76,6 +80,8 @@ type frameworkImpl struct {
 	waitingPods          *waitingPodsMap
 	scorePluginWeight    map[string]int
 	queueSortPlugins     []framework.QueueSortPlugin
+	preEnqueuePlugins    []framework.PreEnqueuePlugin
+	resyncPlugins        []framework.ResyncPlugin
 	preFilterPlugins     []framework.PreFilterPlugin
 	filterPlugins        []framework.FilterPlugin
 	postFilterPlugins    []framework.PostFilterPlugin
@@ -99,6 +105,16 @@ type frameworkImpl struct {
 	framework.PodNominator
 
 	parallelizer parallelize.Parallelizer
+	rand         *rand.Rand
+	hostSelector framework.HostSelector
+
+	networkTopology     *framework.NetworkTopology
+	clusterEventHandler ClusterEventHandler
+
+	// scorePluginWeightLock guards the replacement of scorePluginWeight by
+	// SetScorePluginWeights once the framework is built. The map is replaced,
+	// never modified.
+	scorePluginWeightLock sync.RWMutex
 
 	// Indicates that RunFilterPlugins should accumulate all failed statuses and not return
 	// after the first failure.
@@ -150,7 +166,12 @@ type frameworkOptions struct {
 	runAllFilters          bool
 	captureProfile         CaptureProfile
 	clusterEventMap        map[framework.ClusterEvent]sets.String
+	queueingHintMap        map[framework.ClusterEvent]map[string]framework.QueueingHintFn
 	parallelizer           parallelize.Parallelizer
+	rand                   *rand.Rand
+	networkTopology        *framework.NetworkTopology
+	clusterEventHandler    ClusterEventHandler
+	hostSelectors          map[string]*config.HostSelector
 }
 
 // Option for the frameworkImpl.
@@ -230,6 +251,43 @@ func WithParallelism(parallelism int) Option {
 	}
 }
 
+// WithRand sets the source of randomness for the scheduling frameworkImpl. It
+// must be safe for concurrent use.
+func WithRand(r *rand.Rand) Option {
+	return func(o *frameworkOptions) {
+		o.rand = r
+	}
+}
+
+// WithNetworkTopology sets the links between nodes measured by the node
+// telemetry for the scheduling frameworkImpl.
+func WithNetworkTopology(t *framework.NetworkTopology) Option {
+	return func(o *frameworkOptions) {
+		o.networkTopology = t
+	}
+}
+
+// WithHostSelectors sets the strategies picking the node a pod is assigned to,
+// by profile name. Profiles missing from the map pick the node with the
+// highest score.
+func WithHostSelectors(selectors map[string]*config.HostSelector) Option {
+	return func(o *frameworkOptions) {
+		o.hostSelectors = selectors
+	}
+}
+
+// ClusterEventHandler is a callback to handle the cluster events reported by
+// plugins.
+type ClusterEventHandler func(framework.ClusterEvent)
+
+// WithClusterEventHandler sets a callback to handle the cluster events reported
+// by plugins. Without it, the reported events are dropped.
+func WithClusterEventHandler(h ClusterEventHandler) Option {
+	return func(o *frameworkOptions) {
+		o.clusterEventHandler = h
+	}
+}
+
 // CaptureProfile is a callback to capture a finalized profile.
 type CaptureProfile func(config.KubeSchedulerProfile)
 
@@ -244,7 +302,10 @@ func defaultFrameworkOptions() frameworkOptions {
 	return frameworkOptions{
 		metricsRecorder: newMetricsRecorder(1000, time.Second),
 		clusterEventMap: make(map[framework.ClusterEvent]sets.String),
+		queueingHintMap: make(map[framework.ClusterEvent]map[string]framework.QueueingHintFn),
 		parallelizer:    parallelize.NewParallelizer(parallelize.DefaultParallelism),
+		rand:            util.NewRand(time.Now().UnixNano()),
+		networkTopology: framework.NewNetworkTopology(),
 	}
 }
 
@@ -255,6 +316,15 @@ func WithClusterEventMap(m map[framework.ClusterEvent]sets.String) Option {
 	}
 }
 
+// WithQueueingHintMap sets queueingHintMap for the scheduling frameworkImpl.
+// It's filled with the hint functions of the plugins, keyed by event and
+// plugin name.
+func WithQueueingHintMap(m map[framework.ClusterEvent]map[string]framework.QueueingHintFn) Option {
+	return func(o *frameworkOptions) {
+		o.queueingHintMap = m
+	}
+}
+
 var _ framework.Framework = &frameworkImpl{}
 
 // NewFramework initializes plugins given the configuration and the registry.
@@ -278,13 +348,22 @@ func NewFramework(r Registry, profile *config.KubeSchedulerProfile, opts ...Opti
 		extenders:            options.extenders,
 		PodNominator:         options.podNominator,
 		parallelizer:         options.parallelizer,
+		rand:                 options.rand,
+		networkTopology:      options.networkTopology,
+		clusterEventHandler:  options.clusterEventHandler,
 	}
 
 	if profile == nil {
+		f.hostSelector = &hostselector.MaxScore{}
 		return f, nil
 	}
 
 	f.profileName = profile.SchedulerName
+	hs, err := hostselector.New(options.hostSelectors[f.profileName])
+	if err != nil {
+		return nil, fmt.Errorf("initializing host selector: %w", err)
+	}
+	f.hostSelector = hs
 	if profile.Plugins == nil {
 		return f, nil
 	}
@@ -328,6 +407,24 @@ func NewFramework(r Registry, profile *config.KubeSchedulerProfile, opts ...Opti
 
 		// Update ClusterEventMap in place.
 		fillEventToPluginMap(p, options.clusterEventMap)
+		fillQueueingHintMap(p, options.queueingHintMap)
+	}
+
+	// PreEnqueue and Resync aren't extension points of the configuration:
+	// every enabled plugin implementing PreEnqueuePlugin or ResyncPlugin runs,
+	// sorted by name.
+	pluginNames := make([]string, 0, len(pluginsMap))
+	for name := range pluginsMap {
+		pluginNames = append(pluginNames, name)
+	}
+	sort.Strings(pluginNames)
+	for _, name := range pluginNames {
+		if pl, ok := pluginsMap[name].(framework.PreEnqueuePlugin); ok {
+			f.preEnqueuePlugins = append(f.preEnqueuePlugins, pl)
+		}
+		if pl, ok := pluginsMap[name].(framework.ResyncPlugin); ok {
+			f.resyncPlugins = append(f.resyncPlugins, pl)
+		}
 	}
 
 	// initialize plugins per individual extension points
@@ -505,6 +602,22 @@ func fillEventToPluginMap(p framework.Plugin, eventToPlugins map[framework.Clust
 	registerClusterEvents(p.Name(), eventToPlugins, events)
 }
 
+func fillQueueingHintMap(p framework.Plugin, hints map[framework.ClusterEvent]map[string]framework.QueueingHintFn) {
+	ext, ok := p.(framework.QueueingHintExtensions)
+	if !ok {
+		return
+	}
+	for evt, fn := range ext.QueueingHints() {
+		if fn == nil {
+			continue
+		}
+		if hints[evt] == nil {
+			hints[evt] = make(map[string]framework.QueueingHintFn)
+		}
+		hints[evt][p.Name()] = fn
+	}
+}
+
 func registerClusterEvents(name string, eventToPlugins map[framework.ClusterEvent]sets.String, evts []framework.ClusterEvent) {
 	for _, evt := range evts {
 		if eventToPlugins[evt] == nil {
@@ -541,6 +654,21 @@ func updatePluginList(pluginList interface{}, pluginSet config.PluginSet, plugin
 	return nil
 }
 
+// PreEnqueuePlugins returns the PreEnqueue plugins of the profile.
+func (f *frameworkImpl) PreEnqueuePlugins() []framework.PreEnqueuePlugin {
+	return f.preEnqueuePlugins
+}
+
+// ResyncPlugins returns the plugins of the profile to resync periodically.
+func (f *frameworkImpl) ResyncPlugins() []framework.ResyncPlugin {
+	return f.resyncPlugins
+}
+
+// PostFilterPlugins returns the PostFilter plugins of the profile.
+func (f *frameworkImpl) PostFilterPlugins() []framework.PostFilterPlugin {
+	return f.postFilterPlugins
+}
+
 // QueueSortFunc returns the function to sort pods in scheduling queue
 func (f *frameworkImpl) QueueSortFunc() framework.LessFunc {
 	if f == nil {
@@ -909,10 +1037,11 @@ func (f *frameworkImpl) RunScorePlugins(ctx context.Context, state *framework.Cy
 	}
 
 	// Apply score defaultWeights for each ScorePlugin in parallel.
+	weights := f.scoreWeights()
 	f.Parallelizer().Until(ctx, len(f.scorePlugins), func(index int) {
 		pl := f.scorePlugins[index]
 		// Score plugins' weight has been checked when they are initialized.
-		weight := f.scorePluginWeight[pl.Name()]
+		weight := weights[pl.Name()]
 		nodeScoreList := pluginToNodeScores[pl.Name()]
 
 		for i, nodeScore := range nodeScoreList {
@@ -1222,6 +1351,7 @@ func (f *frameworkImpl) HasScorePlugins() bool {
 // point. Returns nil if no plugins where configured.
 func (f *frameworkImpl) ListPlugins() *config.Plugins {
 	m := config.Plugins{}
+	weights := f.scoreWeights()
 
 	for _, e := range f.getExtensionPoints(&m) {
 		plugins := reflect.ValueOf(e.slicePtr).Elem()
@@ -1232,7 +1362,7 @@ func (f *frameworkImpl) ListPlugins() *config.Plugins {
 			p := config.Plugin{Name: name}
 			if extName == "ScorePlugin" {
 				// Weights apply only to score plugins.
-				p.Weight = int32(f.scorePluginWeight[name])
+				p.Weight = int32(weights[name])
 			}
 			cfgs = append(cfgs, p)
 		}
@@ -1293,3 +1423,70 @@ func (f *frameworkImpl) ProfileName() string {
 func (f *frameworkImpl) Parallelizer() parallelize.Parallelizer {
 	return f.parallelizer
 }
+
+// HostSelector returns the strategy used to pick the node a pod is assigned to.
+func (f *frameworkImpl) HostSelector() framework.HostSelector {
+	return f.hostSelector
+}
+
+func (f *frameworkImpl) scoreWeights() map[string]int {
+	f.scorePluginWeightLock.RLock()
+	defer f.scorePluginWeightLock.RUnlock()
+	return f.scorePluginWeight
+}
+
+// ScorePluginWeights returns the current weights of the Score plugins.
+func (f *frameworkImpl) ScorePluginWeights() map[string]int {
+	weights := make(map[string]int, len(f.scorePlugins))
+	current := f.scoreWeights()
+	for _, pl := range f.scorePlugins {
+		weights[pl.Name()] = current[pl.Name()]
+	}
+	return weights
+}
+
+// SetScorePluginWeights replaces the weights of the given Score plugins.
+func (f *frameworkImpl) SetScorePluginWeights(weights map[string]int) error {
+	f.scorePluginWeightLock.Lock()
+	defer f.scorePluginWeightLock.Unlock()
+	updated := make(map[string]int, len(f.scorePluginWeight))
+	for name, w := range f.scorePluginWeight {
+		updated[name] = w
+	}
+	for name, w := range weights {
+		if _, ok := updated[name]; !ok {
+			return fmt.Errorf("%q is not a Score plugin of profile %q", name, f.profileName)
+		}
+		if w < 1 {
+			return fmt.Errorf("weight of Score plugin %q must be at least 1, got %d", name, w)
+		}
+		updated[name] = w
+	}
+	var totalPriority int64
+	for _, pl := range f.scorePlugins {
+		totalPriority += int64(updated[pl.Name()]) * framework.MaxNodeScore
+		if totalPriority > framework.MaxTotalScore {
+			return fmt.Errorf("total score of Score plugins could overflow")
+		}
+	}
+	f.scorePluginWeight = updated
+	return nil
+}
+
+// Rand returns the source of randomness shared by the scheduler.
+func (f *frameworkImpl) Rand() *rand.Rand {
+	return f.rand
+}
+
+// NetworkTopology returns the links between nodes measured by the node
+// telemetry.
+func (f *frameworkImpl) NetworkTopology() *framework.NetworkTopology {
+	return f.networkTopology
+}
+
+// ReportClusterEvent passes the event to the cluster event handler, if any.
+func (f *frameworkImpl) ReportClusterEvent(evt framework.ClusterEvent) {
+	if f.clusterEventHandler != nil {
+		f.clusterEventHandler(evt)
+	}
+}
@@ -443,10 +443,11 @@ func TestInitFrameworkWithScorePlugins(t *testing.T) {
 
 func TestNewFrameworkErrors(t *testing.T) {
 	tests := []struct {
-		name      string
-		plugins   *config.Plugins
-		pluginCfg []config.PluginConfig
-		wantErr   string
+		name         string
+		plugins      *config.Plugins
+		pluginCfg    []config.PluginConfig
+		hostSelector *config.HostSelector
+		wantErr      string
 	}{
 		{
 			name: "duplicate plugin name",
@@ -478,15 +479,22 @@ func TestNewFrameworkErrors(t *testing.T) {
 			},
 			wantErr: "repeated config for plugin",
 		},
+		{
+			name:         "invalid host selector",
+			hostSelector: &config.HostSelector{Type: config.TopKHostSelector},
+			wantErr:      "initializing host selector",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			profile := &config.KubeSchedulerProfile{
-				Plugins:      tc.plugins,
-				PluginConfig: tc.pluginCfg,
+				SchedulerName: testProfileName,
+				Plugins:       tc.plugins,
+				PluginConfig:  tc.pluginCfg,
 			}
-			_, err := NewFramework(registry, profile)
+			hostSelectors := map[string]*config.HostSelector{testProfileName: tc.hostSelector}
+			_, err := NewFramework(registry, profile, WithHostSelectors(hostSelectors))
 			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
 				t.Errorf("Unexpected error, got %v, expect: %s", err, tc.wantErr)
 			}
@@ -1116,6 +1124,69 @@ func TestRunScorePlugins(t *testing.T) {
 	}
 }
 
+func TestSetScorePluginWeights(t *testing.T) {
+	tests := []struct {
+		name        string
+		weights     map[string]int
+		wantWeights map[string]int
+		wantErr     bool
+	}{
+		{
+			name:        "update one weight",
+			weights:     map[string]int{scoreWithNormalizePlugin2: 5},
+			wantWeights: map[string]int{scorePlugin1: 1, scoreWithNormalizePlugin2: 5},
+		},
+		{
+			name:        "unknown plugin",
+			weights:     map[string]int{scoreWithNormalizePlugin1: 5},
+			wantWeights: map[string]int{scorePlugin1: 1, scoreWithNormalizePlugin2: 2},
+			wantErr:     true,
+		},
+		{
+			name:        "zero weight",
+			weights:     map[string]int{scorePlugin1: 3, scoreWithNormalizePlugin2: 0},
+			wantWeights: map[string]int{scorePlugin1: 1, scoreWithNormalizePlugin2: 2},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := config.KubeSchedulerProfile{
+				Plugins: buildScoreConfigDefaultWeights(scorePlugin1, scoreWithNormalizePlugin2),
+				PluginConfig: []config.PluginConfig{
+					{Name: scorePlugin1, Args: &runtime.Unknown{Raw: []byte(`{ "scoreRes": 1 }`)}},
+					{Name: scoreWithNormalizePlugin2, Args: &runtime.Unknown{Raw: []byte(`{ "scoreRes": 4, "normalizeRes": 4 }`)}},
+				},
+			}
+			f, err := newFrameworkWithQueueSortAndBind(registry, profile)
+			if err != nil {
+				t.Fatalf("Failed to create framework for testing: %v", err)
+			}
+			err = f.SetScorePluginWeights(tt.weights)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Got error %v, want error: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantWeights, f.ScorePluginWeights()); diff != "" {
+				t.Errorf("Unexpected weights (-want,+got):\n%s", diff)
+			}
+
+			res, status := f.RunScorePlugins(context.Background(), state, pod, nodes)
+			if !status.IsSuccess() {
+				t.Fatalf("Unexpected status: %v", status)
+			}
+			for name, w := range tt.wantWeights {
+				wantScore := int64(w)
+				if name == scoreWithNormalizePlugin2 {
+					wantScore *= 4
+				}
+				if got := res[name][0].Score; got != wantScore {
+					t.Errorf("Got score %d for plugin %q, want %d", got, name, wantScore)
+				}
+			}
+		})
+	}
+}
+
 func TestPreFilterPlugins(t *testing.T) {
 	preFilter1 := &TestPreFilterPlugin{}
 	preFilter2 := &TestPreFilterWithExtensionsPlugin{}
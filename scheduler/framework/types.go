@@ -71,7 +71,18 @@ const (
 	CSINode               GVK = "storage.k8s.io/CSINode"
 	CSIDriver             GVK = "storage.k8s.io/CSIDriver"
 	CSIStorageCapacity    GVK = "storage.k8s.io/CSIStorageCapacity"
-	WildCard              GVK = "*"
+	// NodeUtilization is a synthetic resource, whose events are raised when the
+	// node telemetry reports a change in the utilization of a node, rather than
+	// by an informer.
+	NodeUtilization GVK = "NodeUtilization"
+	// NetworkLink is a synthetic resource, whose events are raised when the
+	// node telemetry reports a new or faster link between nodes.
+	NetworkLink GVK = "NetworkLink"
+	// ElasticQuota is a synthetic resource, whose events are raised by the
+	// CapacityScheduling plugin when it notices that the elastic quotas of its
+	// ConfigMap were updated.
+	ElasticQuota GVK = "ElasticQuota"
+	WildCard     GVK = "*"
 )
 
 // ClusterEvent abstracts how a system resource's state gets changed.
@@ -105,6 +116,13 @@ type QueuedPodInfo struct {
 	InitialAttemptTimestamp time.Time
 	// If a Pod failed in a scheduling cycle, record the plugin names it failed by.
 	UnschedulablePlugins sets.String
+	// LastFailureMessage is the message of the error the last scheduling
+	// attempt of the pod failed with.
+	LastFailureMessage string
+	// GatingPlugin is the name of the PreEnqueue plugin holding the pod out of
+	// activeQ, if any, and GatingMessage is the reason it gave.
+	GatingPlugin  string
+	GatingMessage string
 }
 
 // DeepCopy returns a deep copy of the QueuedPodInfo object.
@@ -114,6 +132,9 @@ func (pqi *QueuedPodInfo) DeepCopy() *QueuedPodInfo {
 		Timestamp:               pqi.Timestamp,
 		Attempts:                pqi.Attempts,
 		InitialAttemptTimestamp: pqi.InitialAttemptTimestamp,
+		LastFailureMessage:      pqi.LastFailureMessage,
+		GatingPlugin:            pqi.GatingPlugin,
+		GatingMessage:           pqi.GatingMessage,
 	}
 }
 
@@ -711,7 +732,7 @@ func max(a, b int64) int64 {
 	return b
 }
 
-// resourceRequest = max(sum(podSpec.Containers), podSpec.InitContainers) + overHead
+// resourceRequest = max(sum(podSpec.Containers), podSpec.InitContainers) + overHead + bandwidth annotations
 func calculateResource(pod *v1.Pod) (res Resource, non0CPU int64, non0Mem int64) {
 	resPtr := &res
 	for _, c := range pod.Spec.Containers {
@@ -741,6 +762,7 @@ func calculateResource(pod *v1.Pod) (res Resource, non0CPU int64, non0Mem int64)
 		}
 	}
 
+	AddPodBandwidthRequests(resPtr, pod)
 	return
 }
 
@@ -780,6 +802,7 @@ func (n *NodeInfo) updatePVCRefCounts(pod *v1.Pod, add bool) {
 func (n *NodeInfo) SetNode(node *v1.Node) {
 	n.node = node
 	n.Allocatable = NewResource(node.Status.Allocatable)
+	setNodeBandwidthCapacity(n.Allocatable, node)
 	n.Generation = nextGeneration()
 }
 
@@ -20,9 +20,9 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
-	"math/rand"
 	"net/http"
 	"net/url"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -65,6 +65,17 @@ type ScheduleAlgorithm interface {
 	Schedule(context.Context, []framework.Extender, framework.Framework, *framework.CycleState, *v1.Pod) (scheduleResult ScheduleResult, err error)
 }
 
+// BatchScheduleAlgorithm is a ScheduleAlgorithm that can also place several pods
+// against a single snapshot of the cluster.
+type BatchScheduleAlgorithm interface {
+	ScheduleAlgorithm
+	// ScheduleBatch places the pods one after the other, each with its own cycle
+	// state, taking the placements of the previous pods into account. It stops at
+	// the first pod that can't be placed and returns the results of the pods
+	// placed before it, along with the error.
+	ScheduleBatch(context.Context, []framework.Extender, framework.Framework, []*framework.CycleState, []*v1.Pod) ([]ScheduleResult, error)
+}
+
 // ScheduleResult represents the result of one pod scheduled. It will contain
 // the final selected Node, along with the selected intermediate information.
 type ScheduleResult struct {
@@ -74,6 +85,9 @@ type ScheduleResult struct {
 	EvaluatedNodes int
 	// Number of feasible nodes on one pod scheduled
 	FeasibleNodes int
+	// Other feasible nodes, ranked by score with the best first, that the pod can
+	// fall back to if SuggestedHost rejects it at Reserve or Permit.
+	FallbackHosts []string
 }
 
 type genericScheduler struct {
@@ -81,6 +95,12 @@ type genericScheduler struct {
 	nodeInfoSnapshot         *internalcache.Snapshot
 	percentageOfNodesToScore int32
 	nextStartNodeIndex       int
+	// deterministic makes the filtering phase collect feasible nodes in the order
+	// they are visited, independently of how the parallel checks interleave.
+	deterministic bool
+	// numFallbackHosts is the maximum number of ranked fallback hosts returned
+	// along with the suggested host.
+	numFallbackHosts int
 }
 
 // snapshot snapshots scheduler cache and node infos for all fit and priority
@@ -94,14 +114,43 @@ func (g *genericScheduler) snapshot() error {
 // If it succeeds, it will return the name of the node.
 // If it fails, it will return a FitError error with reasons.
 func (g *genericScheduler) Schedule(ctx context.Context, extenders []framework.Extender, fwk framework.Framework, state *framework.CycleState, pod *v1.Pod) (result ScheduleResult, err error) {
-	trace := utiltrace.New("Scheduling", utiltrace.Field{Key: "namespace", Value: pod.Namespace}, utiltrace.Field{Key: "name", Value: pod.Name})
-	fmt.Printf("[INFO] PodInfo: namespce: %v, name: %v\n\n", pod.Namespace, pod.Name)
-	defer trace.LogIfLong(100 * time.Millisecond)
-
 	if err := g.snapshot(); err != nil {
 		return result, err
 	}
-	trace.Step("Snapshotting scheduler cache and node infos done")
+	return g.schedulePod(ctx, extenders, fwk, state, pod)
+}
+
+// ScheduleBatch places the given pods against a single snapshot. Each placement
+// is assumed into the snapshot before the next pod is scheduled. The snapshot
+// is restored before returning, whether all the pods were placed or not: the
+// placements are only kept once they are assumed in the cache, which a pod
+// rejected at Reserve or Permit never is.
+func (g *genericScheduler) ScheduleBatch(ctx context.Context, extenders []framework.Extender, fwk framework.Framework, states []*framework.CycleState, pods []*v1.Pod) ([]ScheduleResult, error) {
+	if err := g.snapshot(); err != nil {
+		return nil, err
+	}
+	defer g.nodeInfoSnapshot.Restore()
+	results := make([]ScheduleResult, 0, len(pods))
+	for i, pod := range pods {
+		result, err := g.schedulePod(ctx, extenders, fwk, states[i], pod)
+		if err != nil {
+			return results, err
+		}
+		assumed := pod.DeepCopy()
+		assumed.Spec.NodeName = result.SuggestedHost
+		if err := g.nodeInfoSnapshot.AssumePod(assumed); err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// schedulePod schedules the given pod against the current snapshot.
+func (g *genericScheduler) schedulePod(ctx context.Context, extenders []framework.Extender, fwk framework.Framework, state *framework.CycleState, pod *v1.Pod) (result ScheduleResult, err error) {
+	trace := utiltrace.New("Scheduling", utiltrace.Field{Key: "namespace", Value: pod.Namespace}, utiltrace.Field{Key: "name", Value: pod.Name})
+	fmt.Printf("[INFO] PodInfo: namespce: %v, name: %v\n\n", pod.Namespace, pod.Name)
+	defer trace.LogIfLong(100 * time.Millisecond)
 
 	if g.nodeInfoSnapshot.NumNodes() == 0 {
 		return result, ErrNoNodesAvailable
@@ -130,6 +179,9 @@ func (g *genericScheduler) Schedule(ctx context.Context, extenders []framework.E
 		}, nil
 	}
 
+	// The DQN agent narrows feasibleNodes down to the node it chooses, but the
+	// pod can still fall back to the other feasible nodes.
+	allFeasibleNodes := append([]*v1.Node(nil), feasibleNodes...)
 	choose := " "
 	schedulerurl := "http://192.168.1.113:1234/choose"
 	urlValues := url.Values{}
@@ -152,44 +204,73 @@ func (g *genericScheduler) Schedule(ctx context.Context, extenders []framework.E
 
 	fmt.Printf("[INFO] Nodes after dqn: %v\n\n", feasibleNodes)
 
-	priorityList, err := prioritizeNodes(ctx, extenders, fwk, state, pod, feasibleNodes)
+	// Fallback hosts are ranked among all the feasible nodes, so they are all
+	// scored, but the host is still selected among the ones the agent chose.
+	scoredNodes := feasibleNodes
+	if g.numFallbackHosts > 0 {
+		scoredNodes = allFeasibleNodes
+	}
+	priorityList, err := prioritizeNodes(ctx, extenders, fwk, state, pod, scoredNodes)
 	if err != nil {
 		return result, err
 	}
 
-	host, err := g.selectHost(priorityList)
+	host, err := fwk.HostSelector().Select(fwk.Rand(), selectableNodes(priorityList, feasibleNodes))
 	trace.Step("Prioritizing done")
+	if err == nil {
+		klog.V(4).InfoS("Selected host for pod", "pod", klog.KObj(pod), "node", host, "hostSelector", fwk.HostSelector().Name())
+	}
 
 	return ScheduleResult{
 		SuggestedHost:  host,
 		EvaluatedNodes: len(feasibleNodes) + len(diagnosis.NodeToStatusMap),
 		FeasibleNodes:  len(feasibleNodes),
+		FallbackHosts:  rankFallbackHosts(priorityList, host, g.numFallbackHosts),
 	}, err
 }
 
-// selectHost takes a prioritized list of nodes and then picks one
-// in a reservoir sampling manner from the nodes that had the highest score.
-func (g *genericScheduler) selectHost(nodeScoreList framework.NodeScoreList) (string, error) {
-	if len(nodeScoreList) == 0 {
-		return "", fmt.Errorf("empty priorityList")
-	}
-	maxScore := nodeScoreList[0].Score
-	selected := nodeScoreList[0].Name
-	cntOfMaxScore := 1
-	for _, ns := range nodeScoreList[1:] {
-		if ns.Score > maxScore {
-			maxScore = ns.Score
-			selected = ns.Name
-			cntOfMaxScore = 1
-		} else if ns.Score == maxScore {
-			cntOfMaxScore++
-			if rand.Intn(cntOfMaxScore) == 0 {
-				// Replace the candidate with probability of 1/cntOfMaxScore
-				selected = ns.Name
-			}
+// selectableNodes returns the entries of the priority list of the given nodes.
+func selectableNodes(priorityList framework.NodeScoreList, nodes []*v1.Node) framework.NodeScoreList {
+	if len(priorityList) == len(nodes) {
+		return priorityList
+	}
+	names := sets.NewString()
+	for _, n := range nodes {
+		names.Insert(n.Name)
+	}
+	selectable := make(framework.NodeScoreList, 0, len(nodes))
+	for _, ns := range priorityList {
+		if names.Has(ns.Name) {
+			selectable = append(selectable, ns)
+		}
+	}
+	return selectable
+}
+
+// rankFallbackHosts returns up to n nodes from the priority list, other than
+// host, sorted by decreasing score. Nodes with equal scores keep their order in
+// the list.
+func rankFallbackHosts(priorityList framework.NodeScoreList, host string, n int) []string {
+	if n <= 0 || len(priorityList) <= 1 {
+		return nil
+	}
+	ranked := make(framework.NodeScoreList, 0, len(priorityList)-1)
+	for _, ns := range priorityList {
+		if ns.Name != host {
+			ranked = append(ranked, ns)
 		}
 	}
-	return selected, nil
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	hosts := make([]string, len(ranked))
+	for i, ns := range ranked {
+		hosts[i] = ns.Name
+	}
+	return hosts
 }
 
 // numFeasibleNodesToFind returns the number of feasible nodes that once found, the scheduler stops
@@ -336,6 +417,10 @@ func (g *genericScheduler) findNodesThatPassFilters(
 		return feasibleNodes, nil
 	}
 
+	if g.deterministic {
+		return g.findNodesThatPassFiltersInOrder(ctx, fwk, state, pod, diagnosis, nodes, numNodesToFind)
+	}
+
 	errCh := parallelize.NewErrorChannel()
 	var statusesLock sync.Mutex
 	var feasibleNodesLen int32
@@ -388,6 +473,63 @@ func (g *genericScheduler) findNodesThatPassFilters(
 	return feasibleNodes, nil
 }
 
+// findNodesThatPassFiltersInOrder is the deterministic counterpart of the parallel
+// search in findNodesThatPassFilters. Filters still run in parallel, but every node
+// is checked and the results are consumed in visiting order, so that the feasible
+// nodes found, their order and the next start index only depend on the inputs.
+func (g *genericScheduler) findNodesThatPassFiltersInOrder(
+	ctx context.Context,
+	fwk framework.Framework,
+	state *framework.CycleState,
+	pod *v1.Pod,
+	diagnosis framework.Diagnosis,
+	nodes []*framework.NodeInfo,
+	numNodesToFind int32) ([]*v1.Node, error) {
+	errCh := parallelize.NewErrorChannel()
+	statuses := make([]*framework.Status, len(nodes))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	checkNode := func(i int) {
+		nodeInfo := nodes[(g.nextStartNodeIndex+i)%len(nodes)]
+		status := fwk.RunFilterPluginsWithNominatedPods(ctx, state, pod, nodeInfo)
+		if status.Code() == framework.Error {
+			errCh.SendErrorWithCancel(status.AsError(), cancel)
+			return
+		}
+		statuses[i] = status
+	}
+
+	beginCheckNode := time.Now()
+	statusCode := framework.Success
+	defer func() {
+		metrics.FrameworkExtensionPointDuration.WithLabelValues(runtime.Filter, statusCode.String(), fwk.ProfileName()).Observe(metrics.SinceInSeconds(beginCheckNode))
+	}()
+
+	fwk.Parallelizer().Until(ctx, len(nodes), checkNode)
+	if err := errCh.ReceiveError(); err != nil {
+		statusCode = framework.Error
+		return nil, err
+	}
+
+	feasibleNodes := make([]*v1.Node, 0, numNodesToFind)
+	processedNodes := 0
+	for i := range statuses {
+		if int32(len(feasibleNodes)) >= numNodesToFind {
+			break
+		}
+		processedNodes++
+		nodeInfo := nodes[(g.nextStartNodeIndex+i)%len(nodes)]
+		if statuses[i].IsSuccess() {
+			feasibleNodes = append(feasibleNodes, nodeInfo.Node())
+			continue
+		}
+		diagnosis.NodeToStatusMap[nodeInfo.Node().Name] = statuses[i]
+		diagnosis.UnschedulablePlugins.Insert(statuses[i].FailedPlugin())
+	}
+	g.nextStartNodeIndex = (g.nextStartNodeIndex + processedNodes) % len(nodes)
+	return feasibleNodes, nil
+}
+
 func findNodesThatPassExtenders(extenders []framework.Extender, pod *v1.Pod, feasibleNodes []*v1.Node, statuses framework.NodeToStatusMap) ([]*v1.Node, error) {
 	// Extenders are called sequentially.
 	// Nodes in original feasibleNodes can be excluded in one extender, and pass on to the next
@@ -545,14 +687,21 @@ func prioritizeNodes(
 	return result, nil
 }
 
-// NewGenericScheduler creates a genericScheduler object.
+// NewGenericScheduler creates a genericScheduler object. When deterministic is
+// true, feasible nodes are collected in a stable order so that, together with a
+// seeded framework, identical inputs produce identical results. Up to
+// numFallbackHosts ranked alternatives are returned with every suggested host.
 func NewGenericScheduler(
 	cache internalcache.Cache,
 	nodeInfoSnapshot *internalcache.Snapshot,
-	percentageOfNodesToScore int32) ScheduleAlgorithm {
+	percentageOfNodesToScore int32,
+	deterministic bool,
+	numFallbackHosts int) ScheduleAlgorithm {
 	return &genericScheduler{
 		cache:                    cache,
 		nodeInfoSnapshot:         nodeInfoSnapshot,
 		percentageOfNodesToScore: percentageOfNodesToScore,
+		deterministic:            deterministic,
+		numFallbackHosts:         numFallbackHosts,
 	}
 }
@@ -204,76 +204,6 @@ func makeNodeList(nodeNames []string) []*v1.Node {
 	return result
 }
 
-func TestSelectHost(t *testing.T) {
-	scheduler := genericScheduler{}
-	tests := []struct {
-		name          string
-		list          framework.NodeScoreList
-		possibleHosts sets.String
-		expectsErr    bool
-	}{
-		{
-			name: "unique properly ordered scores",
-			list: []framework.NodeScore{
-				{Name: "machine1.1", Score: 1},
-				{Name: "machine2.1", Score: 2},
-			},
-			possibleHosts: sets.NewString("machine2.1"),
-			expectsErr:    false,
-		},
-		{
-			name: "equal scores",
-			list: []framework.NodeScore{
-				{Name: "machine1.1", Score: 1},
-				{Name: "machine1.2", Score: 2},
-				{Name: "machine1.3", Score: 2},
-				{Name: "machine2.1", Score: 2},
-			},
-			possibleHosts: sets.NewString("machine1.2", "machine1.3", "machine2.1"),
-			expectsErr:    false,
-		},
-		{
-			name: "out of order scores",
-			list: []framework.NodeScore{
-				{Name: "machine1.1", Score: 3},
-				{Name: "machine1.2", Score: 3},
-				{Name: "machine2.1", Score: 2},
-				{Name: "machine3.1", Score: 1},
-				{Name: "machine1.3", Score: 3},
-			},
-			possibleHosts: sets.NewString("machine1.1", "machine1.2", "machine1.3"),
-			expectsErr:    false,
-		},
-		{
-			name:          "empty priority list",
-			list:          []framework.NodeScore{},
-			possibleHosts: sets.NewString(),
-			expectsErr:    true,
-		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			// increase the randomness
-			for i := 0; i < 10; i++ {
-				got, err := scheduler.selectHost(test.list)
-				if test.expectsErr {
-					if err == nil {
-						t.Error("Unexpected non-error")
-					}
-				} else {
-					if err != nil {
-						t.Errorf("Unexpected error: %v", err)
-					}
-					if !test.possibleHosts.Has(got) {
-						t.Errorf("got %s is not in the possible map %v", got, test.possibleHosts)
-					}
-				}
-			}
-		})
-	}
-}
-
 func TestFindNodesThatPassExtenders(t *testing.T) {
 	tests := []struct {
 		name                  string
@@ -410,6 +340,20 @@ func TestFindNodesThatPassExtenders(t *testing.T) {
 				"c": framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("FakeExtender: node %q failed and unresolvable", "c")),
 			},
 		},
+		{
+			name: "error of extender after all nodes are filtered out",
+			extenders: []st.FakeExtender{
+				{Predicates: []st.FitPredicate{st.FalsePredicateExtender}},
+				{Predicates: []st.FitPredicate{st.ErrorPredicateExtender}},
+			},
+			nodes:                 makeNodeList([]string{"a"}),
+			filteredNodesStatuses: make(framework.NodeToStatusMap),
+			expectsErr:            false,
+			expectedNodes:         []*v1.Node{},
+			expectedStatuses: framework.NodeToStatusMap{
+				"a": framework.NewStatus(framework.Unschedulable, fmt.Sprintf("FakeExtender: node %q failed", "a")),
+			},
+		},
 	}
 
 	cmpOpts := []cmp.Option{
@@ -1005,6 +949,7 @@ func TestGenericScheduler(t *testing.T) {
 				cache,
 				snapshot,
 				schedulerapi.DefaultPercentageOfNodesToScore,
+				false, 0,
 			)
 			informerFactory.Start(ctx.Done())
 			informerFactory.WaitForCacheSync(ctx.Done())
@@ -1041,7 +986,7 @@ func makeScheduler(nodes []*v1.Node) *genericScheduler {
 	s := NewGenericScheduler(
 		cache,
 		emptySnapshot,
-		schedulerapi.DefaultPercentageOfNodesToScore)
+		schedulerapi.DefaultPercentageOfNodesToScore, false, 0)
 	cache.UpdateSnapshot(s.(*genericScheduler).nodeInfoSnapshot)
 	return s.(*genericScheduler)
 }
@@ -1192,6 +1137,53 @@ func TestFindFitPredicateCallCounts(t *testing.T) {
 	}
 }
 
+func TestScheduleBatchRestoresSnapshot(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	cache := internalcache.New(time.Minute, stop)
+	cache.AddNode(makeNode("machine1", 2000, 1000))
+	snapshot := internalcache.NewEmptySnapshot()
+	fwk, err := st.NewFramework(
+		[]st.RegisterPluginFunc{
+			st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			st.RegisterPluginAsExtensions(noderesources.FitName, frameworkruntime.FactoryAdapter(feature.Features{}, noderesources.NewFit), "Filter", "PreFilter"),
+			st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		}, "",
+		frameworkruntime.WithSnapshotSharedLister(snapshot),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduler := NewGenericScheduler(cache, snapshot, schedulerapi.DefaultPercentageOfNodesToScore, false, 0).(*genericScheduler)
+
+	// The node fits two of the pods, so that the third one only fails if the
+	// placements of the first two were taken into account.
+	var pods []*v1.Pod
+	var states []*framework.CycleState
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("pod%d", i)
+		pods = append(pods, st.MakePod().Name(name).UID(name).Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj())
+		states = append(states, framework.NewCycleState())
+	}
+	results, err := scheduler.ScheduleBatch(context.Background(), nil, fwk, states, pods)
+	if _, ok := err.(*framework.FitError); !ok {
+		t.Fatalf("Got error %v, want a FitError for the third pod", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Got %d results, want 2", len(results))
+	}
+
+	// None of the pods was assumed in the cache, so none of them must be left
+	// in the snapshot.
+	nodeInfo, err := snapshot.NodeInfos().Get("machine1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodeInfo.Pods) != 0 {
+		t.Errorf("Got pods %v on the node after the batch, want none", nodeInfo.Pods)
+	}
+}
+
 func makeNode(node string, milliCPU, memory int64) *v1.Node {
 	return &v1.Node{
 		ObjectMeta: metav1.ObjectMeta{Name: node},
@@ -1333,7 +1325,7 @@ func TestZeroRequest(t *testing.T) {
 			scheduler := NewGenericScheduler(
 				nil,
 				emptySnapshot,
-				schedulerapi.DefaultPercentageOfNodesToScore).(*genericScheduler)
+				schedulerapi.DefaultPercentageOfNodesToScore, false, 0).(*genericScheduler)
 			scheduler.nodeInfoSnapshot = snapshot
 
 			ctx := context.Background()
@@ -1451,6 +1443,130 @@ func TestFairEvaluationForNodes(t *testing.T) {
 	}
 }
 
+func TestDeterministicEvaluationForNodes(t *testing.T) {
+	numAllNodes := 300
+	nodeNames := make([]string, 0, numAllNodes)
+	failedNodes := make(map[string]framework.Code)
+	for i := 0; i < numAllNodes; i++ {
+		nodeNames = append(nodeNames, strconv.Itoa(i))
+		if i%3 == 0 {
+			failedNodes[strconv.Itoa(i)] = framework.Unschedulable
+		}
+	}
+	nodes := makeNodeList(nodeNames)
+	g := makeScheduler(nodes)
+	g.deterministic = true
+	fwk, err := st.NewFramework(
+		[]st.RegisterPluginFunc{
+			st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			st.RegisterFilterPlugin("FakeFilter", st.NewFakeFilterPlugin(failedNodes)),
+			st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		},
+		"",
+		frameworkruntime.WithPodNominator(internalqueue.NewPodNominator(nil)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allNodes, err := g.nodeInfoSnapshot.NodeInfos().List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodesToFind := int(g.numFeasibleNodesToFind(int32(numAllNodes)))
+
+	// Iterating over all nodes more than twice
+	start := 0
+	for i := 0; i < 2*(numAllNodes/nodesToFind+1); i++ {
+		var want []string
+		processed := 0
+		for len(want) < nodesToFind {
+			name := allNodes[(start+processed)%numAllNodes].Node().Name
+			if _, ok := failedNodes[name]; !ok {
+				want = append(want, name)
+			}
+			processed++
+		}
+		start = (start + processed) % numAllNodes
+
+		nodesThatFit, diagnosis, err := g.findNodesThatFitPod(context.Background(), nil, fwk, framework.NewCycleState(), &v1.Pod{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got []string
+		for _, n := range nodesThatFit {
+			got = append(got, n.Name)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("cycle %d: unexpected feasible nodes (-want, +got):\n%s", i, diff)
+		}
+		if len(diagnosis.NodeToStatusMap) != processed-nodesToFind {
+			t.Errorf("cycle %d: got %d failed nodes, want %d", i, len(diagnosis.NodeToStatusMap), processed-nodesToFind)
+		}
+		if g.nextStartNodeIndex != start {
+			t.Errorf("cycle %d: got %d nextStartNodeIndex, want %d", i, g.nextStartNodeIndex, start)
+		}
+	}
+}
+
+func TestRankFallbackHosts(t *testing.T) {
+	priorityList := framework.NodeScoreList{
+		{Name: "node1", Score: 10},
+		{Name: "node2", Score: 30},
+		{Name: "node3", Score: 20},
+		{Name: "node4", Score: 30},
+		{Name: "node5", Score: 20},
+	}
+	tests := []struct {
+		name string
+		host string
+		n    int
+		want []string
+	}{
+		{
+			name: "disabled",
+			host: "node2",
+		},
+		{
+			name: "ranked by score, ties in list order",
+			host: "node2",
+			n:    3,
+			want: []string{"node4", "node3", "node5"},
+		},
+		{
+			name: "more candidates than nodes",
+			host: "node3",
+			n:    10,
+			want: []string{"node2", "node4", "node5", "node1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rankFallbackHosts(priorityList, tt.host, tt.n)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unexpected fallback hosts (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSelectableNodes(t *testing.T) {
+	// All the feasible nodes are scored when the DQN agent narrowed them down,
+	// so that the fallback hosts are ranked among all of them.
+	priorityList := framework.NodeScoreList{
+		{Name: "node1", Score: 10},
+		{Name: "node2", Score: 30},
+		{Name: "node3", Score: 20},
+	}
+	chosen := []*v1.Node{st.MakeNode().Name("node3").Obj()}
+	selectable := selectableNodes(priorityList, chosen)
+	if diff := cmp.Diff(framework.NodeScoreList{{Name: "node3", Score: 20}}, selectable); diff != "" {
+		t.Errorf("Unexpected selectable nodes (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"node2", "node1"}, rankFallbackHosts(priorityList, "node3", 2)); diff != "" {
+		t.Errorf("Unexpected fallback hosts (-want, +got):\n%s", diff)
+	}
+}
+
 func TestPreferNominatedNodeFilterCallCounts(t *testing.T) {
 	tests := []struct {
 		name                  string
@@ -1522,7 +1638,7 @@ func TestPreferNominatedNodeFilterCallCounts(t *testing.T) {
 			scheduler := NewGenericScheduler(
 				cache,
 				snapshot,
-				schedulerapi.DefaultPercentageOfNodesToScore).(*genericScheduler)
+				schedulerapi.DefaultPercentageOfNodesToScore, false, 0).(*genericScheduler)
 
 			_, _, err = scheduler.findNodesThatFitPod(context.Background(), nil, fwk, framework.NewCycleState(), test.pod)
 
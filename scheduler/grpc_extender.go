@@ -0,0 +1,301 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	grpcextenderv1 "k8s.io/kubernetes/pkg/scheduler/apis/extender/v1alpha1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// GRPCExtender implements the Extender interface by calling the Extender gRPC
+// service. A verb of the configuration only enables the matching call.
+type GRPCExtender struct {
+	target           string
+	preempt          bool
+	filter           bool
+	prioritize       bool
+	bind             bool
+	weight           int64
+	timeout          time.Duration
+	client           grpcextenderv1.ExtenderClient
+	nodeCacheCapable bool
+	managedResources sets.String
+	ignorable        bool
+}
+
+// NewGRPCExtender creates a GRPCExtender object. The URL prefix of the
+// configuration is the gRPC target of the extender.
+func NewGRPCExtender(config *schedulerapi.Extender) (framework.Extender, error) {
+	if config.HTTPTimeout.Duration.Nanoseconds() == 0 {
+		config.HTTPTimeout.Duration = time.Duration(DefaultExtenderTimeout)
+	}
+
+	creds := insecure.NewCredentials()
+	if config.EnableHTTPS {
+		tlsConfig, err := makeTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.Dial(config.URLPrefix, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	managedResources := sets.NewString()
+	for _, r := range config.ManagedResources {
+		managedResources.Insert(string(r.Name))
+	}
+	return &GRPCExtender{
+		target:           config.URLPrefix,
+		preempt:          config.PreemptVerb != "",
+		filter:           config.FilterVerb != "",
+		prioritize:       config.PrioritizeVerb != "",
+		bind:             config.BindVerb != "",
+		weight:           config.Weight,
+		timeout:          config.HTTPTimeout.Duration,
+		client:           grpcextenderv1.NewExtenderClient(conn),
+		nodeCacheCapable: config.NodeCacheCapable,
+		managedResources: managedResources,
+		ignorable:        config.Ignorable,
+	}, nil
+}
+
+// Name returns the gRPC target to identify the extender.
+func (g *GRPCExtender) Name() string {
+	return g.target
+}
+
+// IsIgnorable returns true indicates scheduling should not fail when this extender
+// is unavailable
+func (g *GRPCExtender) IsIgnorable() bool {
+	return g.ignorable
+}
+
+// SupportsPreemption returns true if an extender supports preemption.
+func (g *GRPCExtender) SupportsPreemption() bool {
+	return g.preempt
+}
+
+// ProcessPreemption returns filtered candidate nodes and victims after running preemption logic in extender.
+func (g *GRPCExtender) ProcessPreemption(
+	pod *v1.Pod,
+	nodeNameToVictims map[string]*extenderv1.Victims,
+	nodeInfos framework.NodeInfoLister,
+) (map[string]*extenderv1.Victims, error) {
+	if !g.SupportsPreemption() {
+		return nil, fmt.Errorf("preempt verb is not defined for extender %v but run into ProcessPreemption", g.target)
+	}
+
+	podData, err := pod.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	args := &grpcextenderv1.ExtenderPreemptionArgs{
+		Pod:               podData,
+		NodeNameToVictims: make(map[string]*grpcextenderv1.Victims, len(nodeNameToVictims)),
+	}
+	for nodeName, victims := range nodeNameToVictims {
+		v := &grpcextenderv1.Victims{NumPdbViolations: victims.NumPDBViolations}
+		for _, p := range victims.Pods {
+			v.PodUids = append(v.PodUids, string(p.UID))
+			// If extender has cached node info, the UIDs of the pods are enough.
+			if !g.nodeCacheCapable {
+				data, err := p.Marshal()
+				if err != nil {
+					return nil, err
+				}
+				v.Pods = append(v.Pods, data)
+			}
+		}
+		args.NodeNameToVictims[nodeName] = v
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	result, err := g.client.ProcessPreemption(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// The extender always returns the UIDs of the victims.
+	// So let's convert them to pods by using <nodeInfos>.
+	nodeNameToMetaVictims := make(map[string]*extenderv1.MetaVictims, len(result.NodeNameToVictims))
+	for nodeName, victims := range result.NodeNameToVictims {
+		metaVictims := &extenderv1.MetaVictims{
+			Pods:             []*extenderv1.MetaPod{},
+			NumPDBViolations: victims.NumPdbViolations,
+		}
+		for _, uid := range victims.PodUids {
+			metaVictims.Pods = append(metaVictims.Pods, &extenderv1.MetaPod{UID: uid})
+		}
+		nodeNameToMetaVictims[nodeName] = metaVictims
+	}
+	newNodeNameToVictims, err := convertToNodeNameToVictims(g.target, nodeNameToMetaVictims, nodeInfos)
+	if err != nil {
+		return nil, err
+	}
+	for nodeName, victims := range newNodeNameToVictims {
+		victims.NumPDBViolations = nodeNameToMetaVictims[nodeName].NumPDBViolations
+	}
+	return newNodeNameToVictims, nil
+}
+
+// Filter based on extender implemented predicate functions. The filtered list is
+// expected to be a subset of the supplied list; otherwise the function returns an error.
+// The failedNodes and failedAndUnresolvableNodes optionally contains the list
+// of failed nodes and failure reasons, except nodes in the latter are
+// unresolvable.
+func (g *GRPCExtender) Filter(
+	pod *v1.Pod,
+	nodes []*v1.Node,
+) (filteredList []*v1.Node, failedNodes, failedAndUnresolvableNodes extenderv1.FailedNodesMap, err error) {
+	if !g.filter {
+		return nodes, extenderv1.FailedNodesMap{}, extenderv1.FailedNodesMap{}, nil
+	}
+
+	args, err := g.args(pod, nodes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	result, err := g.client.Filter(ctx, args)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if result.Error != "" {
+		return nil, nil, nil, fmt.Errorf(result.Error)
+	}
+
+	fromNodeName := make(map[string]*v1.Node)
+	for _, n := range nodes {
+		fromNodeName[n.Name] = n
+	}
+	nodeResult := make([]*v1.Node, len(result.NodeNames))
+	for i, nodeName := range result.NodeNames {
+		n, ok := fromNodeName[nodeName]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf(
+				"extender %q claims a filtered node %q which is not found in the input node list",
+				g.target, nodeName)
+		}
+		nodeResult[i] = n
+	}
+	return nodeResult, result.FailedNodes, result.FailedAndUnresolvableNodes, nil
+}
+
+// Prioritize based on extender implemented priority functions. Weight*priority is added
+// up for each such priority function. The returned score is added to the score computed
+// by Kubernetes scheduler. The total score is used to do the host selection.
+func (g *GRPCExtender) Prioritize(pod *v1.Pod, nodes []*v1.Node) (*extenderv1.HostPriorityList, int64, error) {
+	result := extenderv1.HostPriorityList{}
+	if !g.prioritize {
+		for _, node := range nodes {
+			result = append(result, extenderv1.HostPriority{Host: node.Name, Score: 0})
+		}
+		return &result, 0, nil
+	}
+
+	args, err := g.args(pod, nodes)
+	if err != nil {
+		return nil, 0, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	priorities, err := g.client.Prioritize(ctx, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, p := range priorities.Priorities {
+		result = append(result, extenderv1.HostPriority{Host: p.Host, Score: p.Score})
+	}
+	return &result, g.weight, nil
+}
+
+// Bind delegates the action of binding a pod to a node to the extender.
+func (g *GRPCExtender) Bind(binding *v1.Binding) error {
+	if !g.IsBinder() {
+		// This shouldn't happen as this extender wouldn't have become a Binder.
+		return fmt.Errorf("unexpected empty bindVerb in extender")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	result, err := g.client.Bind(ctx, &grpcextenderv1.ExtenderBindingArgs{
+		PodName:      binding.Name,
+		PodNamespace: binding.Namespace,
+		PodUid:       string(binding.UID),
+		Node:         binding.Target.Name,
+	})
+	if err != nil {
+		return err
+	}
+	if result.Error != "" {
+		return fmt.Errorf(result.Error)
+	}
+	return nil
+}
+
+// IsBinder returns whether this extender is configured for the Bind method.
+func (g *GRPCExtender) IsBinder() bool {
+	return g.bind
+}
+
+// IsInterested returns true if at least one extended resource requested by
+// this pod is managed by this extender.
+func (g *GRPCExtender) IsInterested(pod *v1.Pod) bool {
+	return isInterested(g.managedResources, pod)
+}
+
+// args returns the arguments of the Filter and Prioritize calls, with the
+// names of the nodes only if the extender caches them.
+func (g *GRPCExtender) args(pod *v1.Pod, nodes []*v1.Node) (*grpcextenderv1.ExtenderArgs, error) {
+	podData, err := pod.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	args := &grpcextenderv1.ExtenderArgs{
+		Pod:       podData,
+		NodeNames: make([]string, 0, len(nodes)),
+	}
+	for _, node := range nodes {
+		args.NodeNames = append(args.NodeNames, node.Name)
+		if !g.nodeCacheCapable {
+			data, err := node.Marshal()
+			if err != nil {
+				return nil, err
+			}
+			args.Nodes = append(args.Nodes, data)
+		}
+	}
+	return args, nil
+}
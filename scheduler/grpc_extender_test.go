@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func TestGRPCExtender(t *testing.T) {
+	nodes := []*v1.Node{createNode("node1"), createNode("node2")}
+	pods := []*v1.Pod{
+		st.MakePod().Name("low1").UID("low1").Node("node1").Priority(1).Obj(),
+		st.MakePod().Name("low2").UID("low2").Node("node2").Priority(1).Obj(),
+	}
+	pod := st.MakePod().Name("p").UID("p").Priority(10).Obj()
+
+	for _, nodeCacheCapable := range []bool{false, true} {
+		t.Run(map[bool]string{false: "nodes", true: "node cache"}[nodeCacheCapable], func(t *testing.T) {
+			fake := &st.FakeGRPCExtender{
+				Extender: &st.FakeExtender{
+					Predicates:   []st.FitPredicate{st.Node1PredicateExtender},
+					Prioritizers: []st.PriorityConfig{{Function: st.Node1PrioritizerExtender, Weight: 1}},
+				},
+				Nodes: nodes,
+				Pods:  pods,
+			}
+			addr, err := fake.Start()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer fake.Stop()
+			extender, err := NewGRPCExtender(&schedulerapi.Extender{
+				URLPrefix:        addr,
+				Transport:        schedulerapi.ExtenderTransportGRPC,
+				FilterVerb:       "filter",
+				PrioritizeVerb:   "prioritize",
+				BindVerb:         "bind",
+				PreemptVerb:      "preempt",
+				Weight:           2,
+				NodeCacheCapable: nodeCacheCapable,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			filtered, failed, _, err := extender.Filter(pod, nodes)
+			if err != nil {
+				t.Fatalf("Filter: %v", err)
+			}
+			if diff := cmp.Diff([]*v1.Node{nodes[0]}, filtered); diff != "" {
+				t.Errorf("Unexpected filtered nodes (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(extenderv1.FailedNodesMap{"node2": `FakeExtender: node "node2" failed`}, failed); diff != "" {
+				t.Errorf("Unexpected failed nodes (-want,+got):\n%s", diff)
+			}
+
+			priorities, weight, err := extender.Prioritize(pod, nodes)
+			if err != nil {
+				t.Fatalf("Prioritize: %v", err)
+			}
+			sort.Slice(*priorities, func(i, j int) bool { return (*priorities)[i].Host < (*priorities)[j].Host })
+			wantPriorities := &extenderv1.HostPriorityList{{Host: "node1", Score: 10}, {Host: "node2", Score: 1}}
+			if diff := cmp.Diff(wantPriorities, priorities); diff != "" {
+				t.Errorf("Unexpected priorities (-want,+got):\n%s", diff)
+			}
+			if weight != 2 {
+				t.Errorf("Got weight %v, want 2", weight)
+			}
+
+			err = extender.Bind(&v1.Binding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID},
+				Target:     v1.ObjectReference{Kind: "Node", Name: "node2"},
+			})
+			if err == nil {
+				t.Errorf("Bind to a node that was filtered out succeeded, want error")
+			}
+
+			snapshot := internalcache.NewSnapshot(pods, nodes)
+			victims, err := extender.ProcessPreemption(pod, map[string]*extenderv1.Victims{
+				"node1": {Pods: []*v1.Pod{pods[0]}, NumPDBViolations: 1},
+				"node2": {Pods: []*v1.Pod{pods[1]}},
+			}, snapshot.NodeInfos())
+			if err != nil {
+				t.Fatalf("ProcessPreemption: %v", err)
+			}
+			wantVictims := map[string]*extenderv1.Victims{
+				"node1": {Pods: []*v1.Pod{pods[0]}, NumPDBViolations: 1},
+			}
+			if diff := cmp.Diff(wantVictims, victims); diff != "" {
+				t.Errorf("Unexpected victims (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGRPCExtenderUnavailable(t *testing.T) {
+	extender, err := NewGRPCExtender(&schedulerapi.Extender{
+		URLPrefix:  "127.0.0.1:1",
+		Transport:  schedulerapi.ExtenderTransportGRPC,
+		FilterVerb: "filter",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := extender.Filter(st.MakePod().Name("p").Obj(), []*v1.Node{createNode("node1")}); err == nil {
+		t.Errorf("Filter with an unavailable extender succeeded, want error")
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/internal/queue"
+)
+
+// PendingPod describes a pod pending in the scheduling queue.
+type PendingPod struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+	// Profile is the name of the profile the pod is scheduled with.
+	Profile string `json:"profile"`
+	// Queue is the sub-queue the pod is in: active, backoff, unschedulable
+	// or gated.
+	Queue                   string     `json:"queue"`
+	Attempts                int        `json:"attempts"`
+	InitialAttemptTimestamp time.Time  `json:"initialAttemptTimestamp"`
+	BackoffExpiry           *time.Time `json:"backoffExpiry,omitempty"`
+	UnschedulablePlugins    []string   `json:"unschedulablePlugins,omitempty"`
+	LastFailureMessage      string     `json:"lastFailureMessage,omitempty"`
+	// GatingPlugin is the PreEnqueue plugin holding a gated pod out of the
+	// active queue, and GatingMessage the reason it gave.
+	GatingPlugin  string `json:"gatingPlugin,omitempty"`
+	GatingMessage string `json:"gatingMessage,omitempty"`
+}
+
+// queueOrder is the order sub-queues are listed in.
+var queueOrder = map[string]int{
+	queue.ActiveQName:        0,
+	queue.BackoffQName:       1,
+	queue.UnschedulableQName: 2,
+	queue.GatedQName:         3,
+}
+
+// PendingPodsHandler serves the pods pending in the scheduling queue. The
+// namespace and profile query parameters filter the pods by namespace and
+// profile, and output=json selects JSON over a table in plain text.
+type PendingPodsHandler struct {
+	podQueue queue.SchedulingQueue
+	// profileName returns the name of the profile of a pod.
+	profileName func(*v1.Pod) string
+}
+
+// NewPendingPodsHandler returns a PendingPodsHandler for the given queue. The
+// profile of the pods is given by profileName, or is their scheduler name if
+// it is nil.
+func NewPendingPodsHandler(podQueue queue.SchedulingQueue, profileName func(*v1.Pod) string) *PendingPodsHandler {
+	if profileName == nil {
+		profileName = func(pod *v1.Pod) string { return pod.Spec.SchedulerName }
+	}
+	return &PendingPodsHandler{podQueue: podQueue, profileName: profileName}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *PendingPodsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	output := query.Get("output")
+	if output != "" && output != "json" && output != "text" {
+		http.Error(w, fmt.Sprintf("unsupported output %q, must be json or text", output), http.StatusBadRequest)
+		return
+	}
+	pods := h.pendingPods(query.Get("namespace"), query.Get("profile"))
+	if output == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(pods); err != nil {
+			klog.ErrorS(err, "Failed to write pending pods")
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tPROFILE\tQUEUE\tATTEMPTS\tINITIAL ATTEMPT\tBACKOFF EXPIRY\tUNSCHEDULABLE PLUGINS\tLAST FAILURE\tGATE")
+	for _, p := range pods {
+		backoffExpiry := "<none>"
+		if p.BackoffExpiry != nil {
+			backoffExpiry = p.BackoffExpiry.Format(time.RFC3339)
+		}
+		initialAttempt := "<none>"
+		if !p.InitialAttemptTimestamp.IsZero() {
+			initialAttempt = p.InitialAttemptTimestamp.Format(time.RFC3339)
+		}
+		gate := ""
+		if p.GatingPlugin != "" {
+			gate = fmt.Sprintf("%s: %s", p.GatingPlugin, p.GatingMessage)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n", p.Namespace, p.Name, p.Profile, p.Queue, p.Attempts,
+			initialAttempt, backoffExpiry, strings.Join(p.UnschedulablePlugins, ","), p.LastFailureMessage, gate)
+	}
+	if err := tw.Flush(); err != nil {
+		klog.ErrorS(err, "Failed to write pending pods")
+	}
+}
+
+// pendingPods returns the pods pending in the scheduling queue in the given
+// namespace and profile, or all of them if empty, sorted by sub-queue and
+// then by namespace and name.
+func (h *PendingPodsHandler) pendingPods(namespace, profile string) []PendingPod {
+	pods := []PendingPod{}
+	for _, pInfo := range h.podQueue.PendingPodInfos() {
+		pod := pInfo.Pod
+		if namespace != "" && pod.Namespace != namespace {
+			continue
+		}
+		podProfile := h.profileName(pod)
+		if profile != "" && podProfile != profile {
+			continue
+		}
+		p := PendingPod{
+			Namespace:               pod.Namespace,
+			Name:                    pod.Name,
+			UID:                     pod.UID,
+			Profile:                 podProfile,
+			Queue:                   pInfo.Queue,
+			Attempts:                pInfo.Attempts,
+			InitialAttemptTimestamp: pInfo.InitialAttemptTimestamp,
+			UnschedulablePlugins:    pInfo.UnschedulablePlugins.List(),
+			LastFailureMessage:      pInfo.LastFailureMessage,
+			GatingPlugin:            pInfo.GatingPlugin,
+			GatingMessage:           pInfo.GatingMessage,
+		}
+		if !pInfo.BackoffExpiry.IsZero() {
+			backoffExpiry := pInfo.BackoffExpiry
+			p.BackoffExpiry = &backoffExpiry
+		}
+		pods = append(pods, p)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Queue != pods[j].Queue {
+			return queueOrder[pods[i].Queue] < queueOrder[pods[j].Queue]
+		}
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	return pods
+}
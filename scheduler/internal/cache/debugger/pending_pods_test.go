@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/schedulinggates"
+	"k8s.io/kubernetes/pkg/scheduler/internal/queue"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestPendingPodsHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	less := func(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+		return pInfo1.Timestamp.Before(pInfo2.Timestamp)
+	}
+	q := queue.NewTestQueue(ctx, less, queue.WithClock(testingclock.NewFakeClock(now)),
+		queue.WithPreEnqueuePluginMap(map[string][]framework.PreEnqueuePlugin{"default-scheduler": {&schedulinggates.SchedulingGates{}}}))
+	fail := func(pod *v1.Pod, plugins ...string) {
+		t.Helper()
+		if err := q.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+		pInfo, err := q.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pInfo.UnschedulablePlugins = sets.NewString(plugins...)
+		pInfo.LastFailureMessage = "0/1 nodes are available"
+		if err := q.AddUnschedulableIfNotPresent(pInfo, q.SchedulingCycle()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first failed pod is moved to backoffQ, the second one stays in unschedulableQ.
+	fail(st.MakePod().Namespace("b").Name("backoff").UID("backoff").SchedulerName("default-scheduler").Obj(), "NodeResourcesFit")
+	q.MoveAllToActiveOrBackoffQueue(queue.UnschedulableTimeout, nil, nil, nil)
+	fail(st.MakePod().Namespace("a").Name("unschedulable").UID("unschedulable").SchedulerName("default-scheduler").Obj(), "NodeAffinity", "TaintToleration")
+	q.Add(st.MakePod().Namespace("b").Name("active").UID("active").SchedulerName("default-scheduler").Obj())
+	q.Add(st.MakePod().Namespace("a").Name("other-profile").UID("other-profile").SchedulerName("other-scheduler").Obj())
+	q.Add(st.MakePod().Namespace("b").Name("gated").UID("gated").SchedulerName("default-scheduler").
+		Annotation(schedulinggates.SchedulingGatesAnnotation, "telemetry").Obj())
+
+	backoffExpiry := now.Add(queue.DefaultPodInitialBackoffDuration)
+	active := PendingPod{
+		Namespace: "b", Name: "active", UID: "active", Profile: "default-scheduler", Queue: queue.ActiveQName,
+		InitialAttemptTimestamp: now,
+	}
+	otherProfile := PendingPod{
+		Namespace: "a", Name: "other-profile", UID: "other-profile", Profile: "other-scheduler", Queue: queue.ActiveQName,
+		InitialAttemptTimestamp: now,
+	}
+	backoff := PendingPod{
+		Namespace: "b", Name: "backoff", UID: "backoff", Profile: "default-scheduler", Queue: queue.BackoffQName,
+		Attempts: 1, InitialAttemptTimestamp: now, BackoffExpiry: &backoffExpiry,
+		UnschedulablePlugins: []string{"NodeResourcesFit"}, LastFailureMessage: "0/1 nodes are available",
+	}
+	unschedulable := PendingPod{
+		Namespace: "a", Name: "unschedulable", UID: "unschedulable", Profile: "default-scheduler", Queue: queue.UnschedulableQName,
+		Attempts: 1, InitialAttemptTimestamp: now, BackoffExpiry: &backoffExpiry,
+		UnschedulablePlugins: []string{"NodeAffinity", "TaintToleration"}, LastFailureMessage: "0/1 nodes are available",
+	}
+	gated := PendingPod{
+		Namespace: "b", Name: "gated", UID: "gated", Profile: "default-scheduler", Queue: queue.GatedQName,
+		InitialAttemptTimestamp: now, UnschedulablePlugins: []string{schedulinggates.Name},
+		GatingPlugin: schedulinggates.Name, GatingMessage: "waiting for scheduling gates: telemetry",
+	}
+
+	routed := func(p PendingPod) PendingPod {
+		p.Profile = "routed"
+		return p
+	}
+
+	tests := []struct {
+		name        string
+		profileName func(*v1.Pod) string
+		query       string
+		want        []PendingPod
+	}{
+		{
+			name:  "all pods",
+			query: "output=json",
+			want:  []PendingPod{otherProfile, active, backoff, unschedulable, gated},
+		},
+		{
+			name:  "by namespace",
+			query: "output=json&namespace=b",
+			want:  []PendingPod{active, backoff, gated},
+		},
+		{
+			name:  "by profile",
+			query: "output=json&profile=default-scheduler",
+			want:  []PendingPod{active, backoff, unschedulable, gated},
+		},
+		{
+			name: "by routed profile",
+			profileName: func(pod *v1.Pod) string {
+				if pod.Namespace == "a" {
+					return "routed"
+				}
+				return pod.Spec.SchedulerName
+			},
+			query: "output=json&profile=routed",
+			want:  []PendingPod{routed(otherProfile), routed(unschedulable)},
+		},
+		{
+			name:  "no match",
+			query: "output=json&namespace=c",
+			want:  []PendingPod{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewPendingPodsHandler(q, tt.profileName)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pending-pods?"+tt.query, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Got status %v, want %v", w.Code, http.StatusOK)
+			}
+			var got []PendingPod
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unexpected pending pods (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("text", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pending-pods?namespace=a&profile=default-scheduler", nil))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Got %d lines, want a header and one pod:\n%s", len(lines), w.Body.String())
+		}
+		for _, want := range []string{"unschedulable", "NodeAffinity,TaintToleration", "0/1 nodes are available"} {
+			if !strings.Contains(lines[1], want) {
+				t.Errorf("Expected %q in %q", want, lines[1])
+			}
+		}
+	})
+
+	t.Run("unsupported output", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pending-pods?output=yaml", nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Got status %v, want %v", w.Code, http.StatusBadRequest)
+		}
+	})
+}
@@ -37,6 +37,9 @@ type Snapshot struct {
 	// required anti-affinity terms.
 	havePodsWithRequiredAntiAffinityNodeInfoList []*framework.NodeInfo
 	generation                                   int64
+	// assumedNodeInfos holds a copy of every NodeInfo, keyed by node name, as it
+	// was before pods were assumed on it with AssumePod.
+	assumedNodeInfos map[string]*framework.NodeInfo
 }
 
 var _ framework.SharedLister = &Snapshot{}
@@ -73,6 +76,60 @@ func NewSnapshot(pods []*v1.Pod, nodes []*v1.Node) *Snapshot {
 	return s
 }
 
+// AssumePod adds the pod to the NodeInfo of the node it is assigned to, so that
+// the pod is taken into account by further scheduling decisions made against
+// this snapshot. The changes are undone by Restore.
+func (s *Snapshot) AssumePod(pod *v1.Pod) error {
+	nodeName := pod.Spec.NodeName
+	n, ok := s.nodeInfoMap[nodeName]
+	if !ok {
+		return fmt.Errorf("nodeinfo not found for node name %q", nodeName)
+	}
+	if s.assumedNodeInfos == nil {
+		s.assumedNodeInfos = make(map[string]*framework.NodeInfo)
+	}
+	if _, ok := s.assumedNodeInfos[nodeName]; !ok {
+		s.assumedNodeInfos[nodeName] = n.Clone()
+	}
+	hadPodsWithAffinity := len(n.PodsWithAffinity) > 0
+	hadPodsWithRequiredAntiAffinity := len(n.PodsWithRequiredAntiAffinity) > 0
+	n.AddPod(pod)
+	if hadPodsWithAffinity != (len(n.PodsWithAffinity) > 0) || hadPodsWithRequiredAntiAffinity != (len(n.PodsWithRequiredAntiAffinity) > 0) {
+		s.updateAffinityLists()
+	}
+	return nil
+}
+
+// Restore undoes all the pods assumed with AssumePod. It must be called before
+// the snapshot is updated from the cache, which only refreshes the nodes that
+// changed in the cache.
+func (s *Snapshot) Restore() {
+	if len(s.assumedNodeInfos) == 0 {
+		return
+	}
+	for nodeName, n := range s.assumedNodeInfos {
+		// Preserve the original pointer, which is also held by the lists.
+		*s.nodeInfoMap[nodeName] = *n
+	}
+	s.assumedNodeInfos = nil
+	s.updateAffinityLists()
+}
+
+// updateAffinityLists re-creates the lists of nodes with pods declaring
+// affinity and required anti-affinity terms, keeping the order of nodeInfoList.
+func (s *Snapshot) updateAffinityLists() {
+	s.havePodsWithAffinityNodeInfoList = make([]*framework.NodeInfo, 0, len(s.nodeInfoList))
+	s.havePodsWithRequiredAntiAffinityNodeInfoList = make([]*framework.NodeInfo, 0, len(s.nodeInfoList))
+	for _, n := range s.nodeInfoList {
+		if len(n.PodsWithAffinity) > 0 {
+			s.havePodsWithAffinityNodeInfoList = append(s.havePodsWithAffinityNodeInfoList, n)
+		}
+		if len(n.PodsWithRequiredAntiAffinity) > 0 {
+			s.havePodsWithRequiredAntiAffinityNodeInfoList = append(s.havePodsWithRequiredAntiAffinityNodeInfoList, n)
+		}
+	}
+}
+
 // createNodeInfoMap obtains a list of pods and pivots that list into a map
 // where the keys are node names and the values are the aggregated information
 // for that node.
@@ -180,3 +180,57 @@ func TestCreateImageExistenceMap(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotAssumePodAndRestore(t *testing.T) {
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+	}
+	existing := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "existing", UID: "existing"}, Spec: v1.PodSpec{NodeName: "node1"}}
+	withAffinity := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "with-affinity", UID: "with-affinity"},
+		Spec: v1.PodSpec{
+			NodeName: "node2",
+			Affinity: &v1.Affinity{
+				PodAffinity: &v1.PodAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
+						{TopologyKey: "zone", LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}},
+					},
+				},
+			},
+		},
+	}
+	plain := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "plain", UID: "plain"}, Spec: v1.PodSpec{NodeName: "node1"}}
+	s := NewSnapshot([]*v1.Pod{existing}, nodes)
+
+	for _, pod := range []*v1.Pod{withAffinity, plain} {
+		if err := s.AssumePod(pod); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := s.AssumePod(&v1.Pod{Spec: v1.PodSpec{NodeName: "node3"}}); err == nil {
+		t.Error("Expected an error assuming a pod on an unknown node")
+	}
+	node1, _ := s.Get("node1")
+	if len(node1.Pods) != 2 {
+		t.Errorf("Expected 2 pods on node1 after AssumePod, got %d", len(node1.Pods))
+	}
+	havePodsWithAffinity, _ := s.HavePodsWithAffinityList()
+	if len(havePodsWithAffinity) != 1 || havePodsWithAffinity[0].Node().Name != "node2" {
+		t.Errorf("Expected node2 to have pods with affinity after AssumePod, got %v", havePodsWithAffinity)
+	}
+
+	s.Restore()
+	node1, _ = s.Get("node1")
+	if len(node1.Pods) != 1 || node1.Pods[0].Pod != existing {
+		t.Errorf("Expected only the existing pod on node1 after Restore, got %v", node1.Pods)
+	}
+	node2, _ := s.Get("node2")
+	if len(node2.Pods) != 0 {
+		t.Errorf("Expected no pods on node2 after Restore, got %v", node2.Pods)
+	}
+	havePodsWithAffinity, _ = s.HavePodsWithAffinityList()
+	if len(havePodsWithAffinity) != 0 {
+		t.Errorf("Expected no nodes with pods with affinity after Restore, got %v", havePodsWithAffinity)
+	}
+}
@@ -239,6 +239,13 @@ func (h *Heap) Len() int {
 	return len(h.data.queue)
 }
 
+// SetLessFunc replaces the function comparing the items, and reorders the heap
+// accordingly.
+func (h *Heap) SetLessFunc(lessFn lessFunc) {
+	h.data.lessFunc = lessFn
+	heap.Init(h.data)
+}
+
 // New returns a Heap which can be used to queue up items to process.
 func New(keyFn KeyFunc, lessFn lessFunc) *Heap {
 	return NewWithRecorder(keyFn, lessFn, nil)
@@ -269,3 +269,24 @@ func TestHeap_List(t *testing.T) {
 		}
 	}
 }
+
+// TestHeap_SetLessFunc tests Heap.SetLessFunc function.
+func TestHeap_SetLessFunc(t *testing.T) {
+	h := New(testHeapObjectKeyFunc, compareInts)
+	for i := 1; i <= 10; i++ {
+		h.Add(mkHeapObj(string([]rune{'a', rune(i)}), i))
+	}
+	h.SetLessFunc(func(val1, val2 interface{}) bool {
+		return !compareInts(val1, val2)
+	})
+	// Make sure that the numbers are popped in descending order.
+	for i := 10; i > 0; i-- {
+		obj, err := h.Pop()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if num := obj.(testHeapObject).val.(int); num != i {
+			t.Errorf("got %d, want %d", num, i)
+		}
+	}
+}
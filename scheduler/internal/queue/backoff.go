@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// BackoffPolicy computes how long a pod is backed off after a failed
+// scheduling attempt.
+type BackoffPolicy interface {
+	// Backoff returns the backoff of the pod, counted from its Timestamp. As
+	// podBackoffQ is ordered by backoff expiry, it must always return the same
+	// duration for the same attempt of a pod.
+	Backoff(podInfo *framework.QueuedPodInfo) time.Duration
+}
+
+// NewBackoffPolicy returns the BackoffPolicy described by the configuration,
+// growing the backoff from initial up to max. A nil configuration results in
+// an exponential backoff without jitter.
+func NewBackoffPolicy(cfg *config.BackoffPolicy, initial, max time.Duration) BackoffPolicy {
+	if cfg == nil {
+		return &exponentialBackoff{initial: initial, max: max}
+	}
+	newPolicy := func(initial, max time.Duration) BackoffPolicy {
+		var policy BackoffPolicy = &exponentialBackoff{initial: initial, max: max}
+		if cfg.Type == config.LinearBackoffPolicy {
+			policy = &linearBackoff{initial: initial, max: max}
+		}
+		if cfg.Jitter > 0 {
+			policy = &jitteredBackoff{BackoffPolicy: policy, jitter: cfg.Jitter, max: max}
+		}
+		return policy
+	}
+	policy := newPolicy(initial, max)
+	if len(cfg.PluginOverrides) != 0 {
+		overrides := make(map[string]BackoffPolicy, len(cfg.PluginOverrides))
+		for _, o := range cfg.PluginOverrides {
+			overrides[o.PluginName] = newPolicy(time.Duration(o.InitialBackoffSeconds)*time.Second, time.Duration(o.MaxBackoffSeconds)*time.Second)
+		}
+		policy = &pluginBackoff{BackoffPolicy: policy, overrides: overrides}
+	}
+	return policy
+}
+
+// exponentialBackoff doubles the backoff on every attempt.
+type exponentialBackoff struct {
+	initial, max time.Duration
+}
+
+func (b *exponentialBackoff) Backoff(podInfo *framework.QueuedPodInfo) time.Duration {
+	duration := b.initial
+	for i := 1; i < podInfo.Attempts; i++ {
+		// Use subtraction instead of addition or multiplication to avoid overflow.
+		if duration > b.max-duration {
+			return b.max
+		}
+		duration += duration
+	}
+	return duration
+}
+
+// linearBackoff grows the backoff by the initial backoff on every attempt.
+type linearBackoff struct {
+	initial, max time.Duration
+}
+
+func (b *linearBackoff) Backoff(podInfo *framework.QueuedPodInfo) time.Duration {
+	attempts := time.Duration(podInfo.Attempts)
+	if attempts < 1 {
+		attempts = 1
+	}
+	// Use division instead of multiplication to avoid overflow.
+	if b.initial > 0 && attempts > b.max/b.initial {
+		return b.max
+	}
+	return b.initial * attempts
+}
+
+// pluginBackoff overrides the backoff of the pods that were only rejected by
+// plugins with an override, using the longest of their backoffs.
+type pluginBackoff struct {
+	BackoffPolicy
+	overrides map[string]BackoffPolicy
+}
+
+func (b *pluginBackoff) Backoff(podInfo *framework.QueuedPodInfo) time.Duration {
+	if len(podInfo.UnschedulablePlugins) == 0 {
+		return b.BackoffPolicy.Backoff(podInfo)
+	}
+	var duration time.Duration
+	for plugin := range podInfo.UnschedulablePlugins {
+		override, ok := b.overrides[plugin]
+		if !ok {
+			return b.BackoffPolicy.Backoff(podInfo)
+		}
+		if d := override.Backoff(podInfo); d > duration {
+			duration = d
+		}
+	}
+	return duration
+}
+
+// jitteredBackoff randomly adds or removes up to a fraction of the backoff,
+// without exceeding the max backoff. The jitter is derived from the pod UID
+// and attempt, so that it's the same every time the backoff of an attempt is
+// computed.
+type jitteredBackoff struct {
+	BackoffPolicy
+	jitter float64
+	max    time.Duration
+}
+
+func (b *jitteredBackoff) Backoff(podInfo *framework.QueuedPodInfo) time.Duration {
+	duration := b.BackoffPolicy.Backoff(podInfo)
+	h := fnv.New64a()
+	h.Write([]byte(podInfo.Pod.UID))
+	h.Write([]byte(strconv.Itoa(podInfo.Attempts)))
+	// A uniform value in [-1, 1).
+	r := float64(h.Sum64()>>11)/(1<<53)*2 - 1
+	duration += time.Duration(r * b.jitter * float64(duration))
+	if duration > b.max {
+		return b.max
+	}
+	return duration
+}
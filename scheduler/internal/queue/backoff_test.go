@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestBackoffPolicy(t *testing.T) {
+	podInfo := func(attempts int, plugins ...string) *framework.QueuedPodInfo {
+		return &framework.QueuedPodInfo{
+			PodInfo:              framework.NewPodInfo(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", UID: "p"}}),
+			Attempts:             attempts,
+			UnschedulablePlugins: sets.NewString(plugins...),
+		}
+	}
+	overrides := []config.PluginBackoffOverride{
+		{PluginName: "dqn-plugin", InitialBackoffSeconds: 1, MaxBackoffSeconds: 2},
+		{PluginName: "NodeAffinity", InitialBackoffSeconds: 3, MaxBackoffSeconds: 3},
+	}
+	tests := []struct {
+		name    string
+		cfg     *config.BackoffPolicy
+		initial time.Duration
+		max     time.Duration
+		podInfo *framework.QueuedPodInfo
+		want    time.Duration
+	}{
+		{
+			name:    "default",
+			initial: time.Second,
+			max:     10 * time.Second,
+			podInfo: podInfo(3),
+			want:    4 * time.Second,
+		},
+		{
+			name:    "exponential capped",
+			cfg:     &config.BackoffPolicy{Type: config.ExponentialBackoffPolicy},
+			initial: time.Second,
+			max:     10 * time.Second,
+			podInfo: podInfo(5),
+			want:    10 * time.Second,
+		},
+		{
+			name:    "linear",
+			cfg:     &config.BackoffPolicy{Type: config.LinearBackoffPolicy},
+			initial: time.Second,
+			max:     10 * time.Second,
+			podInfo: podInfo(3),
+			want:    3 * time.Second,
+		},
+		{
+			name:    "linear capped",
+			cfg:     &config.BackoffPolicy{Type: config.LinearBackoffPolicy},
+			initial: time.Second,
+			max:     10 * time.Second,
+			podInfo: podInfo(11),
+			want:    10 * time.Second,
+		},
+		{
+			name:    "linear overflow",
+			cfg:     &config.BackoffPolicy{Type: config.LinearBackoffPolicy},
+			initial: time.Hour,
+			max:     math.MaxInt64 * time.Nanosecond,
+			podInfo: podInfo(math.MaxInt32),
+			want:    math.MaxInt64 * time.Nanosecond,
+		},
+		{
+			name:    "only overridden plugin failed",
+			cfg:     &config.BackoffPolicy{PluginOverrides: overrides},
+			initial: time.Second,
+			max:     10 * time.Second,
+			podInfo: podInfo(4, "dqn-plugin"),
+			want:    2 * time.Second,
+		},
+		{
+			name:    "several overridden plugins failed",
+			cfg:     &config.BackoffPolicy{PluginOverrides: overrides},
+			initial: time.Second,
+			max:     10 * time.Second,
+			podInfo: podInfo(2, "dqn-plugin", "NodeAffinity"),
+			want:    3 * time.Second,
+		},
+		{
+			name:    "other plugins failed too",
+			cfg:     &config.BackoffPolicy{PluginOverrides: overrides},
+			initial: time.Second,
+			max:     10 * time.Second,
+			podInfo: podInfo(4, "dqn-plugin", "NodeResourcesFit"),
+			want:    8 * time.Second,
+		},
+		{
+			name:    "no plugin failed",
+			cfg:     &config.BackoffPolicy{PluginOverrides: overrides},
+			initial: time.Second,
+			max:     10 * time.Second,
+			podInfo: podInfo(1),
+			want:    time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewBackoffPolicy(tt.cfg, tt.initial, tt.max)
+			if got := policy.Backoff(tt.podInfo); got != tt.want {
+				t.Errorf("Backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJitteredBackoff(t *testing.T) {
+	policy := NewBackoffPolicy(&config.BackoffPolicy{Jitter: 0.5}, 10*time.Second, 12*time.Second)
+	seen := sets.NewInt64()
+	for i := 0; i < 20; i++ {
+		pInfo := &framework.QueuedPodInfo{
+			PodInfo:  framework.NewPodInfo(&v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "p"}}),
+			Attempts: i,
+		}
+		got := policy.Backoff(pInfo)
+		// The backoff is 10s for attempts up to 1, and 12s after that.
+		if got < 5*time.Second || got > 12*time.Second {
+			t.Errorf("Attempt %d: backoff %v out of the jitter range or above the max backoff", i, got)
+		}
+		if again := policy.Backoff(pInfo); again != got {
+			t.Errorf("Attempt %d: got backoff %v, then %v", i, got, again)
+		}
+		seen.Insert(int64(got))
+	}
+	if seen.Len() < 2 {
+		t.Errorf("Expected the backoff to vary between attempts, got %v", seen.List())
+	}
+}
+
+func TestPriorityQueue_BackoffPolicy(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	policy := NewBackoffPolicy(&config.BackoffPolicy{
+		PluginOverrides: []config.PluginBackoffOverride{{PluginName: "dqn-plugin", InitialBackoffSeconds: 1, MaxBackoffSeconds: 1}},
+	}, 5*time.Second, 5*time.Second)
+	q := NewTestQueue(context.Background(), newDefaultQueueSort(), WithClock(c), WithBackoffPolicy(policy))
+	var moved []string
+	for _, plugin := range []string{"dqn-plugin", "NodeResourcesFit"} {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: plugin, Namespace: "ns", UID: types.UID(plugin)}}
+		q.Add(pod)
+		pInfo, err := q.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pInfo.UnschedulablePlugins = sets.NewString(plugin)
+		q.AddUnschedulableIfNotPresent(pInfo, q.SchedulingCycle())
+	}
+	q.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil, nil, nil)
+	c.Step(2 * time.Second)
+	q.flushBackoffQCompleted()
+	for q.activeQ.Len() > 0 {
+		pInfo, err := q.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		moved = append(moved, pInfo.Pod.Name)
+	}
+	if diff := cmp.Diff([]string{"dqn-plugin"}, moved); diff != "" {
+		t.Errorf("Unexpected pods completing backoff (-want, +got):\n%s", diff)
+	}
+}
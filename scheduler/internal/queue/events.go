@@ -23,6 +23,8 @@ import (
 const (
 	// PodAdd is the event when a new pod is added to API server.
 	PodAdd = "PodAdd"
+	// PodUpdate is the event when a pending pod is updated.
+	PodUpdate = "PodUpdate"
 	// ScheduleAttemptFailure is the event when a schedule attempt fails.
 	ScheduleAttemptFailure = "ScheduleAttemptFailure"
 	// BackoffComplete is the event when a pod finishes backoff.
@@ -84,6 +86,12 @@ var (
 	ServiceUpdate = framework.ClusterEvent{Resource: framework.Service, ActionType: framework.Update, Label: "ServiceUpdate"}
 	// ServiceDelete is the event when a service is deleted in the cluster.
 	ServiceDelete = framework.ClusterEvent{Resource: framework.Service, ActionType: framework.Delete, Label: "ServiceDelete"}
+	// NodeUtilizationDrop is the event when the utilization of a node drops below
+	// the threshold, as reported by the node telemetry.
+	NodeUtilizationDrop = framework.ClusterEvent{Resource: framework.NodeUtilization, ActionType: framework.Update, Label: "NodeUtilizationDrop"}
+	// NetworkLinkUpdate is the event when the node telemetry reports a new link
+	// between nodes, or a lower latency of a link.
+	NetworkLinkUpdate = framework.ClusterEvent{Resource: framework.NetworkLink, ActionType: framework.Update, Label: "NetworkLinkUpdate"}
 	// WildCardEvent semantically matches all resources on all actions.
 	WildCardEvent = framework.ClusterEvent{Resource: framework.WildCard, ActionType: framework.All, Label: "WildCardEvent"}
 	// UnschedulableTimeout is the event when a pod stays in unschedulable for longer than timeout.
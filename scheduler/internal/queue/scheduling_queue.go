@@ -19,11 +19,13 @@ limitations under the License.
 // priority queue which has two sub queues. One sub-queue holds pods that are
 // being considered for scheduling. This is called activeQ. Another queue holds
 // pods that are already tried and are determined to be unschedulable. The latter
-// is called unschedulableQ.
+// is called unschedulableQ. Pods rejected by PreEnqueue plugins are parked
+// aside in gatedPods until they are let through.
 
 package queue
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sync"
@@ -64,6 +66,13 @@ const (
 	// for unschedulable pods. To change the default podMaxBackoffDurationSeconds used by the
 	// scheduler, update the ComponentConfig value in defaults.go
 	DefaultPodMaxBackoffDuration time.Duration = 10 * time.Second
+
+	// DefaultPopWindow is the default number of pods at the head of activeQ
+	// that a PopPolicy picks from.
+	DefaultPopWindow = 10
+	// DefaultMaxPopSkip is the default maximum time a pod can be skipped by a
+	// PopPolicy before it's dispatched regardless of the policy.
+	DefaultMaxPopSkip = 30 * time.Second
 )
 
 // PreEnqueueCheck is a function type. It's used to build functions that
@@ -92,12 +101,25 @@ type SchedulingQueue interface {
 	// Pop removes the head of the queue and returns it. It blocks if the
 	// queue is empty and waits until a new item is added to the queue.
 	Pop() (*framework.QueuedPodInfo, error)
+	// PopIf removes the pod Pop would return next and returns it if it
+	// satisfies match. Unlike Pop, it never blocks: it returns nil if the queue
+	// is empty or if that pod doesn't satisfy match.
+	PopIf(match func(*framework.QueuedPodInfo) bool) *framework.QueuedPodInfo
 	Update(oldPod, newPod *v1.Pod) error
 	Delete(pod *v1.Pod) error
-	MoveAllToActiveOrBackoffQueue(event framework.ClusterEvent, preCheck PreEnqueueCheck)
+	// MoveAllToActiveOrBackoffQueue moves the unschedulable pods the event may
+	// make schedulable. oldObj and newObj are the objects of the event, if known.
+	MoveAllToActiveOrBackoffQueue(event framework.ClusterEvent, oldObj, newObj interface{}, preCheck PreEnqueueCheck)
 	AssignedPodAdded(pod *v1.Pod)
 	AssignedPodUpdated(pod *v1.Pod)
 	PendingPods() []*v1.Pod
+	// PendingPodInfos returns a snapshot of the pods pending in the queue,
+	// along with the queue each of them is in.
+	PendingPodInfos() []*PendingPodInfo
+	// UpdateProfiles replaces the function sorting activeQ, if not nil, and
+	// the PreEnqueue plugins of the profiles in preEnqueuePluginMap, with
+	// those of reloaded frameworks.
+	UpdateProfiles(lessFn framework.LessFunc, preEnqueuePluginMap map[string][]framework.PreEnqueuePlugin)
 	// Close closes the SchedulingQueue so that the goroutine which is
 	// waiting to pop items can exit gracefully.
 	Close()
@@ -136,6 +158,8 @@ type PriorityQueue struct {
 	podInitialBackoffDuration time.Duration
 	// pod maximum backoff duration.
 	podMaxBackoffDuration time.Duration
+	// backoffPolicy computes the backoff of unschedulable pods.
+	backoffPolicy BackoffPolicy
 
 	lock sync.RWMutex
 	cond sync.Cond
@@ -148,6 +172,9 @@ type PriorityQueue struct {
 	podBackoffQ *heap.Heap
 	// unschedulableQ holds pods that have been tried and determined unschedulable.
 	unschedulableQ *UnschedulablePodsMap
+	// gatedPods holds pods that haven't been tried yet because a PreEnqueue
+	// plugin rejected them.
+	gatedPods *UnschedulablePodsMap
 	// schedulingCycle represents sequence number of scheduling cycle and is incremented
 	// when a pod is popped.
 	schedulingCycle int64
@@ -158,20 +185,46 @@ type PriorityQueue struct {
 	moveRequestCycle int64
 
 	clusterEventMap map[framework.ClusterEvent]sets.String
+	// queueingHintMap holds the hint functions of the plugins, keyed by event
+	// and plugin name.
+	queueingHintMap map[framework.ClusterEvent]map[string]framework.QueueingHintFn
+	// preEnqueuePluginMap holds the PreEnqueue plugins, keyed by profile name.
+	preEnqueuePluginMap map[string][]framework.PreEnqueuePlugin
+	// profileName returns the name of the profile of a pod.
+	profileName func(*v1.Pod) string
 
 	// closed indicates that the queue is closed.
 	// It is mainly used to let Pop() exit its control loop while waiting for an item.
 	closed bool
 
 	nsLister listersv1.NamespaceLister
+
+	// popPolicy, if set, picks the pod to pop among the popWindow pods at the
+	// head of activeQ, given the state of the cluster returned by nodeInfos.
+	popPolicy framework.PopPolicy
+	nodeInfos func() (framework.NodeInfoLister, error)
+	popWindow int
+	// maxPopSkip is the maximum time a pod can be skipped by popPolicy before
+	// it's popped regardless of the policy.
+	maxPopSkip time.Duration
+	// skippedSince holds the time since when the pods were skipped by popPolicy.
+	skippedSince map[types.UID]time.Time
 }
 
 type priorityQueueOptions struct {
 	clock                     util.Clock
 	podInitialBackoffDuration time.Duration
 	podMaxBackoffDuration     time.Duration
+	backoffPolicy             BackoffPolicy
 	podNominator              framework.PodNominator
 	clusterEventMap           map[framework.ClusterEvent]sets.String
+	queueingHintMap           map[framework.ClusterEvent]map[string]framework.QueueingHintFn
+	preEnqueuePluginMap       map[string][]framework.PreEnqueuePlugin
+	profileName               func(*v1.Pod) string
+	popPolicy                 framework.PopPolicy
+	nodeInfos                 func() (framework.NodeInfoLister, error)
+	popWindow                 int
+	maxPopSkip                time.Duration
 }
 
 // Option configures a PriorityQueue
@@ -198,6 +251,15 @@ func WithPodMaxBackoffDuration(duration time.Duration) Option {
 	}
 }
 
+// WithBackoffPolicy sets the policy computing the backoff of unschedulable
+// pods for PriorityQueue. By default, the backoff doubles on every attempt,
+// from the pod initial backoff duration up to the pod max backoff duration.
+func WithBackoffPolicy(policy BackoffPolicy) Option {
+	return func(o *priorityQueueOptions) {
+		o.backoffPolicy = policy
+	}
+}
+
 // WithPodNominator sets pod nominator for PriorityQueue.
 func WithPodNominator(pn framework.PodNominator) Option {
 	return func(o *priorityQueueOptions) {
@@ -212,10 +274,63 @@ func WithClusterEventMap(m map[framework.ClusterEvent]sets.String) Option {
 	}
 }
 
+// WithQueueingHintMap sets queueingHintMap for PriorityQueue.
+func WithQueueingHintMap(m map[framework.ClusterEvent]map[string]framework.QueueingHintFn) Option {
+	return func(o *priorityQueueOptions) {
+		o.queueingHintMap = m
+	}
+}
+
+// WithPreEnqueuePluginMap sets the PreEnqueue plugins of each profile, keyed
+// by profile name, for PriorityQueue.
+func WithPreEnqueuePluginMap(m map[string][]framework.PreEnqueuePlugin) Option {
+	return func(o *priorityQueueOptions) {
+		o.preEnqueuePluginMap = m
+	}
+}
+
+// WithProfileNameFunc sets the function returning the name of the profile of a
+// pod, for PriorityQueue. By default, it is the scheduler name of the pod.
+func WithProfileNameFunc(f func(*v1.Pod) string) Option {
+	return func(o *priorityQueueOptions) {
+		o.profileName = f
+	}
+}
+
+// WithPopPolicy sets the policy picking the pod to pop among the pods at the
+// head of activeQ, given the state of the cluster returned by nodeInfos, which
+// is called without holding the lock of the queue. If nodeInfos is nil, the
+// policy is given no nodes. By default, pods are popped in the order of the
+// QueueSort plugin.
+func WithPopPolicy(policy framework.PopPolicy, nodeInfos func() (framework.NodeInfoLister, error)) Option {
+	return func(o *priorityQueueOptions) {
+		o.popPolicy = policy
+		o.nodeInfos = nodeInfos
+	}
+}
+
+// WithPopWindow sets the number of pods at the head of activeQ that the
+// PopPolicy picks from.
+func WithPopWindow(n int) Option {
+	return func(o *priorityQueueOptions) {
+		o.popWindow = n
+	}
+}
+
+// WithMaxPopSkip sets the maximum time a pod can be skipped by the PopPolicy
+// before it's popped regardless of the policy.
+func WithMaxPopSkip(d time.Duration) Option {
+	return func(o *priorityQueueOptions) {
+		o.maxPopSkip = d
+	}
+}
+
 var defaultPriorityQueueOptions = priorityQueueOptions{
 	clock:                     util.RealClock{},
 	podInitialBackoffDuration: DefaultPodInitialBackoffDuration,
 	podMaxBackoffDuration:     DefaultPodMaxBackoffDuration,
+	popWindow:                 DefaultPopWindow,
+	maxPopSkip:                DefaultMaxPopSkip,
 }
 
 // Making sure that PriorityQueue implements SchedulingQueue.
@@ -242,15 +357,15 @@ func NewPriorityQueue(
 		opt(&options)
 	}
 
-	comp := func(podInfo1, podInfo2 interface{}) bool {
-		pInfo1 := podInfo1.(*framework.QueuedPodInfo)
-		pInfo2 := podInfo2.(*framework.QueuedPodInfo)
-		return lessFn(pInfo1, pInfo2)
-	}
-
 	if options.podNominator == nil {
 		options.podNominator = NewPodNominator(informerFactory.Core().V1().Pods().Lister())
 	}
+	if options.backoffPolicy == nil {
+		options.backoffPolicy = NewBackoffPolicy(nil, options.podInitialBackoffDuration, options.podMaxBackoffDuration)
+	}
+	if options.profileName == nil {
+		options.profileName = func(pod *v1.Pod) string { return pod.Spec.SchedulerName }
+	}
 
 	pq := &PriorityQueue{
 		PodNominator:              options.podNominator,
@@ -258,10 +373,20 @@ func NewPriorityQueue(
 		stop:                      make(chan struct{}),
 		podInitialBackoffDuration: options.podInitialBackoffDuration,
 		podMaxBackoffDuration:     options.podMaxBackoffDuration,
-		activeQ:                   heap.NewWithRecorder(podInfoKeyFunc, comp, metrics.NewActivePodsRecorder()),
+		backoffPolicy:             options.backoffPolicy,
+		activeQ:                   heap.NewWithRecorder(podInfoKeyFunc, queuedPodInfoLess(lessFn), metrics.NewActivePodsRecorder()),
 		unschedulableQ:            newUnschedulablePodsMap(metrics.NewUnschedulablePodsRecorder()),
+		gatedPods:                 newUnschedulablePodsMap(metrics.NewGatedPodsRecorder()),
 		moveRequestCycle:          -1,
 		clusterEventMap:           options.clusterEventMap,
+		queueingHintMap:           options.queueingHintMap,
+		preEnqueuePluginMap:       options.preEnqueuePluginMap,
+		profileName:               options.profileName,
+		popPolicy:                 options.popPolicy,
+		nodeInfos:                 options.nodeInfos,
+		popWindow:                 options.popWindow,
+		maxPopSkip:                options.maxPopSkip,
+		skippedSince:              make(map[types.UID]time.Time),
 	}
 	pq.cond.L = &pq.lock
 	pq.podBackoffQ = heap.NewWithRecorder(podInfoKeyFunc, pq.podsCompareBackoffCompleted, metrics.NewBackoffPodsRecorder())
@@ -272,18 +397,53 @@ func NewPriorityQueue(
 	return pq
 }
 
+// queuedPodInfoLess adapts lessFn to compare the items of activeQ.
+func queuedPodInfoLess(lessFn framework.LessFunc) func(interface{}, interface{}) bool {
+	return func(podInfo1, podInfo2 interface{}) bool {
+		pInfo1 := podInfo1.(*framework.QueuedPodInfo)
+		pInfo2 := podInfo2.(*framework.QueuedPodInfo)
+		return lessFn(pInfo1, pInfo2)
+	}
+}
+
+// UpdateProfiles replaces the function sorting activeQ, if not nil, and the
+// PreEnqueue plugins of the profiles in preEnqueuePluginMap. activeQ is sorted
+// again with the new function. The pods already gated stay so until an event
+// moves them.
+func (p *PriorityQueue) UpdateProfiles(lessFn framework.LessFunc, preEnqueuePluginMap map[string][]framework.PreEnqueuePlugin) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if lessFn != nil {
+		p.activeQ.SetLessFunc(queuedPodInfoLess(lessFn))
+	}
+	merged := make(map[string][]framework.PreEnqueuePlugin, len(p.preEnqueuePluginMap))
+	for name, plugins := range p.preEnqueuePluginMap {
+		merged[name] = plugins
+	}
+	for name, plugins := range preEnqueuePluginMap {
+		merged[name] = plugins
+	}
+	p.preEnqueuePluginMap = merged
+}
+
 // Run starts the goroutine to pump from podBackoffQ to activeQ
 func (p *PriorityQueue) Run() {
 	go wait.Until(p.flushBackoffQCompleted, 1.0*time.Second, p.stop)
 	go wait.Until(p.flushUnschedulableQLeftover, 30*time.Second, p.stop)
 }
 
-// Add adds a pod to the active queue. It should be called only when a new pod
-// is added so there is no chance the pod is already in active/unschedulable/backoff queues
+// Add adds a pod to the active queue, or to gatedPods if a PreEnqueue plugin
+// rejects it. It should be called only when a new pod is added so there is no
+// chance the pod is already in active/unschedulable/backoff queues
 func (p *PriorityQueue) Add(pod *v1.Pod) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	pInfo := p.newQueuedPodInfo(pod)
+	if !p.runPreEnqueuePlugins(pInfo) {
+		p.gatedPods.addOrUpdate(pInfo)
+		metrics.SchedulerQueueIncomingPods.WithLabelValues("gated", PodAdd).Inc()
+		return nil
+	}
 	if err := p.activeQ.Add(pInfo); err != nil {
 		klog.ErrorS(err, "Error adding pod to the active queue", "pod", klog.KObj(pod))
 		return err
@@ -303,6 +463,35 @@ func (p *PriorityQueue) Add(pod *v1.Pod) error {
 	return nil
 }
 
+// runPreEnqueuePlugins runs the PreEnqueue plugins of the profile of the pod,
+// and returns whether all of them let the pod in activeQ. Otherwise, the pod
+// is marked as gated by the first plugin that rejected it.
+// NOTE: this function assumes lock has been acquired in caller.
+func (p *PriorityQueue) runPreEnqueuePlugins(pInfo *framework.QueuedPodInfo) bool {
+	pod := pInfo.Pod
+	for _, pl := range p.preEnqueuePluginMap[p.profileName(pod)] {
+		s := pl.PreEnqueue(context.Background(), pod)
+		if s.IsSuccess() {
+			continue
+		}
+		if s.Code() == framework.Error {
+			klog.ErrorS(s.AsError(), "Unexpected error running PreEnqueue plugin", "pod", klog.KObj(pod), "plugin", pl.Name())
+		} else {
+			klog.V(5).InfoS("Pod is gated by PreEnqueue plugin", "pod", klog.KObj(pod), "plugin", pl.Name(), "reason", s.Message())
+		}
+		pInfo.GatingPlugin = pl.Name()
+		pInfo.GatingMessage = s.Message()
+		// Record the gating plugin as unschedulable plugin, so that the events
+		// it registered are the ones re-running the PreEnqueue plugins.
+		pInfo.UnschedulablePlugins = sets.NewString(pl.Name())
+		return false
+	}
+	pInfo.GatingPlugin = ""
+	pInfo.GatingMessage = ""
+	pInfo.UnschedulablePlugins = sets.NewString()
+	return true
+}
+
 // Activate moves the given pods to activeQ iff they're in unschedulableQ or backoffQ.
 func (p *PriorityQueue) Activate(pods map[string]*v1.Pod) {
 	p.lock.Lock()
@@ -326,6 +515,10 @@ func (p *PriorityQueue) activate(pod *v1.Pod) bool {
 		// No need to activate if it's already present in activeQ.
 		return false
 	}
+	// Gated pods are only let in activeQ by the PreEnqueue plugins.
+	if p.gatedPods.get(pod) != nil {
+		return false
+	}
 	var pInfo *framework.QueuedPodInfo
 	// Verify if the pod is present in unschedulableQ or backoffQ.
 	if pInfo = p.unschedulableQ.get(pod); pInfo == nil {
@@ -390,6 +583,7 @@ func (p *PriorityQueue) AddUnschedulableIfNotPresent(pInfo *framework.QueuedPodI
 
 	// Refresh the timestamp since the pod is re-added.
 	pInfo.Timestamp = p.clock.Now()
+	metrics.PodBackoffDuration.Observe(p.calculateBackoffDuration(pInfo).Seconds())
 
 	// If a move request has been received, move it to the BackoffQ, otherwise move
 	// it to unschedulableQ.
@@ -448,7 +642,7 @@ func (p *PriorityQueue) flushUnschedulableQLeftover() {
 	}
 
 	if len(podsToMove) > 0 {
-		p.movePodsToActiveOrBackoffQueue(podsToMove, UnschedulableTimeout)
+		p.movePodsToActiveOrBackoffQueue(podsToMove, UnschedulableTimeout, nil, nil)
 	}
 }
 
@@ -458,23 +652,175 @@ func (p *PriorityQueue) flushUnschedulableQLeftover() {
 func (p *PriorityQueue) Pop() (*framework.QueuedPodInfo, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
-	for p.activeQ.Len() == 0 {
-		// When the queue is empty, invocation of Pop() is blocked until new item is enqueued.
-		// When Close() is called, the p.closed is set and the condition is broadcast,
-		// which causes this loop to continue and return from the Pop().
-		if p.closed {
-			return nil, fmt.Errorf(queueClosed)
+	for {
+		for p.activeQ.Len() == 0 {
+			// When the queue is empty, invocation of Pop() is blocked until new item is enqueued.
+			// When Close() is called, the p.closed is set and the condition is broadcast,
+			// which causes this loop to continue and return from the Pop().
+			if p.closed {
+				return nil, fmt.Errorf(queueClosed)
+			}
+			p.cond.Wait()
+		}
+		pInfo, err := p.popActiveQ(nil)
+		if err != nil {
+			return nil, err
+		}
+		if pInfo == nil {
+			// activeQ was emptied while the PopPolicy was picking.
+			continue
+		}
+		pInfo.Attempts++
+		p.schedulingCycle++
+		return pInfo, nil
+	}
+}
+
+// popActiveQ removes from activeQ the pod picked by the PopPolicy among the
+// pods at its head, or the head of activeQ if there is no PopPolicy. If match
+// is not nil, the pod is only removed if it satisfies match, and nil is
+// returned otherwise. It must be called with the lock held and activeQ not
+// empty. The lock is released while the PopPolicy picks, so nil is returned if
+// activeQ is empty by then.
+func (p *PriorityQueue) popActiveQ(match func(*framework.QueuedPodInfo) bool) (*framework.QueuedPodInfo, error) {
+	if p.popPolicy == nil || p.popWindow < 2 || p.activeQ.Len() < 2 {
+		return p.popActiveQHead(match)
+	}
+	candidates, err := p.headOfActiveQ(p.popWindow)
+	if err != nil {
+		return nil, err
+	}
+	picked := p.starvingCandidate(candidates)
+	if picked == -1 {
+		// The PopPolicy may take a while, so don't block the informers and
+		// the other users of the queue meanwhile. It is given copies of the
+		// candidates, which Update modifies in place.
+		copies := make([]*framework.QueuedPodInfo, 0, len(candidates))
+		for _, pInfo := range candidates {
+			copies = append(copies, pInfo.DeepCopy())
 		}
-		p.cond.Wait()
+		p.lock.Unlock()
+		picked = p.pick(copies)
+		p.lock.Lock()
+	}
+
+	// The picked pod may have been removed, updated or popped while the lock
+	// was released, so it is looked up again by its key.
+	obj, exists, err := p.activeQ.Get(candidates[picked])
+	if err == nil && exists && match != nil && !match(obj.(*framework.QueuedPodInfo)) {
+		return nil, nil
+	}
+	now := p.clock.Now()
+	for i, pInfo := range candidates {
+		if i == picked {
+			delete(p.skippedSince, pInfo.Pod.UID)
+		} else if _, ok := p.skippedSince[pInfo.Pod.UID]; !ok {
+			p.skippedSince[pInfo.Pod.UID] = now
+		}
+	}
+	if err != nil || !exists {
+		klog.V(4).InfoS("Pod picked by the pop policy left the active queue, popping the head of the active queue instead", "pod", klog.KObj(candidates[picked].Pod))
+		if p.activeQ.Len() == 0 {
+			return nil, nil
+		}
+		return p.popActiveQHead(match)
+	}
+	if err := p.activeQ.Delete(obj); err != nil {
+		return nil, err
+	}
+	return obj.(*framework.QueuedPodInfo), nil
+}
+
+// popActiveQHead removes the head of activeQ and returns it, if it satisfies
+// match when match is not nil. It must be called with the lock held and
+// activeQ not empty.
+func (p *PriorityQueue) popActiveQHead(match func(*framework.QueuedPodInfo) bool) (*framework.QueuedPodInfo, error) {
+	if match != nil && !match(p.activeQ.Peek().(*framework.QueuedPodInfo)) {
+		return nil, nil
 	}
 	obj, err := p.activeQ.Pop()
 	if err != nil {
 		return nil, err
 	}
 	pInfo := obj.(*framework.QueuedPodInfo)
+	delete(p.skippedSince, pInfo.Pod.UID)
+	return pInfo, nil
+}
+
+// headOfActiveQ returns up to n pods at the head of activeQ, in order, leaving
+// them in activeQ. It must be called with the lock held.
+func (p *PriorityQueue) headOfActiveQ(n int) ([]*framework.QueuedPodInfo, error) {
+	head := make([]*framework.QueuedPodInfo, 0, n)
+	for len(head) < n && p.activeQ.Len() > 0 {
+		obj, err := p.activeQ.Pop()
+		if err != nil {
+			return nil, err
+		}
+		head = append(head, obj.(*framework.QueuedPodInfo))
+	}
+	for _, pInfo := range head {
+		if err := p.activeQ.Add(pInfo); err != nil {
+			return nil, err
+		}
+	}
+	return head, nil
+}
+
+// starvingCandidate returns the index of the first candidate that was skipped
+// by the PopPolicy for maxPopSkip or longer, so that no pod starves, or -1 if
+// there is none. It must be called with the lock held.
+func (p *PriorityQueue) starvingCandidate(candidates []*framework.QueuedPodInfo) int {
+	now := p.clock.Now()
+	for i, pInfo := range candidates {
+		if since, ok := p.skippedSince[pInfo.Pod.UID]; ok && now.Sub(since) >= p.maxPopSkip {
+			klog.V(4).InfoS("Popping pod skipped for too long by the pop policy", "pod", klog.KObj(pInfo.Pod), "skipped", now.Sub(since))
+			return i
+		}
+	}
+	return -1
+}
+
+// pick returns the index of the candidate picked by the PopPolicy, falling
+// back to the first candidate if it fails. It must be called without the lock
+// held, as the PopPolicy may call out to an external agent.
+func (p *PriorityQueue) pick(candidates []*framework.QueuedPodInfo) int {
+	var nodes framework.NodeInfoLister
+	if p.nodeInfos != nil {
+		var err error
+		if nodes, err = p.nodeInfos(); err != nil {
+			klog.ErrorS(err, "Failed to get the state of the cluster for the pop policy, popping the head of the active queue")
+			return 0
+		}
+	}
+	i, err := p.popPolicy.Pick(candidates, nodes)
+	switch {
+	case err != nil:
+		klog.ErrorS(err, "Pop policy failed, popping the head of the active queue")
+		return 0
+	case i < 0 || i >= len(candidates):
+		klog.ErrorS(nil, "Pop policy picked an invalid candidate, popping the head of the active queue", "index", i, "candidates", len(candidates))
+		return 0
+	default:
+		return i
+	}
+}
+
+// PopIf removes from activeQ the pod Pop would return next, picked by the
+// PopPolicy if there is one, and returns it if it satisfies match. It returns
+// nil, without blocking, if activeQ is empty or the pod doesn't match.
+func (p *PriorityQueue) PopIf(match func(*framework.QueuedPodInfo) bool) *framework.QueuedPodInfo {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.activeQ.Len() == 0 {
+		return nil
+	}
+	pInfo, err := p.popActiveQ(match)
+	if err != nil || pInfo == nil {
+		return nil
+	}
 	pInfo.Attempts++
 	p.schedulingCycle++
-	return pInfo, err
+	return pInfo
 }
 
 // isPodUpdated checks if the pod is updated in a way that it may have become
@@ -517,6 +863,23 @@ func (p *PriorityQueue) Update(oldPod, newPod *v1.Pod) error {
 		}
 	}
 
+	// If the pod is gated, updating it may let it through the PreEnqueue plugins.
+	if gatedPodInfo := p.gatedPods.get(newPod); gatedPodInfo != nil {
+		pInfo := updatePod(gatedPodInfo, newPod)
+		if !p.runPreEnqueuePlugins(pInfo) {
+			p.gatedPods.addOrUpdate(pInfo)
+			return nil
+		}
+		if err := p.activeQ.Add(pInfo); err != nil {
+			return err
+		}
+		p.gatedPods.delete(pInfo.Pod)
+		metrics.SchedulerQueueIncomingPods.WithLabelValues("active", PodUpdate).Inc()
+		p.PodNominator.AddNominatedPod(pInfo.PodInfo, nil)
+		p.cond.Broadcast()
+		return nil
+	}
+
 	// If the pod is in the unschedulable queue, updating it may make it schedulable.
 	if usPodInfo := p.unschedulableQ.get(newPod); usPodInfo != nil {
 		pInfo := updatePod(usPodInfo, newPod)
@@ -541,8 +904,14 @@ func (p *PriorityQueue) Update(oldPod, newPod *v1.Pod) error {
 
 		return nil
 	}
-	// If pod is not in any of the queues, we put it in the active queue.
+	// If pod is not in any of the queues, we put it in the active queue, unless
+	// a PreEnqueue plugin rejects it.
 	pInfo := p.newQueuedPodInfo(newPod)
+	if !p.runPreEnqueuePlugins(pInfo) {
+		p.gatedPods.addOrUpdate(pInfo)
+		metrics.SchedulerQueueIncomingPods.WithLabelValues("gated", PodUpdate).Inc()
+		return nil
+	}
 	if err := p.activeQ.Add(pInfo); err != nil {
 		return err
 	}
@@ -551,16 +920,18 @@ func (p *PriorityQueue) Update(oldPod, newPod *v1.Pod) error {
 	return nil
 }
 
-// Delete deletes the item from either of the two queues. It assumes the pod is
+// Delete deletes the item from any of the queues. It assumes the pod is
 // only in one queue.
 func (p *PriorityQueue) Delete(pod *v1.Pod) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	p.PodNominator.DeleteNominatedPodIfExists(pod)
+	delete(p.skippedSince, pod.UID)
 	if err := p.activeQ.Delete(newQueuedPodInfoForLookup(pod)); err != nil {
 		// The item was probably not found in the activeQ.
 		p.podBackoffQ.Delete(newQueuedPodInfoForLookup(pod))
 		p.unschedulableQ.delete(pod)
+		p.gatedPods.delete(pod)
 	}
 	return nil
 }
@@ -569,7 +940,7 @@ func (p *PriorityQueue) Delete(pod *v1.Pod) error {
 // may make pending pods with matching affinity terms schedulable.
 func (p *PriorityQueue) AssignedPodAdded(pod *v1.Pod) {
 	p.lock.Lock()
-	p.movePodsToActiveOrBackoffQueue(p.getUnschedulablePodsWithMatchingAffinityTerm(pod), AssignedPodAdd)
+	p.movePodsToActiveOrBackoffQueue(p.getUnschedulablePodsWithMatchingAffinityTerm(pod), AssignedPodAdd, nil, pod)
 	p.lock.Unlock()
 }
 
@@ -577,7 +948,7 @@ func (p *PriorityQueue) AssignedPodAdded(pod *v1.Pod) {
 // may make pending pods with matching affinity terms schedulable.
 func (p *PriorityQueue) AssignedPodUpdated(pod *v1.Pod) {
 	p.lock.Lock()
-	p.movePodsToActiveOrBackoffQueue(p.getUnschedulablePodsWithMatchingAffinityTerm(pod), AssignedPodUpdate)
+	p.movePodsToActiveOrBackoffQueue(p.getUnschedulablePodsWithMatchingAffinityTerm(pod), AssignedPodUpdate, nil, pod)
 	p.lock.Unlock()
 }
 
@@ -585,7 +956,9 @@ func (p *PriorityQueue) AssignedPodUpdated(pod *v1.Pod) {
 // This function adds all pods and then signals the condition variable to ensure that
 // if Pop() is waiting for an item, it receives the signal after all the pods are in the
 // queue and the head is the highest priority pod.
-func (p *PriorityQueue) MoveAllToActiveOrBackoffQueue(event framework.ClusterEvent, preCheck PreEnqueueCheck) {
+// Gated pods whose gating plugin registered the event are moved to activeQ if
+// the PreEnqueue plugins let them through.
+func (p *PriorityQueue) MoveAllToActiveOrBackoffQueue(event framework.ClusterEvent, oldObj, newObj interface{}, preCheck PreEnqueueCheck) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	unschedulablePods := make([]*framework.QueuedPodInfo, 0, len(p.unschedulableQ.podInfoMap))
@@ -594,18 +967,48 @@ func (p *PriorityQueue) MoveAllToActiveOrBackoffQueue(event framework.ClusterEve
 			unschedulablePods = append(unschedulablePods, pInfo)
 		}
 	}
-	p.movePodsToActiveOrBackoffQueue(unschedulablePods, event)
+	var gatedPods []*framework.QueuedPodInfo
+	for _, pInfo := range p.gatedPods.podInfoMap {
+		if preCheck == nil || preCheck(pInfo.Pod) {
+			gatedPods = append(gatedPods, pInfo)
+		}
+	}
+	p.moveGatedPodsToActiveQueue(gatedPods, event, oldObj, newObj)
+	p.movePodsToActiveOrBackoffQueue(unschedulablePods, event, oldObj, newObj)
 }
 
+// moveGatedPodsToActiveQueue re-runs the PreEnqueue plugins on the gated pods
+// the event may let through, and moves the ones let through to activeQ.
 // NOTE: this function assumes lock has been acquired in caller
-func (p *PriorityQueue) movePodsToActiveOrBackoffQueue(podInfoList []*framework.QueuedPodInfo, event framework.ClusterEvent) {
+func (p *PriorityQueue) moveGatedPodsToActiveQueue(podInfoList []*framework.QueuedPodInfo, event framework.ClusterEvent, oldObj, newObj interface{}) {
+	moved := false
+	for _, pInfo := range podInfoList {
+		if !p.podMatchesEvent(pInfo, event, oldObj, newObj) || !p.runPreEnqueuePlugins(pInfo) {
+			continue
+		}
+		if err := p.activeQ.Add(pInfo); err != nil {
+			klog.ErrorS(err, "Error adding pod to the scheduling queue", "pod", klog.KObj(pInfo.Pod))
+			continue
+		}
+		moved = true
+		metrics.SchedulerQueueIncomingPods.WithLabelValues("active", event.Label).Inc()
+		p.gatedPods.delete(pInfo.Pod)
+		p.PodNominator.AddNominatedPod(pInfo.PodInfo, nil)
+	}
+	if moved {
+		p.cond.Broadcast()
+	}
+}
+
+// NOTE: this function assumes lock has been acquired in caller
+func (p *PriorityQueue) movePodsToActiveOrBackoffQueue(podInfoList []*framework.QueuedPodInfo, event framework.ClusterEvent, oldObj, newObj interface{}) {
 	moved := false
 	for _, pInfo := range podInfoList {
 		// If the event doesn't help making the Pod schedulable, continue.
 		// Note: we don't run the check if pInfo.UnschedulablePlugins is nil, which denotes
 		// either there is some abnormal error, or scheduling the pod failed by plugins other than PreFilter, Filter and Permit.
 		// In that case, it's desired to move it anyways.
-		if len(pInfo.UnschedulablePlugins) != 0 && !p.podMatchesEvent(pInfo, event) {
+		if len(pInfo.UnschedulablePlugins) != 0 && !p.podMatchesEvent(pInfo, event, oldObj, newObj) {
 			continue
 		}
 		moved = true
@@ -669,6 +1072,57 @@ func (p *PriorityQueue) PendingPods() []*v1.Pod {
 	for _, pInfo := range p.unschedulableQ.podInfoMap {
 		result = append(result, pInfo.Pod)
 	}
+	for _, pInfo := range p.gatedPods.podInfoMap {
+		result = append(result, pInfo.Pod)
+	}
+	return result
+}
+
+// Names of the sub-queues of PriorityQueue, as reported by PendingPodInfos.
+const (
+	ActiveQName        = "active"
+	BackoffQName       = "backoff"
+	UnschedulableQName = "unschedulable"
+	GatedQName         = "gated"
+)
+
+// PendingPodInfo describes a pod pending in the scheduling queue.
+type PendingPodInfo struct {
+	*framework.QueuedPodInfo
+	// Queue is the name of the sub-queue the pod is in.
+	Queue string
+	// BackoffExpiry is the time the backoff of the pod expires at. It's zero
+	// for pods in activeQ and gated pods.
+	BackoffExpiry time.Time
+}
+
+// PendingPodInfos returns a copy of the pods pending in the queue, along
+// with the sub-queue each of them is in. This function is used for debugging
+// purposes.
+func (p *PriorityQueue) PendingPodInfos() []*PendingPodInfo {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	var result []*PendingPodInfo
+	add := func(pInfo *framework.QueuedPodInfo, queue string) {
+		pp := &PendingPodInfo{QueuedPodInfo: pInfo.DeepCopy(), Queue: queue}
+		pp.UnschedulablePlugins = sets.NewString(pInfo.UnschedulablePlugins.UnsortedList()...)
+		if queue == BackoffQName || queue == UnschedulableQName {
+			pp.BackoffExpiry = p.getBackoffTime(pInfo)
+		}
+		result = append(result, pp)
+	}
+	for _, pInfo := range p.activeQ.List() {
+		add(pInfo.(*framework.QueuedPodInfo), ActiveQName)
+	}
+	for _, pInfo := range p.podBackoffQ.List() {
+		add(pInfo.(*framework.QueuedPodInfo), BackoffQName)
+	}
+	for _, pInfo := range p.unschedulableQ.podInfoMap {
+		add(pInfo, UnschedulableQName)
+	}
+	for _, pInfo := range p.gatedPods.podInfoMap {
+		add(pInfo, GatedQName)
+	}
 	return result
 }
 
@@ -740,15 +1194,7 @@ func (p *PriorityQueue) getBackoffTime(podInfo *framework.QueuedPodInfo) time.Ti
 // calculateBackoffDuration is a helper function for calculating the backoffDuration
 // based on the number of attempts the pod has made.
 func (p *PriorityQueue) calculateBackoffDuration(podInfo *framework.QueuedPodInfo) time.Duration {
-	duration := p.podInitialBackoffDuration
-	for i := 1; i < podInfo.Attempts; i++ {
-		// Use subtraction instead of addition or multiplication to avoid overflow.
-		if duration > p.podMaxBackoffDuration-duration {
-			return p.podMaxBackoffDuration
-		}
-		duration += duration
-	}
-	return duration
+	return p.backoffPolicy.Backoff(podInfo)
 }
 
 func updatePod(oldPodInfo interface{}, newPod *v1.Pod) *framework.QueuedPodInfo {
@@ -938,8 +1384,10 @@ func podInfoKeyFunc(obj interface{}) (string, error) {
 }
 
 // Checks if the Pod may become schedulable upon the event.
-// This is achieved by looking up the global clusterEventMap registry.
-func (p *PriorityQueue) podMatchesEvent(podInfo *framework.QueuedPodInfo, clusterEvent framework.ClusterEvent) bool {
+// This is achieved by looking up the global clusterEventMap registry, and
+// then asking the hint functions of the matching plugins, if any, whether
+// the objects of the event may make the Pod schedulable.
+func (p *PriorityQueue) podMatchesEvent(podInfo *framework.QueuedPodInfo, clusterEvent framework.ClusterEvent, oldObj, newObj interface{}) bool {
 	if clusterEvent.IsWildCard() {
 		return true
 	}
@@ -954,24 +1402,22 @@ func (p *PriorityQueue) podMatchesEvent(podInfo *framework.QueuedPodInfo, cluste
 		evtMatch := evt.IsWildCard() ||
 			(evt.Resource == clusterEvent.Resource && evt.ActionType&clusterEvent.ActionType != 0)
 
-		// Secondly verify the plugin name matches.
-		// Note that if it doesn't match, we shouldn't continue to search.
-		if evtMatch && intersect(nameSet, podInfo.UnschedulablePlugins) {
-			return true
+		if !evtMatch {
+			continue
 		}
-	}
-
-	return false
-}
-
-func intersect(x, y sets.String) bool {
-	if len(x) > len(y) {
-		x, y = y, x
-	}
-	for v := range x {
-		if y.Has(v) {
-			return true
+		// Secondly verify the plugin name matches, and that the plugin doesn't
+		// tell the event can't make the Pod schedulable.
+		for name := range podInfo.UnschedulablePlugins {
+			if !nameSet.Has(name) {
+				continue
+			}
+			hintFn, ok := p.queueingHintMap[evt][name]
+			if !ok || hintFn(podInfo.Pod, oldObj, newObj) == framework.Queue {
+				return true
+			}
+			klog.V(5).InfoS("Event is skipped by the queueing hint of the plugin", "pod", klog.KObj(podInfo.Pod), "event", clusterEvent.Label, "plugin", name)
 		}
 	}
+
 	return false
 }
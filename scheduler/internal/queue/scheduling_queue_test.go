@@ -29,6 +29,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -40,6 +41,7 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
 	"k8s.io/kubernetes/pkg/scheduler/metrics"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
 	"k8s.io/kubernetes/pkg/scheduler/util"
 	testingclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/pointer"
@@ -252,7 +254,7 @@ func TestPriorityQueue_AddUnschedulableIfNotPresent_Backoff(t *testing.T) {
 	}
 
 	// move all pods to active queue when we were trying to schedule them
-	q.MoveAllToActiveOrBackoffQueue(TestEvent, nil)
+	q.MoveAllToActiveOrBackoffQueue(TestEvent, nil, nil, nil)
 	oldCycle := q.SchedulingCycle()
 
 	firstPod, _ := q.Pop()
@@ -305,6 +307,189 @@ func TestPriorityQueue_Pop(t *testing.T) {
 	wg.Wait()
 }
 
+func TestPriorityQueue_PopIf(t *testing.T) {
+	q := NewTestQueue(context.Background(), newDefaultQueueSort())
+	matchName := func(name string) func(*framework.QueuedPodInfo) bool {
+		return func(pInfo *framework.QueuedPodInfo) bool {
+			return pInfo.Pod.Name == name
+		}
+	}
+	if p := q.PopIf(matchName(highPriorityPodInfo.Pod.Name)); p != nil {
+		t.Errorf("Expected nil from an empty queue, but got: %v", p.Pod.Name)
+	}
+	q.Add(highPriorityPodInfo.Pod)
+	q.Add(medPriorityPodInfo.Pod)
+	if p := q.PopIf(matchName(medPriorityPodInfo.Pod.Name)); p != nil {
+		t.Errorf("Expected nil when the head doesn't match, but got: %v", p.Pod.Name)
+	}
+	p := q.PopIf(matchName(highPriorityPodInfo.Pod.Name))
+	if p == nil || p.Pod != highPriorityPodInfo.Pod {
+		t.Fatalf("Expected: %v after PopIf, but got: %v", highPriorityPodInfo.Pod.Name, p)
+	}
+	if p.Attempts != 1 {
+		t.Errorf("Expected 1 attempt, but got: %v", p.Attempts)
+	}
+	if q.SchedulingCycle() != 1 {
+		t.Errorf("Expected scheduling cycle 1, but got: %v", q.SchedulingCycle())
+	}
+}
+
+// popPolicyFunc is a framework.PopPolicy calling itself to pick a pod.
+type popPolicyFunc func(candidates []*framework.QueuedPodInfo) (int, error)
+
+func (f popPolicyFunc) Pick(candidates []*framework.QueuedPodInfo, _ framework.NodeInfoLister) (int, error) {
+	return f(candidates)
+}
+
+func TestPriorityQueue_PopPolicy(t *testing.T) {
+	pickLast := popPolicyFunc(func(candidates []*framework.QueuedPodInfo) (int, error) {
+		return len(candidates) - 1, nil
+	})
+	tests := []struct {
+		name       string
+		policy     framework.PopPolicy
+		window     int
+		maxPopSkip time.Duration
+		want       []string
+	}{
+		{
+			name: "no policy",
+			want: []string{"p0", "p1", "p2", "p3", "p4"},
+		},
+		{
+			name:       "policy picks within the window",
+			policy:     pickLast,
+			window:     3,
+			maxPopSkip: time.Hour,
+			want:       []string{"p2", "p3", "p4", "p1", "p0"},
+		},
+		{
+			name:       "pods skipped for too long are popped",
+			policy:     pickLast,
+			window:     3,
+			maxPopSkip: 2 * time.Second,
+			want:       []string{"p2", "p3", "p0", "p1", "p4"},
+		},
+		{
+			name: "failing policy falls back to the head",
+			policy: popPolicyFunc(func([]*framework.QueuedPodInfo) (int, error) {
+				return 0, fmt.Errorf("agent unavailable")
+			}),
+			window:     3,
+			maxPopSkip: time.Hour,
+			want:       []string{"p0", "p1", "p2", "p3", "p4"},
+		},
+		{
+			name: "invalid pick falls back to the head",
+			policy: popPolicyFunc(func(candidates []*framework.QueuedPodInfo) (int, error) {
+				return len(candidates), nil
+			}),
+			window:     3,
+			maxPopSkip: time.Hour,
+			want:       []string{"p0", "p1", "p2", "p3", "p4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testingclock.NewFakeClock(time.Now())
+			opts := []Option{WithClock(c)}
+			if tt.policy != nil {
+				opts = append(opts, WithPopPolicy(tt.policy, nil), WithPopWindow(tt.window), WithMaxPopSkip(tt.maxPopSkip))
+			}
+			q := NewTestQueue(context.Background(), newDefaultQueueSort(), opts...)
+			for i := 0; i < len(tt.want); i++ {
+				name := fmt.Sprintf("p%d", i)
+				q.Add(&v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", UID: types.UID(name)},
+					Spec:       v1.PodSpec{Priority: pointer.Int32Ptr(int32(len(tt.want) - i))},
+				})
+			}
+			var got []string
+			for range tt.want {
+				c.Step(time.Second)
+				p, err := q.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, p.Pod.Name)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unexpected pop order (-want, +got):\n%s", diff)
+			}
+			if len(q.skippedSince) != 0 {
+				t.Errorf("Expected no skipped pods left, got %v", q.skippedSince)
+			}
+		})
+	}
+}
+
+func TestPriorityQueue_PopIfFollowsPopPolicy(t *testing.T) {
+	pickLast := popPolicyFunc(func(candidates []*framework.QueuedPodInfo) (int, error) {
+		return len(candidates) - 1, nil
+	})
+	q := NewTestQueue(context.Background(), newDefaultQueueSort(), WithPopPolicy(pickLast, nil), WithPopWindow(3), WithMaxPopSkip(time.Hour))
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("p%d", i)
+		q.Add(&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", UID: types.UID(name)},
+			Spec:       v1.PodSpec{Priority: pointer.Int32Ptr(int32(3 - i))},
+		})
+	}
+	matchName := func(name string) func(*framework.QueuedPodInfo) bool {
+		return func(pInfo *framework.QueuedPodInfo) bool {
+			return pInfo.Pod.Name == name
+		}
+	}
+	// The head of the queue is p0, but the policy picks p2.
+	if p := q.PopIf(matchName("p0")); p != nil {
+		t.Errorf("Expected nil when the picked pod doesn't match, but got: %v", p.Pod.Name)
+	}
+	if len(q.skippedSince) != 0 {
+		t.Errorf("Expected no skipped pods when nothing is popped, got %v", q.skippedSince)
+	}
+	if p := q.PopIf(matchName("p2")); p == nil || p.Pod.Name != "p2" {
+		t.Fatalf("Expected: p2 after PopIf, but got: %v", p)
+	}
+	if _, ok := q.skippedSince["p0"]; !ok {
+		t.Errorf("Expected p0 to be skipped by the pop policy, got %v", q.skippedSince)
+	}
+}
+
+func TestPriorityQueue_PopPolicyRunsUnlocked(t *testing.T) {
+	newPod := func(name string, priority int32) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", UID: types.UID(name)},
+			Spec:       v1.PodSpec{Priority: pointer.Int32Ptr(priority)},
+		}
+	}
+	var q *PriorityQueue
+	// The policy picks the last candidate after deleting it, as if the pod
+	// was deleted while the agent was picking. Deleting locks the queue, so
+	// this would deadlock if the lock was held.
+	policy := popPolicyFunc(func(candidates []*framework.QueuedPodInfo) (int, error) {
+		last := len(candidates) - 1
+		if err := q.Delete(candidates[last].Pod); err != nil {
+			return 0, err
+		}
+		return last, nil
+	})
+	q = NewTestQueue(context.Background(), newDefaultQueueSort(), WithPopPolicy(policy, nil), WithPopWindow(3), WithMaxPopSkip(time.Hour))
+	for i, name := range []string{"p0", "p1", "p2"} {
+		q.Add(newPod(name, int32(3-i)))
+	}
+
+	p, err := q.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Pod.Name != "p0" {
+		t.Errorf("Expected the head to be popped when the picked pod left the queue, got %v", p.Pod.Name)
+	}
+	if got := q.activeQ.Len(); got != 1 {
+		t.Errorf("Expected 1 pod left in activeQ, got %v", got)
+	}
+}
+
 func TestPriorityQueue_Update(t *testing.T) {
 	objs := []runtime.Object{highPriorityPodInfo.Pod, unschedulablePodInfo.Pod, medPriorityPodInfo.Pod}
 	c := testingclock.NewFakeClock(time.Now())
@@ -387,6 +572,112 @@ func TestPriorityQueue_Update(t *testing.T) {
 	}
 }
 
+// gatePlugin is a PreEnqueue plugin rejecting the pods with a "gated" label.
+type gatePlugin struct{}
+
+func (pl *gatePlugin) Name() string { return "Gate" }
+
+func (pl *gatePlugin) PreEnqueue(_ context.Context, p *v1.Pod) *framework.Status {
+	if _, ok := p.Labels["gated"]; ok {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, "pod is gated")
+	}
+	return nil
+}
+
+func TestPriorityQueue_PreEnqueue(t *testing.T) {
+	gatedPod := st.MakePod().Name("gated").UID("gated").SchedulerName("default-scheduler").Label("gated", "").Obj()
+	otherProfilePod := st.MakePod().Name("other").UID("other").SchedulerName("other-scheduler").Label("gated", "").Obj()
+	q := NewTestQueue(context.Background(), newDefaultQueueSort(),
+		WithPreEnqueuePluginMap(map[string][]framework.PreEnqueuePlugin{"default-scheduler": {&gatePlugin{}}}),
+		WithClusterEventMap(map[framework.ClusterEvent]sets.String{NodeAdd: sets.NewString("Gate")}),
+	)
+	if err := q.Add(gatedPod); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Add(otherProfilePod); err != nil {
+		t.Fatal(err)
+	}
+	if pInfo := q.gatedPods.get(gatedPod); pInfo == nil || pInfo.GatingPlugin != "Gate" || pInfo.GatingMessage != "pod is gated" {
+		t.Fatalf("Expected pod %v to be gated by plugin Gate, got %v", gatedPod.Name, pInfo)
+	}
+	if q.gatedPods.get(otherProfilePod) != nil {
+		t.Errorf("Expected pod %v of a profile without PreEnqueue plugins not to be gated", otherProfilePod.Name)
+	}
+	var queues []string
+	for _, pInfo := range q.PendingPodInfos() {
+		queues = append(queues, pInfo.Pod.Name+"/"+pInfo.Queue)
+	}
+	if diff := cmp.Diff([]string{"other/" + ActiveQName, "gated/" + GatedQName}, queues); diff != "" {
+		t.Errorf("Unexpected pending pods (-want, +got):\n%s", diff)
+	}
+
+	// Neither activating the gated pod nor an event it registered, while
+	// it's still gated, moves it to activeQ.
+	q.Activate(map[string]*v1.Pod{"gated": gatedPod})
+	q.MoveAllToActiveOrBackoffQueue(NodeAdd, nil, nil, nil)
+	if q.gatedPods.get(gatedPod) == nil {
+		t.Fatalf("Expected pod %v to stay gated", gatedPod.Name)
+	}
+
+	// Updating the pod so that it's no longer gated moves it to activeQ.
+	ungatedPod := gatedPod.DeepCopy()
+	ungatedPod.Labels = nil
+	if err := q.Update(gatedPod, ungatedPod); err != nil {
+		t.Fatal(err)
+	}
+	if q.gatedPods.get(ungatedPod) != nil {
+		t.Fatalf("Expected pod %v to be let through", ungatedPod.Name)
+	}
+	if _, exists, _ := q.activeQ.Get(newQueuedPodInfoForLookup(ungatedPod)); !exists {
+		t.Errorf("Expected pod %v to be added to activeQ", ungatedPod.Name)
+	}
+
+	// A gated pod is moved to activeQ on an event registered by the gating
+	// plugin, once the plugin lets it through.
+	if err := q.Delete(ungatedPod); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Add(gatedPod); err != nil {
+		t.Fatal(err)
+	}
+	q.gatedPods.get(gatedPod).Update(ungatedPod)
+	q.MoveAllToActiveOrBackoffQueue(AssignedPodDelete, nil, nil, nil)
+	if q.gatedPods.get(gatedPod) == nil {
+		t.Fatalf("Expected pod %v to stay gated on an event not registered by the plugin", gatedPod.Name)
+	}
+	q.MoveAllToActiveOrBackoffQueue(NodeAdd, nil, nil, nil)
+	if _, exists, _ := q.activeQ.Get(newQueuedPodInfoForLookup(ungatedPod)); !exists {
+		t.Errorf("Expected pod %v to be moved to activeQ", ungatedPod.Name)
+	}
+}
+
+func TestPriorityQueue_UpdateProfiles(t *testing.T) {
+	q := NewTestQueue(context.Background(), newDefaultQueueSort())
+	if err := q.Add(medPriorityPodInfo.Pod); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Add(highPriorityPodInfo.Pod); err != nil {
+		t.Fatal(err)
+	}
+	reversed := func(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+		return newDefaultQueueSort()(pInfo2, pInfo1)
+	}
+	q.UpdateProfiles(reversed, map[string][]framework.PreEnqueuePlugin{"default-scheduler": {&gatePlugin{}}})
+
+	// activeQ is sorted again with the new function.
+	if p, err := q.Pop(); err != nil || p.Pod != medPriorityPodInfo.Pod {
+		t.Errorf("Expected: %v after Pop, but got: %v", medPriorityPodInfo.Pod.Name, p.Pod.Name)
+	}
+	// The new PreEnqueue plugins gate the pods added afterwards.
+	gatedPod := st.MakePod().Name("gated").UID("gated").SchedulerName("default-scheduler").Label("gated", "").Obj()
+	if err := q.Add(gatedPod); err != nil {
+		t.Fatal(err)
+	}
+	if q.gatedPods.get(gatedPod) == nil {
+		t.Errorf("Expected pod %v to be gated", gatedPod.Name)
+	}
+}
+
 func TestPriorityQueue_Delete(t *testing.T) {
 	objs := []runtime.Object{highPriorityPodInfo.Pod, unschedulablePodInfo.Pod}
 	q := NewTestQueueWithObjects(context.Background(), newDefaultQueueSort(), objs)
@@ -514,10 +805,10 @@ func BenchmarkMoveAllToActiveOrBackoffQueue(b *testing.B) {
 
 					b.StartTimer()
 					if tt.moveEvent.Resource != "" {
-						q.MoveAllToActiveOrBackoffQueue(tt.moveEvent, nil)
+						q.MoveAllToActiveOrBackoffQueue(tt.moveEvent, nil, nil, nil)
 					} else {
 						// Random case.
-						q.MoveAllToActiveOrBackoffQueue(events[i%len(events)], nil)
+						q.MoveAllToActiveOrBackoffQueue(events[i%len(events)], nil, nil, nil)
 					}
 				}
 			})
@@ -543,7 +834,7 @@ func TestPriorityQueue_MoveAllToActiveOrBackoffQueue(t *testing.T) {
 	hpp2.Name = "hpp2"
 	q.AddUnschedulableIfNotPresent(q.newQueuedPodInfo(hpp2, "barPlugin"), q.SchedulingCycle())
 	// Pods is still backing off, move the pod into backoffQ.
-	q.MoveAllToActiveOrBackoffQueue(NodeAdd, nil)
+	q.MoveAllToActiveOrBackoffQueue(NodeAdd, nil, nil, nil)
 	if q.activeQ.Len() != 1 {
 		t.Errorf("Expected 1 item to be in activeQ, but got: %v", q.activeQ.Len())
 	}
@@ -569,7 +860,7 @@ func TestPriorityQueue_MoveAllToActiveOrBackoffQueue(t *testing.T) {
 	// Move clock by podInitialBackoffDuration, so that pods in the unschedulableQ would pass the backing off,
 	// and the pods will be moved into activeQ.
 	c.Step(q.podInitialBackoffDuration)
-	q.MoveAllToActiveOrBackoffQueue(NodeAdd, nil)
+	q.MoveAllToActiveOrBackoffQueue(NodeAdd, nil, nil, nil)
 	// hpp2 won't be moved regardless of its backoff timer.
 	if q.activeQ.Len() != 4 {
 		t.Errorf("Expected 4 items to be in activeQ, but got: %v", q.activeQ.Len())
@@ -733,7 +1024,7 @@ func TestPriorityQueue_PendingPods(t *testing.T) {
 		t.Error("Unexpected list of pending Pods.")
 	}
 	// Move all to active queue. We should still see the same set of pods.
-	q.MoveAllToActiveOrBackoffQueue(TestEvent, nil)
+	q.MoveAllToActiveOrBackoffQueue(TestEvent, nil, nil, nil)
 	if !reflect.DeepEqual(expectedSet, makeSet(q.PendingPods())) {
 		t.Error("Unexpected list of pending Pods...")
 	}
@@ -1039,7 +1330,7 @@ func TestRecentlyTriedPodsGoBack(t *testing.T) {
 	q.AddUnschedulableIfNotPresent(p1, q.SchedulingCycle())
 	c.Step(DefaultPodInitialBackoffDuration)
 	// Move all unschedulable pods to the active queue.
-	q.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil)
+	q.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil, nil, nil)
 	// Simulation is over. Now let's pop all pods. The pod popped first should be
 	// the last one we pop here.
 	for i := 0; i < 5; i++ {
@@ -1091,7 +1382,7 @@ func TestPodFailedSchedulingMultipleTimesDoesNotBlockNewerPod(t *testing.T) {
 	// Move clock to make the unschedulable pods complete backoff.
 	c.Step(DefaultPodInitialBackoffDuration + time.Second)
 	// Move all unschedulable pods to the active queue.
-	q.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil)
+	q.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil, nil, nil)
 
 	// Simulate a pod being popped by the scheduler,
 	// At this time, unschedulable pod should be popped.
@@ -1134,7 +1425,7 @@ func TestPodFailedSchedulingMultipleTimesDoesNotBlockNewerPod(t *testing.T) {
 	// Move clock to make the unschedulable pods complete backoff.
 	c.Step(DefaultPodInitialBackoffDuration + time.Second)
 	// Move all unschedulable pods to the active queue.
-	q.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil)
+	q.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil, nil, nil)
 
 	// At this time, newerPod should be popped
 	// because it is the oldest tried pod.
@@ -1199,7 +1490,7 @@ func TestHighPriorityBackoff(t *testing.T) {
 	// Put in the unschedulable queue.
 	q.AddUnschedulableIfNotPresent(p, q.SchedulingCycle())
 	// Move all unschedulable pods to the active queue.
-	q.MoveAllToActiveOrBackoffQueue(TestEvent, nil)
+	q.MoveAllToActiveOrBackoffQueue(TestEvent, nil, nil, nil)
 
 	p, err = q.Pop()
 	if err != nil {
@@ -1306,7 +1597,7 @@ var (
 		queue.podBackoffQ.Add(pInfo)
 	}
 	moveAllToActiveOrBackoffQ = func(queue *PriorityQueue, _ *framework.QueuedPodInfo) {
-		queue.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil)
+		queue.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil, nil, nil)
 	}
 	flushBackoffQ = func(queue *PriorityQueue, _ *framework.QueuedPodInfo) {
 		queue.clock.(*testingclock.FakeClock).Step(2 * time.Second)
@@ -1452,10 +1743,11 @@ func TestPendingPodsMetric(t *testing.T) {
 			},
 			metricsName: "scheduler_pending_pods",
 			wants: `
-# HELP scheduler_pending_pods [STABLE] Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ.
+# HELP scheduler_pending_pods [STABLE] Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ; 'gated' means number of pods held out of activeQ by PreEnqueue plugins.
 # TYPE scheduler_pending_pods gauge
 scheduler_pending_pods{queue="active"} 30
 scheduler_pending_pods{queue="backoff"} 0
+scheduler_pending_pods{queue="gated"} 0
 scheduler_pending_pods{queue="unschedulable"} 20
 `,
 		},
@@ -1473,10 +1765,11 @@ scheduler_pending_pods{queue="unschedulable"} 20
 			},
 			metricsName: "scheduler_pending_pods",
 			wants: `
-# HELP scheduler_pending_pods [STABLE] Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ.
+# HELP scheduler_pending_pods [STABLE] Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ; 'gated' means number of pods held out of activeQ by PreEnqueue plugins.
 # TYPE scheduler_pending_pods gauge
 scheduler_pending_pods{queue="active"} 15
 scheduler_pending_pods{queue="backoff"} 25
+scheduler_pending_pods{queue="gated"} 0
 scheduler_pending_pods{queue="unschedulable"} 10
 `,
 		},
@@ -1494,10 +1787,11 @@ scheduler_pending_pods{queue="unschedulable"} 10
 			},
 			metricsName: "scheduler_pending_pods",
 			wants: `
-# HELP scheduler_pending_pods [STABLE] Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ.
+# HELP scheduler_pending_pods [STABLE] Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ; 'gated' means number of pods held out of activeQ by PreEnqueue plugins.
 # TYPE scheduler_pending_pods gauge
 scheduler_pending_pods{queue="active"} 50
 scheduler_pending_pods{queue="backoff"} 0
+scheduler_pending_pods{queue="gated"} 0
 scheduler_pending_pods{queue="unschedulable"} 0
 `,
 		},
@@ -1517,10 +1811,11 @@ scheduler_pending_pods{queue="unschedulable"} 0
 			},
 			metricsName: "scheduler_pending_pods",
 			wants: `
-# HELP scheduler_pending_pods [STABLE] Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ.
+# HELP scheduler_pending_pods [STABLE] Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ; 'gated' means number of pods held out of activeQ by PreEnqueue plugins.
 # TYPE scheduler_pending_pods gauge
 scheduler_pending_pods{queue="active"} 30
 scheduler_pending_pods{queue="backoff"} 20
+scheduler_pending_pods{queue="gated"} 0
 scheduler_pending_pods{queue="unschedulable"} 0
 `,
 		},
@@ -1540,10 +1835,11 @@ scheduler_pending_pods{queue="unschedulable"} 0
 			},
 			metricsName: "scheduler_pending_pods",
 			wants: `
-# HELP scheduler_pending_pods [STABLE] Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ.
+# HELP scheduler_pending_pods [STABLE] Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ; 'gated' means number of pods held out of activeQ by PreEnqueue plugins.
 # TYPE scheduler_pending_pods gauge
 scheduler_pending_pods{queue="active"} 50
 scheduler_pending_pods{queue="backoff"} 0
+scheduler_pending_pods{queue="gated"} 0
 scheduler_pending_pods{queue="unschedulable"} 0
 `,
 		},
@@ -1553,6 +1849,7 @@ scheduler_pending_pods{queue="unschedulable"} 0
 		metrics.ActivePods().Set(0)
 		metrics.BackoffPods().Set(0)
 		metrics.UnschedulablePods().Set(0)
+		metrics.GatedPods().Set(0)
 	}
 
 	for _, test := range tests {
@@ -1786,7 +2083,7 @@ func TestBackOffFlow(t *testing.T) {
 			}
 
 			// An event happens.
-			q.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil)
+			q.MoveAllToActiveOrBackoffQueue(UnschedulableTimeout, nil, nil, nil)
 
 			if _, ok, _ := q.podBackoffQ.Get(podInfo); !ok {
 				t.Errorf("pod %v is not in the backoff queue", podID)
@@ -1822,6 +2119,7 @@ func TestPodMatchesEvent(t *testing.T) {
 		podInfo         *framework.QueuedPodInfo
 		event           framework.ClusterEvent
 		clusterEventMap map[framework.ClusterEvent]sets.String
+		queueingHintMap map[framework.ClusterEvent]map[string]framework.QueueingHintFn
 		want            bool
 	}{
 		{
@@ -1897,19 +2195,107 @@ func TestPodMatchesEvent(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name:    "queueing hint of the plugin skips the event",
+			podInfo: newQueuedPodInfoForLookup(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p"}}, "foo"),
+			event:   NodeAdd,
+			clusterEventMap: map[framework.ClusterEvent]sets.String{
+				NodeAllEvent: sets.NewString("foo"),
+			},
+			queueingHintMap: map[framework.ClusterEvent]map[string]framework.QueueingHintFn{
+				NodeAllEvent: {"foo": queueSkipHint},
+			},
+			want: false,
+		},
+		{
+			name:    "queueing hint of the plugin queues the event",
+			podInfo: newQueuedPodInfoForLookup(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p"}}, "foo"),
+			event:   NodeAdd,
+			clusterEventMap: map[framework.ClusterEvent]sets.String{
+				NodeAllEvent: sets.NewString("foo"),
+			},
+			queueingHintMap: map[framework.ClusterEvent]map[string]framework.QueueingHintFn{
+				NodeAllEvent: {"foo": queueHint},
+			},
+			want: true,
+		},
+		{
+			name:    "plugin without queueing hint matches although the other plugin skips the event",
+			podInfo: newQueuedPodInfoForLookup(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p"}}, "foo", "bar"),
+			event:   NodeAdd,
+			clusterEventMap: map[framework.ClusterEvent]sets.String{
+				NodeAllEvent: sets.NewString("foo", "bar"),
+			},
+			queueingHintMap: map[framework.ClusterEvent]map[string]framework.QueueingHintFn{
+				NodeAllEvent: {"foo": queueSkipHint},
+			},
+			want: true,
+		},
+		{
+			name:    "queueing hint of another event doesn't apply",
+			podInfo: newQueuedPodInfoForLookup(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p"}}, "foo"),
+			event:   NodeAdd,
+			clusterEventMap: map[framework.ClusterEvent]sets.String{
+				NodeAllEvent: sets.NewString("foo"),
+			},
+			queueingHintMap: map[framework.ClusterEvent]map[string]framework.QueueingHintFn{
+				AssignedPodDelete: {"foo": queueSkipHint},
+			},
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			q := NewTestQueue(context.Background(), newDefaultQueueSort())
 			q.clusterEventMap = tt.clusterEventMap
-			if got := q.podMatchesEvent(tt.podInfo, tt.event); got != tt.want {
+			q.queueingHintMap = tt.queueingHintMap
+			if got := q.podMatchesEvent(tt.podInfo, tt.event, nil, nil); got != tt.want {
 				t.Errorf("Want %v, but got %v", tt.want, got)
 			}
 		})
 	}
 }
 
+func queueHint(*v1.Pod, interface{}, interface{}) framework.QueueingHint {
+	return framework.Queue
+}
+
+func queueSkipHint(*v1.Pod, interface{}, interface{}) framework.QueueingHint {
+	return framework.QueueSkip
+}
+
+func TestMoveAllToActiveOrBackoffQueue_QueueingHints(t *testing.T) {
+	small := st.MakeNode().Name("small").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj()
+	large := st.MakeNode().Name("large").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj()
+	// fitsNode queues the pods when the added node has at least 2 CPUs.
+	fitsNode := func(pod *v1.Pod, oldObj, newObj interface{}) framework.QueueingHint {
+		node, ok := newObj.(*v1.Node)
+		if ok && node.Status.Allocatable.Cpu().Cmp(resource.MustParse("2")) >= 0 {
+			return framework.Queue
+		}
+		return framework.QueueSkip
+	}
+
+	q := NewTestQueue(context.Background(), newDefaultQueueSort(),
+		WithClusterEventMap(map[framework.ClusterEvent]sets.String{NodeAdd: sets.NewString("fit")}),
+		WithQueueingHintMap(map[framework.ClusterEvent]map[string]framework.QueueingHintFn{NodeAdd: {"fit": fitsNode}}),
+	)
+	pInfo := newQueuedPodInfoForLookup(st.MakePod().Name("p").UID("p").Obj(), "fit")
+	if err := q.AddUnschedulableIfNotPresent(pInfo, q.SchedulingCycle()); err != nil {
+		t.Fatal(err)
+	}
+
+	q.MoveAllToActiveOrBackoffQueue(NodeAdd, nil, small, nil)
+	if q.unschedulableQ.get(pInfo.Pod) == nil {
+		t.Errorf("Expected pod %v to stay in unschedulableQ after adding node %v", pInfo.Pod.Name, small.Name)
+	}
+	q.MoveAllToActiveOrBackoffQueue(NodeAdd, nil, large, nil)
+	if q.unschedulableQ.get(pInfo.Pod) != nil {
+		t.Errorf("Expected pod %v to leave unschedulableQ after adding node %v", pInfo.Pod.Name, large.Name)
+	}
+}
+
 func TestMoveAllToActiveOrBackoffQueue_PreEnqueueChecks(t *testing.T) {
 	var podInfos []*framework.QueuedPodInfo
 	for i := 0; i < 5; i++ {
@@ -1960,7 +2346,7 @@ func TestMoveAllToActiveOrBackoffQueue_PreEnqueueChecks(t *testing.T) {
 			for _, podInfo := range tt.podInfos {
 				q.AddUnschedulableIfNotPresent(podInfo, q.schedulingCycle)
 			}
-			q.MoveAllToActiveOrBackoffQueue(TestEvent, tt.preEnqueueCheck)
+			q.MoveAllToActiveOrBackoffQueue(TestEvent, nil, nil, tt.preEnqueueCheck)
 			var got []string
 			for q.podBackoffQ.Len() != 0 {
 				obj, err := q.podBackoffQ.Pop()
@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package weighttuner searches online for the weights of the Score plugins of
+// a profile that maximize an objective measured on the cluster.
+package weighttuner
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+)
+
+// Bandit is an epsilon-greedy multi-armed bandit whose arms are candidate
+// weight vectors of Score plugins. The candidates are the initial weights and
+// the vectors where the weight of a single tuned plugin is doubled or halved,
+// within [1, MaxWeight], which bounds the search to 2n+1 arms for n tuned
+// plugins.
+type Bandit struct {
+	arms    []*arm
+	current int
+	epsilon float64
+	rand    *rand.Rand
+}
+
+type arm struct {
+	weights map[string]int
+	label   string
+	pulls   int
+	mean    float64
+}
+
+// ArmStats holds the statistics of the objective measured with an arm.
+type ArmStats struct {
+	// Label identifies the weights of the arm, as "plugin=weight" pairs sorted
+	// by plugin name.
+	Label string
+	// Pulls is the number of times the objective was measured with the arm.
+	Pulls int
+	// Mean is the mean of the measured objective.
+	Mean float64
+}
+
+// New returns a Bandit over the weight vectors derived from the given weights
+// of the Score plugins, starting with them. r must be safe for concurrent use
+// if it is shared.
+func New(weights map[string]int, cfg *config.ScoreWeightTuning, r *rand.Rand) *Bandit {
+	tuned := cfg.Plugins
+	if len(tuned) == 0 {
+		for name := range weights {
+			tuned = append(tuned, name)
+		}
+	}
+	tuned = append([]string(nil), tuned...)
+	sort.Strings(tuned)
+
+	b := &Bandit{epsilon: cfg.Epsilon, rand: r}
+	seen := make(map[string]bool)
+	add := func(w map[string]int) {
+		a := &arm{weights: w, label: label(w)}
+		if seen[a.label] {
+			return
+		}
+		seen[a.label] = true
+		b.arms = append(b.arms, a)
+	}
+	add(copyWeights(weights))
+	for _, name := range tuned {
+		w, ok := weights[name]
+		if !ok {
+			continue
+		}
+		for _, candidate := range []int{w * 2, w / 2} {
+			if candidate < 1 {
+				candidate = 1
+			}
+			if candidate > int(cfg.MaxWeight) {
+				candidate = int(cfg.MaxWeight)
+			}
+			if candidate == w {
+				continue
+			}
+			updated := copyWeights(weights)
+			updated[name] = candidate
+			add(updated)
+		}
+	}
+	return b
+}
+
+// Weights returns the weights currently tried.
+func (b *Bandit) Weights() map[string]int {
+	return copyWeights(b.arms[b.current].weights)
+}
+
+// Label returns the label of the weights currently tried.
+func (b *Bandit) Label() string {
+	return b.arms[b.current].label
+}
+
+// Record records the objective measured with the weights currently tried, and
+// picks the weights to try next. Arms that were never tried are tried first,
+// in order. Then, a random arm is tried with probability epsilon, and the arm
+// with the highest mean objective otherwise.
+func (b *Bandit) Record(objective float64) {
+	a := b.arms[b.current]
+	a.pulls++
+	a.mean += (objective - a.mean) / float64(a.pulls)
+
+	for i, a := range b.arms {
+		if a.pulls == 0 {
+			b.current = i
+			return
+		}
+	}
+	if b.epsilon > 0 && b.rand.Float64() < b.epsilon {
+		b.current = b.rand.Intn(len(b.arms))
+		return
+	}
+	best := 0
+	for i, a := range b.arms {
+		if a.mean > b.arms[best].mean {
+			best = i
+		}
+	}
+	b.current = best
+}
+
+// Stats returns the statistics of the arms.
+func (b *Bandit) Stats() []ArmStats {
+	stats := make([]ArmStats, 0, len(b.arms))
+	for _, a := range b.arms {
+		stats = append(stats, ArmStats{Label: a.label, Pulls: a.pulls, Mean: a.mean})
+	}
+	return stats
+}
+
+func label(weights map[string]int) string {
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", name, weights[name]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func copyWeights(weights map[string]int) map[string]int {
+	c := make(map[string]int, len(weights))
+	for name, w := range weights {
+		c[name] = w
+	}
+	return c
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weighttuner
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+)
+
+func TestNewArms(t *testing.T) {
+	weights := map[string]int{"ImageLocality": 1, "NodeResourcesBalancedAllocation": 4, "TaintToleration": 3}
+	tests := []struct {
+		name     string
+		cfg      config.ScoreWeightTuning
+		wantArms []string
+	}{
+		{
+			name: "all plugins",
+			cfg:  config.ScoreWeightTuning{MaxWeight: 6},
+			wantArms: []string{
+				"ImageLocality=1,NodeResourcesBalancedAllocation=4,TaintToleration=3",
+				"ImageLocality=2,NodeResourcesBalancedAllocation=4,TaintToleration=3",
+				"ImageLocality=1,NodeResourcesBalancedAllocation=6,TaintToleration=3",
+				"ImageLocality=1,NodeResourcesBalancedAllocation=2,TaintToleration=3",
+				"ImageLocality=1,NodeResourcesBalancedAllocation=4,TaintToleration=6",
+				"ImageLocality=1,NodeResourcesBalancedAllocation=4,TaintToleration=1",
+			},
+		},
+		{
+			name: "some plugins",
+			cfg:  config.ScoreWeightTuning{Plugins: []string{"NodeResourcesBalancedAllocation", "Unknown"}, MaxWeight: 4},
+			wantArms: []string{
+				"ImageLocality=1,NodeResourcesBalancedAllocation=4,TaintToleration=3",
+				"ImageLocality=1,NodeResourcesBalancedAllocation=2,TaintToleration=3",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(weights, &tt.cfg, rand.New(rand.NewSource(1)))
+			var got []string
+			for _, s := range b.Stats() {
+				got = append(got, s.Label)
+			}
+			if diff := cmp.Diff(tt.wantArms, got); diff != "" {
+				t.Errorf("Unexpected arms (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(weights, b.Weights()); diff != "" {
+				t.Errorf("Bandit didn't start with the given weights (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	weights := map[string]int{"ImageLocality": 2, "TaintToleration": 1}
+	b := New(weights, &config.ScoreWeightTuning{Plugins: []string{"ImageLocality"}, MaxWeight: 4}, rand.New(rand.NewSource(1)))
+
+	// Every arm is tried once, in order.
+	objectives := map[string]float64{
+		"ImageLocality=2,TaintToleration=1": -0.3,
+		"ImageLocality=4,TaintToleration=1": -0.1,
+		"ImageLocality=1,TaintToleration=1": -0.2,
+	}
+	var tried []string
+	for range objectives {
+		tried = append(tried, b.Label())
+		b.Record(objectives[b.Label()])
+	}
+	wantTried := []string{"ImageLocality=2,TaintToleration=1", "ImageLocality=4,TaintToleration=1", "ImageLocality=1,TaintToleration=1"}
+	if diff := cmp.Diff(wantTried, tried); diff != "" {
+		t.Errorf("Unexpected tried arms (-want,+got):\n%s", diff)
+	}
+
+	// Without exploration, the best arm is exploited.
+	for i := 0; i < 3; i++ {
+		if got, want := b.Label(), "ImageLocality=4,TaintToleration=1"; got != want {
+			t.Fatalf("Got arm %q, want %q", got, want)
+		}
+		b.Record(-0.1)
+	}
+	if diff := cmp.Diff(map[string]int{"ImageLocality": 4, "TaintToleration": 1}, b.Weights()); diff != "" {
+		t.Errorf("Unexpected weights (-want,+got):\n%s", diff)
+	}
+	for _, s := range b.Stats() {
+		if s.Label == "ImageLocality=4,TaintToleration=1" && s.Pulls != 4 {
+			t.Errorf("Got %d pulls of the best arm, want 4", s.Pulls)
+		}
+	}
+}
@@ -93,7 +93,7 @@ var (
 		&metrics.GaugeOpts{
 			Subsystem:      SchedulerSubsystem,
 			Name:           "pending_pods",
-			Help:           "Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ.",
+			Help:           "Number of pending pods, by the queue type. 'active' means number of pods in activeQ; 'backoff' means number of pods in backoffQ; 'unschedulable' means number of pods in unschedulableQ; 'gated' means number of pods held out of activeQ by PreEnqueue plugins.",
 			StabilityLevel: metrics.STABLE,
 		}, []string{"queue"})
 	SchedulerGoroutines = metrics.NewGaugeVec(
@@ -173,6 +173,63 @@ var (
 			StabilityLevel: metrics.ALPHA,
 		}, []string{"type"})
 
+	HostFallbacks = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "host_fallbacks_total",
+			Help:           "Number of pods that were tried on the next-best ranked nodes after the suggested node rejected them, by the extension point that rejected the suggested node and the result. 'bound' means the fallback saved the pod from being requeued, 'requeued' means it did not.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"extension_point", "result"})
+
+	PodBackoffDuration = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "pod_backoff_duration_seconds",
+			Help:           "Backoff of unschedulable pods after a failed scheduling attempt, in seconds.",
+			Buckets:        metrics.ExponentialBuckets(0.1, 2, 12),
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	PodSchedulingDeadlineMissed = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "pod_scheduling_deadline_missed_total",
+			Help:           "Number of pods that were still pending at their scheduling deadline, by SLO class. The SLO class is empty for pods that declared their deadline explicitly.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"slo_class"})
+
+	ScorePluginWeight = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "score_plugin_weight",
+			Help:           "Current weight of the Score plugins whose weights are tuned online, by profile and plugin.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"profile", "plugin"})
+
+	ScoreWeightTuningObjective = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "score_weight_tuning_objective",
+			Help:           "Last balance of the node utilization evaluated by the Score weight tuning, by profile. The balance is the opposite of the standard deviation of the utilization of the nodes, so higher is better.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"profile"})
+
+	ScoreWeightTuningArmObjective = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "score_weight_tuning_arm_objective",
+			Help:           "Mean balance of the node utilization evaluated with each candidate weight vector of the Score weight tuning, by profile and weights.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"profile", "weights"})
+
+	ScoreWeightTuningArmEvaluations = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      SchedulerSubsystem,
+			Name:           "score_weight_tuning_arm_evaluations_total",
+			Help:           "Number of evaluations of each candidate weight vector of the Score weight tuning, by profile and weights.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"profile", "weights"})
+
 	metricsList = []metrics.Registerable{
 		scheduleAttempts,
 		e2eSchedulingLatency,
@@ -189,6 +246,13 @@ var (
 		SchedulerGoroutines,
 		PermitWaitDuration,
 		CacheSize,
+		HostFallbacks,
+		PodSchedulingDeadlineMissed,
+		PodBackoffDuration,
+		ScorePluginWeight,
+		ScoreWeightTuningObjective,
+		ScoreWeightTuningArmObjective,
+		ScoreWeightTuningArmEvaluations,
 	}
 )
 
@@ -231,6 +295,11 @@ func UnschedulablePods() metrics.GaugeMetric {
 	return pendingPods.With(metrics.Labels{"queue": "unschedulable"})
 }
 
+// GatedPods returns the pending pods metrics with the label gated
+func GatedPods() metrics.GaugeMetric {
+	return pendingPods.With(metrics.Labels{"queue": "gated"})
+}
+
 // SinceInSeconds gets the time since the specified start in seconds.
 func SinceInSeconds(start time.Time) float64 {
 	return time.Since(start).Seconds()
@@ -0,0 +1,281 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	v1 "k8s.io/api/core/v1"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+)
+
+// PreemptionReport is the outcome of the simulation of the preemption of a
+// pending pod.
+type PreemptionReport struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Profile   string `json:"profile"`
+	// FeasibleNodes are the nodes the pod fits on without preempting any pod.
+	// No preemption is simulated if there are some.
+	FeasibleNodes []string `json:"feasibleNodes,omitempty"`
+	// Plugins are the simulations of the PostFilter plugins of the profile, in
+	// the order they run.
+	Plugins []PluginPreemptionReport `json:"plugins,omitempty"`
+}
+
+// PluginPreemptionReport is the preemption a PostFilter plugin would perform.
+type PluginPreemptionReport struct {
+	Plugin string `json:"plugin"`
+	// Message explains why the plugin would not preempt, if it wouldn't.
+	Message    string                      `json:"message,omitempty"`
+	Candidates []PreemptionCandidateReport `json:"candidates,omitempty"`
+	// SelectedNode is the candidate the pod would be nominated to.
+	SelectedNode string `json:"selectedNode,omitempty"`
+}
+
+// PreemptionCandidateReport describes a node where preempting the victims
+// makes room for the pod.
+type PreemptionCandidateReport struct {
+	Node string `json:"node"`
+	// Victims are the namespaced names of the pods to preempt.
+	Victims               []string `json:"victims"`
+	NumPDBViolations      int64    `json:"numPDBViolations"`
+	HighestVictimPriority int32    `json:"highestVictimPriority"`
+}
+
+// preemptionSimulationHandler serves the preemption report of the pending pod
+// given by the namespace and name query parameters. output=json selects JSON
+// over a table in plain text.
+type preemptionSimulationHandler struct {
+	sched *Scheduler
+}
+
+// ServeHTTP implements http.Handler.
+func (h *preemptionSimulationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	output := query.Get("output")
+	if output != "" && output != "json" && output != "text" {
+		http.Error(w, fmt.Sprintf("unsupported output %q, must be json or text", output), http.StatusBadRequest)
+		return
+	}
+	namespace, name := query.Get("namespace"), query.Get("name")
+	if name == "" {
+		http.Error(w, "the name of the pod is required", http.StatusBadRequest)
+		return
+	}
+	if namespace == "" {
+		namespace = v1.NamespaceDefault
+	}
+	var pod *v1.Pod
+	for _, pInfo := range h.sched.SchedulingQueue.PendingPodInfos() {
+		if pInfo.Pod.Namespace == namespace && pInfo.Pod.Name == name {
+			pod = pInfo.Pod
+			break
+		}
+	}
+	if pod == nil {
+		http.Error(w, fmt.Sprintf("pod %s/%s is not pending", namespace, name), http.StatusNotFound)
+		return
+	}
+	report, err := h.sched.simulatePreemption(r.Context(), pod)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if output == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			klog.ErrorS(err, "Failed to write preemption report")
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if len(report.FeasibleNodes) != 0 {
+		fmt.Fprintf(w, "Pod %s/%s fits on %d node(s) without preemption: %s\n", report.Namespace, report.Name,
+			len(report.FeasibleNodes), strings.Join(report.FeasibleNodes, ","))
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLUGIN\tNODE\tVICTIMS\tPDB VIOLATIONS\tHIGHEST VICTIM PRIORITY\tSELECTED")
+	for _, p := range report.Plugins {
+		if len(p.Candidates) == 0 {
+			fmt.Fprintf(tw, "%s\t<none>\t\t\t\t%s\n", p.Plugin, p.Message)
+			continue
+		}
+		for _, c := range p.Candidates {
+			selected := ""
+			if c.Node == p.SelectedNode {
+				selected = "*"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\n", p.Plugin, c.Node, strings.Join(c.Victims, ","),
+				c.NumPDBViolations, c.HighestVictimPriority, selected)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		klog.ErrorS(err, "Failed to write preemption report")
+	}
+}
+
+// simulationProfile is a framework built for preemption simulations, along
+// with the configuration it was built from.
+type simulationProfile struct {
+	cfg schedulerapi.KubeSchedulerProfile
+	fwk framework.Framework
+}
+
+// simulatePreemption runs the PreFilter and Filter plugins of the profile of
+// the pod against a fresh snapshot of the cluster and, if the pod fits on no
+// node, simulates the preemption of its PostFilter plugins. Nothing is
+// evicted or nominated.
+//
+// The simulation runs in a dry run cycle, with frameworks and a snapshot of
+// its own, so that it neither waits for nor disturbs the scheduling cycles.
+func (sched *Scheduler) simulatePreemption(ctx context.Context, pod *v1.Pod) (*PreemptionReport, error) {
+	if sched.newSimulationProfile == nil {
+		return nil, errors.New("preemption can't be simulated by this scheduler")
+	}
+	profileFwk, err := sched.frameworkForPod(pod)
+	if err != nil {
+		return nil, err
+	}
+	name := profileFwk.ProfileName()
+	report := &PreemptionReport{Namespace: pod.Namespace, Name: pod.Name, Profile: name}
+
+	sched.simulationLock.Lock()
+	defer sched.simulationLock.Unlock()
+	fwk, err := sched.simulationFramework(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := sched.SchedulerCache.UpdateSnapshot(sched.simulationSnapshot); err != nil {
+		return nil, err
+	}
+	nodes, err := sched.simulationSnapshot.NodeInfos().List()
+	if err != nil {
+		return nil, err
+	}
+
+	state := framework.NewCycleState()
+	state.SetDryRun(true)
+	state.Write(framework.PodsToActivateKey, framework.NewPodsToActivate())
+	m := make(framework.NodeToStatusMap)
+	if s := fwk.RunPreFilterPlugins(ctx, state, pod); !s.IsSuccess() {
+		if !s.IsUnschedulable() {
+			return nil, s.AsError()
+		}
+		for _, n := range nodes {
+			m[n.Node().Name] = s
+		}
+	} else {
+		var lock sync.Mutex
+		fwk.Parallelizer().Until(ctx, len(nodes), func(i int) {
+			name := nodes[i].Node().Name
+			s := fwk.RunFilterPluginsWithNominatedPods(ctx, state, pod, nodes[i])
+			lock.Lock()
+			defer lock.Unlock()
+			if s.IsSuccess() {
+				report.FeasibleNodes = append(report.FeasibleNodes, name)
+			} else {
+				m[name] = s
+			}
+		})
+	}
+	if len(report.FeasibleNodes) != 0 {
+		sort.Strings(report.FeasibleNodes)
+		return report, nil
+	}
+
+	for _, pl := range fwk.PostFilterPlugins() {
+		p := PluginPreemptionReport{Plugin: pl.Name()}
+		simulator, ok := pl.(preemption.Simulator)
+		if !ok {
+			p.Message = "preemption simulation not supported"
+			report.Plugins = append(report.Plugins, p)
+			continue
+		}
+		sim, status := simulator.SimulatePreemption(ctx, state, pod, m)
+		switch {
+		case status.Code() == framework.Error:
+			return nil, status.AsError()
+		case !status.IsSuccess():
+			p.Message = status.Message()
+		case len(sim.Candidates) == 0:
+			p.Message = "preemption will not help schedule the pod on any node"
+		default:
+			for _, c := range sim.Candidates {
+				p.Candidates = append(p.Candidates, preemptionCandidateReport(c))
+			}
+			sort.Slice(p.Candidates, func(i, j int) bool {
+				return p.Candidates[i].Node < p.Candidates[j].Node
+			})
+			if sim.Selected != nil {
+				p.SelectedNode = sim.Selected.Name()
+			}
+		}
+		report.Plugins = append(report.Plugins, p)
+	}
+	return report, nil
+}
+
+// simulationFramework returns the framework of the given profile for
+// preemption simulations, building it again if the configuration of the
+// profile was reloaded since it was built. simulationLock must be held.
+func (sched *Scheduler) simulationFramework(name string) (framework.Framework, error) {
+	cfg := sched.profileConfig(name)
+	if p, ok := sched.simulationProfiles[name]; ok && reflect.DeepEqual(p.cfg, cfg) {
+		return p.fwk, nil
+	}
+	fwk, err := sched.newSimulationProfile(cfg, sched.simulationSnapshot)
+	if err != nil {
+		return nil, fmt.Errorf("initializing profile %q for preemption simulations: %w", name, err)
+	}
+	if sched.simulationProfiles == nil {
+		sched.simulationProfiles = make(map[string]simulationProfile)
+	}
+	sched.simulationProfiles[name] = simulationProfile{cfg: cfg, fwk: fwk}
+	return fwk, nil
+}
+
+func preemptionCandidateReport(c preemption.Candidate) PreemptionCandidateReport {
+	victims := c.Victims()
+	r := PreemptionCandidateReport{
+		Node:             c.Name(),
+		Victims:          make([]string, 0, len(victims.Pods)),
+		NumPDBViolations: victims.NumPDBViolations,
+	}
+	for i, v := range victims.Pods {
+		r.Victims = append(r.Victims, v.Namespace+"/"+v.Name)
+		if p := corev1helpers.PodPriority(v); i == 0 || p > r.HighestVictimPriority {
+			r.HighestVictimPriority = p
+		}
+	}
+	return r
+}
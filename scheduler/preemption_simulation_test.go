@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/noderesources"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	internalqueue "k8s.io/kubernetes/pkg/scheduler/internal/queue"
+	"k8s.io/kubernetes/pkg/scheduler/profile"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func TestPreemptionSimulationHandler(t *testing.T) {
+	capacity := map[v1.ResourceName]string{v1.ResourceCPU: "1000m", v1.ResourceMemory: "1000"}
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node1").Capacity(capacity).Obj(),
+		st.MakeNode().Name("node2").Capacity(capacity).Obj(),
+	}
+	pods := []*v1.Pod{
+		st.MakePod().Name("low1").UID("low1").Node("node1").Priority(1).Req(map[v1.ResourceName]string{v1.ResourceCPU: "800m"}).Obj(),
+		st.MakePod().Name("low2").UID("low2").Node("node2").Priority(5).Req(map[v1.ResourceName]string{v1.ResourceCPU: "600m"}).Obj(),
+		st.MakePod().Name("high").UID("high").Node("node2").Priority(100).Req(map[v1.ResourceName]string{v1.ResourceCPU: "300m"}).Obj(),
+	}
+	tests := []struct {
+		name       string
+		pending    *v1.Pod
+		query      string
+		wantStatus int
+		want       *PreemptionReport
+		wantText   *regexp.Regexp
+	}{
+		{
+			name:       "preemption candidates",
+			pending:    st.MakePod().Name("p").UID("p").SchedulerName(testSchedulerName).Priority(10).Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj(),
+			query:      "name=p&output=json",
+			wantStatus: http.StatusOK,
+			want: &PreemptionReport{
+				Namespace: "default",
+				Name:      "p",
+				Profile:   testSchedulerName,
+				Plugins: []PluginPreemptionReport{
+					{
+						Plugin: defaultpreemption.Name,
+						Candidates: []PreemptionCandidateReport{
+							{Node: "node1", Victims: []string{"default/low1"}, HighestVictimPriority: 1},
+							{Node: "node2", Victims: []string{"default/low2"}, HighestVictimPriority: 5},
+						},
+						SelectedNode: "node1",
+					},
+				},
+			},
+		},
+		{
+			name:       "text output",
+			pending:    st.MakePod().Name("p").UID("p").SchedulerName(testSchedulerName).Priority(10).Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj(),
+			query:      "name=p",
+			wantStatus: http.StatusOK,
+			wantText:   regexp.MustCompile(`DefaultPreemption\s+node1\s+default/low1\s+0\s+1\s+\*\n`),
+		},
+		{
+			name:       "pod fits without preemption",
+			pending:    st.MakePod().Name("p").UID("p").SchedulerName(testSchedulerName).Priority(10).Req(map[v1.ResourceName]string{v1.ResourceCPU: "100m"}).Obj(),
+			query:      "namespace=default&name=p&output=json",
+			wantStatus: http.StatusOK,
+			want: &PreemptionReport{
+				Namespace:     "default",
+				Name:          "p",
+				Profile:       testSchedulerName,
+				FeasibleNodes: []string{"node1", "node2"},
+			},
+		},
+		{
+			name:       "pod is not eligible to preempt",
+			pending:    st.MakePod().Name("p").UID("p").SchedulerName(testSchedulerName).Priority(10).PreemptionPolicy(v1.PreemptNever).Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj(),
+			query:      "name=p&output=json",
+			wantStatus: http.StatusOK,
+			want: &PreemptionReport{
+				Namespace: "default",
+				Name:      "p",
+				Profile:   testSchedulerName,
+				Plugins: []PluginPreemptionReport{
+					{Plugin: defaultpreemption.Name, Message: "pod is not eligible to preempt other pods"},
+				},
+			},
+		},
+		{
+			name:       "pod is not pending",
+			pending:    st.MakePod().Name("p").UID("p").SchedulerName(testSchedulerName).Obj(),
+			query:      "name=other",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "pod name is missing",
+			pending:    st.MakePod().Name("p").UID("p").SchedulerName(testSchedulerName).Obj(),
+			query:      "namespace=default",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			cache := internalcache.New(10*time.Minute, ctx.Done())
+			for _, n := range nodes {
+				cache.AddNode(n)
+			}
+			for _, p := range pods {
+				cache.AddPod(p)
+			}
+			client := clientsetfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(client, 0)
+			newFramework := func(snapshot *internalcache.Snapshot) (framework.Framework, error) {
+				return st.NewFramework(
+					[]st.RegisterPluginFunc{
+						st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+						st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+						st.RegisterPluginAsExtensions(noderesources.FitName, frameworkruntime.FactoryAdapter(feature.Features{}, noderesources.NewFit), "Filter", "PreFilter"),
+						st.RegisterPluginAsExtensions(defaultpreemption.Name, func(_ runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+							return defaultpreemption.New(&schedulerapi.DefaultPreemptionArgs{MinCandidateNodesPercentage: 10, MinCandidateNodesAbsolute: 1}, fh, feature.Features{})
+						}, "PostFilter"),
+					},
+					testSchedulerName,
+					frameworkruntime.WithClientSet(client),
+					frameworkruntime.WithEventRecorder(&events.FakeRecorder{}),
+					frameworkruntime.WithInformerFactory(informerFactory),
+					frameworkruntime.WithSnapshotSharedLister(snapshot),
+					frameworkruntime.WithPodNominator(internalqueue.NewPodNominator(informerFactory.Core().V1().Pods().Lister())),
+				)
+			}
+			snapshot := internalcache.NewEmptySnapshot()
+			fwk, err := newFramework(snapshot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			queue := internalqueue.NewTestQueue(ctx, nil)
+			if err := queue.Add(tt.pending); err != nil {
+				t.Fatal(err)
+			}
+			sched := &Scheduler{
+				SchedulerCache:     cache,
+				SchedulingQueue:    queue,
+				Profiles:           profile.Map{testSchedulerName: fwk},
+				nodeInfoSnapshot:   snapshot,
+				simulationSnapshot: internalcache.NewEmptySnapshot(),
+				newSimulationProfile: func(_ schedulerapi.KubeSchedulerProfile, snapshot *internalcache.Snapshot) (framework.Framework, error) {
+					return newFramework(snapshot)
+				},
+			}
+
+			w := httptest.NewRecorder()
+			sched.PreemptionSimulationHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/preemption?"+tt.query, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Got status %v, want %v: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if n := snapshot.NumNodes(); n != 0 {
+				t.Errorf("Got %d nodes in the snapshot of the scheduling cycles, want the simulation to leave it untouched", n)
+			}
+			if tt.wantText != nil && !tt.wantText.MatchString(w.Body.String()) {
+				t.Errorf("Got report:\n%s\nwant it to contain %q", w.Body.String(), tt.wantText)
+			}
+			if tt.want == nil {
+				return
+			}
+			var got PreemptionReport
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, &got); diff != "" {
+				t.Errorf("Unexpected report (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+)
+
+// NamespaceLabelsFunc returns the labels of the namespace with the given name.
+type NamespaceLabelsFunc func(namespace string) labels.Set
+
+// Router assigns a profile to the pods that don't ask for one, following the
+// routes of the configuration.
+type Router struct {
+	routes         []route
+	defaultProfile string
+}
+
+type route struct {
+	schedulerName     string
+	namespaceSelector labels.Selector
+	podSelector       labels.Selector
+}
+
+// NewRouter builds a Router for the routes and the default profile of the
+// configuration. It returns nil if there are neither.
+func NewRouter(routes []config.ProfileRoute, defaultProfile string) (*Router, error) {
+	if len(routes) == 0 && len(defaultProfile) == 0 {
+		return nil, nil
+	}
+	r := &Router{defaultProfile: defaultProfile}
+	for i, cfg := range routes {
+		rt := route{schedulerName: cfg.SchedulerName, namespaceSelector: labels.Everything(), podSelector: labels.Everything()}
+		var err error
+		if cfg.NamespaceSelector != nil {
+			if rt.namespaceSelector, err = metav1.LabelSelectorAsSelector(cfg.NamespaceSelector); err != nil {
+				return nil, fmt.Errorf("route %d: invalid namespace selector: %w", i, err)
+			}
+		}
+		if cfg.PodSelector != nil {
+			if rt.podSelector, err = metav1.LabelSelectorAsSelector(cfg.PodSelector); err != nil {
+				return nil, fmt.Errorf("route %d: invalid pod selector: %w", i, err)
+			}
+		}
+		r.routes = append(r.routes, rt)
+	}
+	return r, nil
+}
+
+// ProfileName returns the scheduler name of the profile the pod is scheduled
+// with. Pods that ask for a profile other than the default one get it, the
+// others get the profile of the first route they match or the default profile.
+// The labels of the namespace of the pod are only looked up if a route needs
+// them. A nil Router returns the scheduler name of the pod.
+func (r *Router) ProfileName(pod *v1.Pod, namespaceLabels NamespaceLabelsFunc) string {
+	if r == nil || (len(pod.Spec.SchedulerName) != 0 && pod.Spec.SchedulerName != v1.DefaultSchedulerName) {
+		return pod.Spec.SchedulerName
+	}
+	var nsLabels labels.Set
+	nsLabelsFetched := false
+	for _, rt := range r.routes {
+		if !rt.podSelector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if !rt.namespaceSelector.Empty() {
+			if !nsLabelsFetched {
+				nsLabels, nsLabelsFetched = namespaceLabels(pod.Namespace), true
+			}
+			if !rt.namespaceSelector.Matches(nsLabels) {
+				continue
+			}
+		}
+		return rt.schedulerName
+	}
+	if len(r.defaultProfile) != 0 {
+		return r.defaultProfile
+	}
+	return v1.DefaultSchedulerName
+}
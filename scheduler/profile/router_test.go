@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+)
+
+func TestRouterProfileName(t *testing.T) {
+	routes := []config.ProfileRoute{
+		{
+			SchedulerName:     "drs",
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "drs"}},
+		},
+		{
+			SchedulerName: "batch",
+			PodSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "batch"}},
+		},
+	}
+	namespaces := map[string]labels.Set{
+		"drs-ns":   {"team": "drs"},
+		"other-ns": {"team": "web"},
+	}
+	pod := func(namespace, schedulerName string, podLabels map[string]string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "p", Labels: podLabels},
+			Spec:       v1.PodSpec{SchedulerName: schedulerName},
+		}
+	}
+	tests := []struct {
+		name           string
+		routes         []config.ProfileRoute
+		defaultProfile string
+		pod            *v1.Pod
+		want           string
+		// wantLookups is the number of namespace label lookups.
+		wantLookups int
+	}{
+		{
+			name: "no routes",
+			pod:  pod("drs-ns", v1.DefaultSchedulerName, nil),
+			want: v1.DefaultSchedulerName,
+		},
+		{
+			name:        "namespace route",
+			routes:      routes,
+			pod:         pod("drs-ns", v1.DefaultSchedulerName, nil),
+			want:        "drs",
+			wantLookups: 1,
+		},
+		{
+			name:        "empty scheduler name",
+			routes:      routes,
+			pod:         pod("drs-ns", "", nil),
+			want:        "drs",
+			wantLookups: 1,
+		},
+		{
+			name:        "pod route",
+			routes:      routes,
+			pod:         pod("other-ns", v1.DefaultSchedulerName, map[string]string{"app": "batch"}),
+			want:        "batch",
+			wantLookups: 1,
+		},
+		{
+			name:   "explicit profile",
+			routes: routes,
+			pod:    pod("drs-ns", "batch", nil),
+			want:   "batch",
+		},
+		{
+			name:           "no matching route",
+			routes:         routes,
+			defaultProfile: "web",
+			pod:            pod("other-ns", v1.DefaultSchedulerName, nil),
+			want:           "web",
+			wantLookups:    1,
+		},
+		{
+			name:        "no matching route nor default profile",
+			routes:      routes,
+			pod:         pod("unknown-ns", v1.DefaultSchedulerName, nil),
+			want:        v1.DefaultSchedulerName,
+			wantLookups: 1,
+		},
+		{
+			name:           "pod route doesn't look up namespace",
+			routes:         routes[1:],
+			defaultProfile: "web",
+			pod:            pod("drs-ns", v1.DefaultSchedulerName, nil),
+			want:           "web",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRouter(tt.routes, tt.defaultProfile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lookups := 0
+			got := r.ProfileName(tt.pod, func(namespace string) labels.Set {
+				lookups++
+				return namespaces[namespace]
+			})
+			if got != tt.want {
+				t.Errorf("Got profile %q, want %q", got, tt.want)
+			}
+			if lookups != tt.wantLookups {
+				t.Errorf("Got %d namespace lookups, want %d", lookups, tt.wantLookups)
+			}
+		})
+	}
+}
+
+func TestNewRouterInvalidSelector(t *testing.T) {
+	_, err := NewRouter([]config.ProfileRoute{{
+		SchedulerName: "drs",
+		PodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: "Unknown"},
+		}},
+	}}, "")
+	if err == nil {
+		t.Error("Expected error for invalid selector")
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/scheme"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/profile"
+)
+
+// ReloadProfiles validates the configuration and rebuilds the frameworks of
+// the profiles whose configuration changed, such as their plugins, weights or
+// plugin args. The new frameworks are swapped in between two scheduling
+// cycles, keeping the scheduling queue and the cache. Nothing is swapped in if
+// the configuration is invalid or any framework fails to build.
+//
+// Only the profiles of the configuration are reloaded. Adding or removing
+// profiles, or changing their queue sort or PreEnqueue plugins requires a
+// restart, as do the cluster events the plugins register for. The scheduling
+// queue is sorted and gated by the plugins of the reloaded frameworks, which
+// start with no state of their own. Pods already past their scheduling cycle
+// are bound with the frameworks they were reserved with. The settings made
+// with scheduler options, such as the host selectors and the profile routes,
+// are kept.
+func (sched *Scheduler) ReloadProfiles(cfg *schedulerapi.KubeSchedulerConfiguration) error {
+	if sched.newProfiles == nil {
+		return errors.New("the profiles of this scheduler can't be reloaded")
+	}
+	if err := validation.ValidateKubeSchedulerConfiguration(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	cfgs := make([]schedulerapi.KubeSchedulerProfile, 0, len(cfg.Profiles))
+	for i := range cfg.Profiles {
+		cfgs = append(cfgs, *cfg.Profiles[i].DeepCopy())
+	}
+	if err := setIgnoredExtendedResources(cfgs, sched.ignoredExtendedResources); err != nil {
+		return err
+	}
+
+	names := sets.NewString()
+	var changed []schedulerapi.KubeSchedulerProfile
+	for _, c := range cfgs {
+		names.Insert(c.SchedulerName)
+		old, ok := sched.profileConfigs[c.SchedulerName]
+		if !ok {
+			continue
+		}
+		if reflect.DeepEqual(old, c) {
+			continue
+		}
+		if !reflect.DeepEqual(queueSortConfig(old), queueSortConfig(c)) {
+			return fmt.Errorf("profile %q: changing the queue sort plugins requires a restart", c.SchedulerName)
+		}
+		changed = append(changed, c)
+	}
+	if oldNames := sets.StringKeySet(sched.profileConfigs); !names.Equal(oldNames) {
+		return fmt.Errorf("adding or removing profiles requires a restart: got profiles %v, want %v", names.List(), oldNames.List())
+	}
+	if len(changed) == 0 {
+		klog.V(2).InfoS("Scheduler profiles are unchanged, nothing to reload")
+		return nil
+	}
+
+	profiles, err := sched.newProfiles(changed)
+	if err != nil {
+		return fmt.Errorf("initializing profiles: %w", err)
+	}
+	current := sched.profileMap()
+	for name, fwk := range profiles {
+		if !reflect.DeepEqual(preEnqueuePluginNames(current[name]), preEnqueuePluginNames(fwk)) {
+			return fmt.Errorf("profile %q: changing the PreEnqueue plugins requires a restart", name)
+		}
+	}
+
+	// Wait for the scheduling cycle in progress, if any, to finish.
+	sched.cycleLock.Lock()
+	defer sched.cycleLock.Unlock()
+	sched.profilesLock.Lock()
+	defer sched.profilesLock.Unlock()
+	merged := make(profile.Map, len(sched.Profiles))
+	for name, fwk := range sched.Profiles {
+		merged[name] = fwk
+	}
+	profileConfigs := make(map[string]schedulerapi.KubeSchedulerProfile, len(sched.profileConfigs))
+	for name, c := range sched.profileConfigs {
+		profileConfigs[name] = c
+	}
+	var lessFn framework.LessFunc
+	preEnqueuePluginMap := make(map[string][]framework.PreEnqueuePlugin, len(changed))
+	for _, c := range changed {
+		fwk := profiles[c.SchedulerName]
+		merged[c.SchedulerName] = fwk
+		profileConfigs[c.SchedulerName] = c
+		if c.SchedulerName == sched.queueSortProfile {
+			lessFn = fwk.QueueSortFunc()
+		}
+		preEnqueuePluginMap[c.SchedulerName] = fwk.PreEnqueuePlugins()
+		klog.InfoS("Reloaded scheduler profile", "profile", c.SchedulerName)
+	}
+	// The queue sort and PreEnqueue plugins of the reloaded frameworks are new
+	// instances, which the scheduling queue must use from now on.
+	sched.SchedulingQueue.UpdateProfiles(lessFn, preEnqueuePluginMap)
+	sched.Profiles = merged
+	sched.profileConfigs = profileConfigs
+	return nil
+}
+
+// WatchConfigFile checks the configuration file at the given path every
+// interval until the context is done, and reloads the profiles of the
+// scheduler whenever the file changes. Invalid configurations are logged and
+// ignored, leaving the profiles unchanged.
+func (sched *Scheduler) WatchConfigFile(ctx context.Context, path string, interval time.Duration) {
+	var last []byte
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			klog.ErrorS(err, "Failed to read scheduler configuration", "path", path)
+			return
+		}
+		if bytes.Equal(data, last) {
+			return
+		}
+		last = data
+		cfg, err := decodeConfig(data)
+		if err != nil {
+			klog.ErrorS(err, "Failed to decode scheduler configuration", "path", path)
+			return
+		}
+		if err := sched.ReloadProfiles(cfg); err != nil {
+			klog.ErrorS(err, "Failed to reload scheduler profiles, keeping the current ones", "path", path)
+		}
+	}, interval)
+}
+
+// decodeConfig decodes a KubeSchedulerConfiguration of any supported version
+// into the internal type, with defaults applied.
+func decodeConfig(data []byte) (*schedulerapi.KubeSchedulerConfiguration, error) {
+	obj, gvk, err := scheme.Codecs.UniversalDecoder().Decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	cfg, ok := obj.(*schedulerapi.KubeSchedulerConfiguration)
+	if !ok {
+		return nil, fmt.Errorf("couldn't decode as KubeSchedulerConfiguration, got %s", gvk)
+	}
+	return cfg, nil
+}
+
+// queueSortConfig returns the queue sort plugins enabled in the profile, along
+// with their args.
+func queueSortConfig(cfg schedulerapi.KubeSchedulerProfile) []schedulerapi.PluginConfig {
+	var cfgs []schedulerapi.PluginConfig
+	if cfg.Plugins == nil {
+		return cfgs
+	}
+	for _, p := range cfg.Plugins.QueueSort.Enabled {
+		pc := schedulerapi.PluginConfig{Name: p.Name}
+		for _, c := range cfg.PluginConfig {
+			if c.Name == p.Name {
+				pc.Args = c.Args
+			}
+		}
+		cfgs = append(cfgs, pc)
+	}
+	return cfgs
+}
+
+func preEnqueuePluginNames(fwk framework.Framework) []string {
+	var names []string
+	if fwk == nil {
+		return names
+	}
+	for _, pl := range fwk.PreEnqueuePlugins() {
+		names = append(names, pl.Name())
+	}
+	sort.Strings(names)
+	return names
+}
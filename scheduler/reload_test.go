@@ -0,0 +1,291 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/profile"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+const reloadBaseConfig = `apiVersion: kubescheduler.config.k8s.io/v1beta3
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: a
+  plugins:
+    score:
+      enabled:
+      - name: ImageLocality
+        weight: 2
+- schedulerName: b
+`
+
+const reloadDeadlineSort = `  plugins:
+    queueSort:
+      enabled:
+      - name: DeadlineSort
+      disabled:
+      - name: "*"
+`
+
+const reloadFairShareConfig = `apiVersion: kubescheduler.config.k8s.io/v1beta3
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: a
+  plugins:
+    queueSort:
+      enabled:
+      - name: FairShare
+      disabled:
+      - name: "*"
+    reserve:
+      enabled:
+      - name: FairShare
+      disabled:
+      - name: "*"
+    score:
+      enabled:
+      - name: ImageLocality
+        weight: 2
+- schedulerName: b
+  plugins:
+    queueSort:
+      enabled:
+      - name: FairShare
+      disabled:
+      - name: "*"
+`
+
+func newReloadTestScheduler(t *testing.T, stopCh <-chan struct{}, opts ...Option) *Scheduler {
+	cfg, err := decodeConfig([]byte(reloadBaseConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newReloadTestSchedulerWithProfiles(t, stopCh, cfg.Profiles, opts...)
+}
+
+func newReloadTestSchedulerWithProfiles(t *testing.T, stopCh <-chan struct{}, profiles []schedulerapi.KubeSchedulerProfile, opts ...Option) *Scheduler {
+	client := clientsetfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	eventBroadcaster := events.NewBroadcaster(&events.EventSinkImpl{Interface: client.EventsV1()})
+	sched, err := New(client, informerFactory, nil, profile.NewRecorderFactory(eventBroadcaster), stopCh,
+		append([]Option{WithProfiles(profiles...)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sched
+}
+
+func TestReloadProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+		// wantReloaded are the profiles expected to be rebuilt.
+		wantReloaded []string
+	}{
+		{
+			name:   "unchanged configuration",
+			config: reloadBaseConfig,
+		},
+		{
+			name:         "changed score weight",
+			config:       strings.Replace(reloadBaseConfig, "weight: 2", "weight: 5", 1),
+			wantReloaded: []string{"a"},
+		},
+		{
+			name:    "invalid configuration",
+			config:  reloadBaseConfig + "parallelism: -1\n",
+			wantErr: "invalid configuration",
+		},
+		{
+			name:    "unknown plugin",
+			config:  strings.Replace(reloadBaseConfig, "name: ImageLocality", "name: Unknown", 1),
+			wantErr: "initializing profiles",
+		},
+		{
+			name:    "added profile",
+			config:  reloadBaseConfig + "- schedulerName: c\n",
+			wantErr: "adding or removing profiles requires a restart",
+		},
+		{
+			name:    "changed queue sort plugin",
+			config:  strings.ReplaceAll(reloadBaseConfig+"  plugins:\n", "  plugins:\n", reloadDeadlineSort),
+			wantErr: "queue sort",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			sched := newReloadTestScheduler(t, stopCh)
+			before := sched.profileMap()
+
+			cfg, err := decodeConfig([]byte(tt.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = sched.ReloadProfiles(cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Got error %v, want it to contain %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			after := sched.profileMap()
+			for name, fwk := range before {
+				reloaded := false
+				for _, n := range tt.wantReloaded {
+					reloaded = reloaded || n == name
+				}
+				if got := after[name] != fwk; got != reloaded {
+					t.Errorf("Profile %q reloaded: %v, want %v", name, got, reloaded)
+				}
+			}
+		})
+	}
+}
+
+func TestReloadProfilesKeepsOptions(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	routes := []schedulerapi.ProfileRoute{{
+		SchedulerName: "b",
+		PodSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "batch"}},
+	}}
+	sched := newReloadTestScheduler(t, stopCh,
+		WithHostSelector("a", &schedulerapi.HostSelector{Type: schedulerapi.TopKHostSelector, K: 3}),
+		WithProfileRoutes(routes, "a"))
+	before := sched.profileMap()["a"]
+	router := sched.profileRouter
+
+	cfg, err := decodeConfig([]byte(strings.Replace(reloadBaseConfig, "weight: 2", "weight: 5", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.ReloadProfiles(cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	after := sched.profileMap()["a"]
+	if after == before {
+		t.Fatal("Profile \"a\" wasn't reloaded")
+	}
+	if got, want := after.HostSelector().Name(), string(schedulerapi.TopKHostSelector); got != want {
+		t.Errorf("Got host selector %q after the reload, want %q", got, want)
+	}
+	if sched.profileRouter != router {
+		t.Error("The profile routes changed with the reload")
+	}
+}
+
+func TestReloadProfilesSortsQueueWithReloadedPlugins(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	cfg, err := decodeConfig([]byte(reloadFairShareConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sched := newReloadTestSchedulerWithProfiles(t, stopCh, cfg.Profiles)
+	reserve := func(pod *v1.Pod) {
+		fwk := sched.profileMap()["a"]
+		if s := fwk.RunReservePluginsReserve(context.Background(), framework.NewCycleState(), pod, "node"); !s.IsSuccess() {
+			t.Fatalf("Unexpected status reserving pod %v: %v", pod.Name, s)
+		}
+	}
+
+	// Tenant b is charged on the frameworks built at startup, and tenant a on
+	// the reloaded ones.
+	reserve(st.MakePod().Namespace("b").Name("b0").UID("b0").SchedulerName("a").Obj())
+	cfg, err = decodeConfig([]byte(strings.Replace(reloadFairShareConfig, "weight: 2", "weight: 5", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.ReloadProfiles(cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reserve(st.MakePod().Namespace("a").Name("a0").UID("a0").SchedulerName("a").Obj())
+
+	for _, pod := range []*v1.Pod{
+		st.MakePod().Namespace("a").Name("a1").UID("a1").SchedulerName("a").Obj(),
+		st.MakePod().Namespace("b").Name("b1").UID("b1").SchedulerName("a").Obj(),
+	} {
+		if err := sched.SchedulingQueue.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got []string
+	for i := 0; i < 2; i++ {
+		pInfo, err := sched.SchedulingQueue.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, pInfo.Pod.Name)
+	}
+	if diff := cmp.Diff([]string{"b1", "a1"}, got); diff != "" {
+		t.Errorf("Unexpected order of the pods (-want,+got):\n%s", diff)
+	}
+}
+
+func TestWatchConfigFile(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	sched := newReloadTestScheduler(t, stopCh)
+	before := sched.profileMap()["a"]
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := ioutil.WriteFile(path, []byte(reloadBaseConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sched.WatchConfigFile(ctx, path, 10*time.Millisecond)
+
+	// An invalid configuration is ignored.
+	if err := ioutil.WriteFile(path, []byte("profiles: ["), 0644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if sched.profileMap()["a"] != before {
+		t.Fatal("Profile reloaded from an invalid configuration")
+	}
+
+	if err := ioutil.WriteFile(path, []byte(strings.Replace(reloadBaseConfig, "weight: 2", "weight: 5", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait.Poll(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return sched.profileMap()["a"] != before, nil
+	}); err != nil {
+		t.Errorf("Profile was not reloaded: %v", err)
+	}
+}
@@ -19,12 +19,14 @@ package scheduler
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -41,11 +43,13 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/apis/config"
 	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/apis/config/scheme"
+	schedulervalidation "k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/parallelize"
 	frameworkplugins "k8s.io/kubernetes/pkg/scheduler/framework/plugins"
 	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	cachedebugger "k8s.io/kubernetes/pkg/scheduler/internal/cache/debugger"
 	internalqueue "k8s.io/kubernetes/pkg/scheduler/internal/queue"
 	"k8s.io/kubernetes/pkg/scheduler/metrics"
 	"k8s.io/kubernetes/pkg/scheduler/profile"
@@ -60,6 +64,12 @@ const (
 	// Duration the scheduler will wait before expiring an assumed pod.
 	// See issue #106361 for more details about this parameter and its value.
 	durationToExpireAssumedPod = 15 * time.Minute
+	// Period of the resync of the plugins implementing ResyncPlugin.
+	pluginResyncPeriod = 30 * time.Second
+
+	// DefaultNodeUtilizationThreshold is the default utilization below which a
+	// node reported by the node telemetry is considered to have cooled down.
+	DefaultNodeUtilizationThreshold = 0.8
 )
 
 // Scheduler watches for new unscheduled pods. It attempts to find
@@ -93,6 +103,63 @@ type Scheduler struct {
 	Profiles profile.Map
 
 	client clientset.Interface
+
+	// batchSize is the maximum number of compatible pods placed in a single
+	// scheduling cycle. Batching is disabled if it is lower than 2.
+	batchSize int
+
+	// nodeUtilization detects when the utilization of a node reported by the
+	// node telemetry drops below the threshold.
+	nodeUtilization *nodeUtilizationTracker
+	// networkTopology holds the links between nodes reported by the node
+	// telemetry, shared by the frameworks of the profiles.
+	networkTopology *framework.NetworkTopology
+	// randomSeed is the seed of the random source shared by the profiles. The
+	// weight tuners derive their own sources from it, so that their draws don't
+	// change the sequence the scheduling decisions draw from.
+	randomSeed int64
+
+	// nodeInfoSnapshot is the snapshot of the cluster the profiles run against.
+	nodeInfoSnapshot *internalcache.Snapshot
+	// cycleLock serializes the scheduling cycles, which share nodeInfoSnapshot.
+	cycleLock sync.Mutex
+
+	// simulationLock serializes the preemption simulations, which share
+	// simulationSnapshot and simulationProfiles.
+	simulationLock sync.Mutex
+	// simulationSnapshot is the snapshot of the cluster the preemption
+	// simulations run against.
+	simulationSnapshot *internalcache.Snapshot
+	// simulationProfiles are the frameworks built by newSimulationProfile, by
+	// profile name.
+	simulationProfiles map[string]simulationProfile
+	// newSimulationProfile builds a framework of the given profile running
+	// against the given snapshot. Preemption can't be simulated if it is nil.
+	newSimulationProfile func(schedulerapi.KubeSchedulerProfile, *internalcache.Snapshot) (framework.Framework, error)
+
+	// profilesLock guards the replacement of Profiles by ReloadProfiles. The
+	// maps are replaced, never modified.
+	profilesLock sync.RWMutex
+	// profileConfigs are the configurations Profiles were built from.
+	profileConfigs map[string]schedulerapi.KubeSchedulerProfile
+	// newProfiles builds the frameworks of the given profiles with the options
+	// of the initial ones. Profiles can't be reloaded if it is nil.
+	newProfiles func([]schedulerapi.KubeSchedulerProfile) (profile.Map, error)
+	// ignoredExtendedResources are the extended resources managed by extenders
+	// that the NodeResourcesFit plugin of the profiles ignores.
+	ignoredExtendedResources []string
+	// queueSortProfile is the profile whose queue sort plugin sorts the
+	// scheduling queue.
+	queueSortProfile string
+	// profileRouter assigns a profile to the pods that don't ask for one. It
+	// is nil if there are no profile routes.
+	profileRouter *profile.Router
+	// profileName returns the name of the profile of a pod, following
+	// profileRouter. If nil, it is the scheduler name of the pod.
+	profileName func(*v1.Pod) string
+	// scoreWeightTunings configure the tuning of the weights of the Score
+	// plugins of the profiles, by profile name.
+	scoreWeightTunings map[string]*schedulerapi.ScoreWeightTuning
 }
 
 type schedulerOptions struct {
@@ -101,13 +168,25 @@ type schedulerOptions struct {
 	percentageOfNodesToScore int32
 	podInitialBackoffSeconds int64
 	podMaxBackoffSeconds     int64
+	backoffPolicy            *schedulerapi.BackoffPolicy
 	// Contains out-of-tree plugins to be merged with the in-tree registry.
 	frameworkOutOfTreeRegistry frameworkruntime.Registry
 	profiles                   []schedulerapi.KubeSchedulerProfile
+	profileRoutes              []schedulerapi.ProfileRoute
+	defaultProfile             string
+	hostSelectors              map[string]*schedulerapi.HostSelector
+	scoreWeightTunings         map[string]*schedulerapi.ScoreWeightTuning
 	extenders                  []schedulerapi.Extender
 	frameworkCapturer          FrameworkCapturer
 	parallelism                int32
 	applyDefaultProfile        bool
+	randomSeed                 *int64
+	maxFallbackHosts           int32
+	batchSize                  int32
+	popPolicy                  framework.PopPolicy
+	popWindow                  int32
+	maxPopSkip                 time.Duration
+	nodeUtilizationThreshold   float64
 }
 
 // Option configures a Scheduler
@@ -175,6 +254,115 @@ func WithPodMaxBackoffSeconds(podMaxBackoffSeconds int64) Option {
 	}
 }
 
+// WithBackoffPolicy sets how long unschedulable pods are backed off before
+// they are retried. By default, the backoff doubles on every attempt.
+func WithBackoffPolicy(policy *schedulerapi.BackoffPolicy) Option {
+	return func(o *schedulerOptions) {
+		o.backoffPolicy = policy
+	}
+}
+
+// WithProfileRoutes sets the routes assigning a profile to the pods that don't
+// ask for one, based on the labels of the pods and of their namespaces, and the
+// profile of the pods matching none of them. The first matching route wins, and
+// routes to different profiles must not be able to match the same pod. By
+// default, pods are scheduled with the profile named by their scheduler name.
+func WithProfileRoutes(routes []schedulerapi.ProfileRoute, defaultProfile string) Option {
+	return func(o *schedulerOptions) {
+		o.profileRoutes = routes
+		o.defaultProfile = defaultProfile
+	}
+}
+
+// WithHostSelector sets the strategy picking the node a pod is assigned to
+// among the scored feasible nodes, for the profile with the given scheduler
+// name. By default, the node with the highest score is picked, breaking ties
+// uniformly at random.
+func WithHostSelector(schedulerName string, hs *schedulerapi.HostSelector) Option {
+	return func(o *schedulerOptions) {
+		if o.hostSelectors == nil {
+			o.hostSelectors = make(map[string]*schedulerapi.HostSelector)
+		}
+		o.hostSelectors[schedulerName] = hs
+	}
+}
+
+// WithScoreWeightTuning makes the Scheduler search online for the weights of
+// the Score plugins of the profile with the given scheduler name that best
+// balance the utilization of the nodes, as reported by the node telemetry. By
+// default, the weights configured in the profile are used.
+func WithScoreWeightTuning(schedulerName string, t *schedulerapi.ScoreWeightTuning) Option {
+	return func(o *schedulerOptions) {
+		if o.scoreWeightTunings == nil {
+			o.scoreWeightTunings = make(map[string]*schedulerapi.ScoreWeightTuning)
+		}
+		o.scoreWeightTunings[schedulerName] = t
+	}
+}
+
+// WithRandomSeed seeds every source of randomness used by the Scheduler and
+// makes the collection of feasible nodes order-preserving, so that identical
+// inputs result in identical scheduling decisions. By default, the Scheduler
+// is seeded from the current time.
+func WithRandomSeed(seed int64) Option {
+	return func(o *schedulerOptions) {
+		o.randomSeed = &seed
+	}
+}
+
+// WithMaxFallbackHosts sets the maximum number of next-best ranked nodes that a
+// pod is tried on when the suggested node rejects it at Reserve, Permit or Bind,
+// before the pod is requeued. Plugins can disable the fallback of a pod with
+// framework.DisableHostFallback. By default, fallback is disabled.
+func WithMaxFallbackHosts(n int32) Option {
+	return func(o *schedulerOptions) {
+		o.maxFallbackHosts = n
+	}
+}
+
+// WithBatchSize sets the maximum number of compatible pods that the Scheduler
+// places against a single snapshot of the cluster. By default, pods are
+// scheduled one at a time.
+func WithBatchSize(n int32) Option {
+	return func(o *schedulerOptions) {
+		o.batchSize = n
+	}
+}
+
+// WithPopPolicy sets the policy picking which of the pods at the head of the
+// scheduling queue is scheduled next. By default, pods are scheduled in the
+// order of the QueueSort plugin.
+func WithPopPolicy(policy framework.PopPolicy) Option {
+	return func(o *schedulerOptions) {
+		o.popPolicy = policy
+	}
+}
+
+// WithPopWindow sets the number of pods at the head of the scheduling queue
+// that the PopPolicy picks from.
+func WithPopWindow(n int32) Option {
+	return func(o *schedulerOptions) {
+		o.popWindow = n
+	}
+}
+
+// WithMaxPopSkip sets the maximum time a pod can be skipped by the PopPolicy
+// before it's scheduled regardless of the policy.
+func WithMaxPopSkip(d time.Duration) Option {
+	return func(o *schedulerOptions) {
+		o.maxPopSkip = d
+	}
+}
+
+// WithNodeUtilizationThreshold sets the utilization, as a fraction of the
+// capacity of a node, below which a node reported by ReportNodeUtilization is
+// considered to have cooled down.
+func WithNodeUtilizationThreshold(threshold float64) Option {
+	return func(o *schedulerOptions) {
+		o.nodeUtilizationThreshold = threshold
+	}
+}
+
 // WithExtenders sets extenders for the Scheduler
 func WithExtenders(e ...schedulerapi.Extender) Option {
 	return func(o *schedulerOptions) {
@@ -196,6 +384,9 @@ var defaultSchedulerOptions = schedulerOptions{
 	percentageOfNodesToScore: schedulerapi.DefaultPercentageOfNodesToScore,
 	podInitialBackoffSeconds: int64(internalqueue.DefaultPodInitialBackoffDuration.Seconds()),
 	podMaxBackoffSeconds:     int64(internalqueue.DefaultPodMaxBackoffDuration.Seconds()),
+	popWindow:                internalqueue.DefaultPopWindow,
+	maxPopSkip:               internalqueue.DefaultMaxPopSkip,
+	nodeUtilizationThreshold: DefaultNodeUtilizationThreshold,
 	parallelism:              int32(parallelize.DefaultParallelism),
 	// Ideally we would statically set the default profile here, but we can't because
 	// creating the default profile may require testing feature gates, which may get
@@ -231,6 +422,9 @@ func New(client clientset.Interface,
 		}
 		options.profiles = cfg.Profiles
 	}
+	if err := validateOptions(&options); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
 	schedulerCache := internalcache.New(durationToExpireAssumedPod, stopEverything)
 
 	registry := frameworkplugins.NewInTreeRegistry()
@@ -252,13 +446,24 @@ func New(client clientset.Interface,
 		percentageOfNodesToScore: options.percentageOfNodesToScore,
 		podInitialBackoffSeconds: options.podInitialBackoffSeconds,
 		podMaxBackoffSeconds:     options.podMaxBackoffSeconds,
+		backoffPolicy:            options.backoffPolicy,
 		profiles:                 append([]schedulerapi.KubeSchedulerProfile(nil), options.profiles...),
+		profileRoutes:            options.profileRoutes,
+		defaultProfile:           options.defaultProfile,
+		hostSelectors:            options.hostSelectors,
+		scoreWeightTunings:       options.scoreWeightTunings,
 		registry:                 registry,
 		nodeInfoSnapshot:         snapshot,
 		extenders:                options.extenders,
 		frameworkCapturer:        options.frameworkCapturer,
 		parallellism:             options.parallelism,
 		clusterEventMap:          clusterEventMap,
+		randomSeed:               options.randomSeed,
+		maxFallbackHosts:         options.maxFallbackHosts,
+		batchSize:                options.batchSize,
+		popPolicy:                options.popPolicy,
+		popWindow:                options.popWindow,
+		maxPopSkip:               options.maxPopSkip,
 	}
 
 	metrics.Register()
@@ -272,12 +477,56 @@ func New(client clientset.Interface,
 	// Additional tweaks to the config produced by the configurator.
 	sched.StopEverything = stopEverything
 	sched.client = client
+	sched.nodeUtilization = newNodeUtilizationTracker(options.nodeUtilizationThreshold)
 
 	addAllEventHandlers(sched, informerFactory, dynInformerFactory, unionedGVKs(clusterEventMap))
 
 	return sched, nil
 }
 
+// validateOptions validates the options that the versioned configuration can't
+// carry, and that are therefore not validated with it.
+func validateOptions(o *schedulerOptions) error {
+	var errs []error
+	if o.backoffPolicy != nil {
+		if err := schedulervalidation.ValidateBackoffPolicy(o.backoffPolicy); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := schedulervalidation.ValidateProfileRoutes(o.profileRoutes, o.defaultProfile, o.profiles); err != nil {
+		errs = append(errs, err)
+	}
+	if o.maxFallbackHosts < 0 {
+		errs = append(errs, fmt.Errorf("max fallback hosts must be greater than or equal to 0, got %d", o.maxFallbackHosts))
+	}
+	if o.batchSize < 0 {
+		errs = append(errs, fmt.Errorf("batch size must be greater than or equal to 0, got %d", o.batchSize))
+	}
+	profileNames := sets.NewString()
+	for _, p := range o.profiles {
+		profileNames.Insert(p.SchedulerName)
+	}
+	for name, hs := range o.hostSelectors {
+		if !profileNames.Has(name) {
+			errs = append(errs, fmt.Errorf("host selector of unknown profile %q", name))
+		} else if hs != nil {
+			if err := schedulervalidation.ValidateHostSelector(hs); err != nil {
+				errs = append(errs, fmt.Errorf("profile %q: %w", name, err))
+			}
+		}
+	}
+	for name, t := range o.scoreWeightTunings {
+		if !profileNames.Has(name) {
+			errs = append(errs, fmt.Errorf("score weight tuning of unknown profile %q", name))
+		} else if t != nil {
+			if err := schedulervalidation.ValidateScoreWeightTuning(t); err != nil {
+				errs = append(errs, fmt.Errorf("profile %q: %w", name, err))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
 func unionedGVKs(m map[framework.ClusterEvent]sets.String) map[framework.GVK]framework.ActionType {
 	gvkMap := make(map[framework.GVK]framework.ActionType)
 	for evt := range m {
@@ -293,10 +542,38 @@ func unionedGVKs(m map[framework.ClusterEvent]sets.String) map[framework.GVK]fra
 // Run begins watching and scheduling. It starts scheduling and blocked until the context is done.
 func (sched *Scheduler) Run(ctx context.Context) {
 	sched.SchedulingQueue.Run()
+	sched.startScoreWeightTuning(ctx)
+	go wait.UntilWithContext(ctx, sched.resyncPlugins, pluginResyncPeriod)
 	wait.UntilWithContext(ctx, sched.scheduleOne, 0)
 	sched.SchedulingQueue.Close()
 }
 
+// resyncPlugins resyncs the plugins of every profile implementing
+// ResyncPlugin, so that they see the changes of their external state even
+// when no pod is scheduled.
+func (sched *Scheduler) resyncPlugins(ctx context.Context) {
+	for _, fwk := range sched.profileMap() {
+		for _, pl := range fwk.ResyncPlugins() {
+			pl.Resync(ctx)
+		}
+	}
+}
+
+// PendingPodsHandler returns an HTTP handler listing the pods pending in the
+// scheduling queue, with the sub-queue they are in and why they failed their
+// last scheduling attempt. It's meant to be installed on a debugging endpoint.
+func (sched *Scheduler) PendingPodsHandler() http.Handler {
+	return cachedebugger.NewPendingPodsHandler(sched.SchedulingQueue, sched.profileName)
+}
+
+// PreemptionSimulationHandler returns an HTTP handler reporting what the
+// PostFilter plugins of the profile of a pending pod would preempt to make
+// room for it, without evicting anything. It's meant to be installed on a
+// debugging endpoint.
+func (sched *Scheduler) PreemptionSimulationHandler() http.Handler {
+	return &preemptionSimulationHandler{sched: sched}
+}
+
 // recordSchedulingFailure records an event for the pod that indicates the
 // pod has failed to schedule. Also, update the pod condition and nominated node name if set.
 func (sched *Scheduler) recordSchedulingFailure(fwk framework.Framework, podInfo *framework.QueuedPodInfo, err error, reason string, nominatingInfo *framework.NominatingInfo) {
@@ -405,6 +682,66 @@ func (sched *Scheduler) extendersBinding(pod *v1.Pod, node string) (bool, error)
 	return false, nil
 }
 
+// extendersReserve tells the interested extenders that the pod is reserved on
+// the node. The first failing extender that is not ignorable rejects the pod.
+func (sched *Scheduler) extendersReserve(pod *v1.Pod, node string) *framework.Status {
+	for _, extender := range sched.Extenders {
+		e, ok := extender.(framework.ReserveExtender)
+		if !ok || !extender.IsInterested(pod) {
+			continue
+		}
+		if err := e.Reserve(pod, node); err != nil {
+			if extender.IsIgnorable() {
+				klog.InfoS("Skipping extender as it returned error and has ignorable flag set", "extender", extender.Name(), "err", err)
+				continue
+			}
+			return framework.AsStatus(fmt.Errorf("running Reserve on extender %q: %w", extender.Name(), err))
+		}
+	}
+	return nil
+}
+
+// unreserve runs the Unreserve method of reserve plugins, then tells the
+// interested extenders, in reverse order, that the pod is no longer reserved
+// on the node.
+func (sched *Scheduler) unreserve(ctx context.Context, fwk framework.Framework, state *framework.CycleState, pod *v1.Pod, node string) {
+	fwk.RunReservePluginsUnreserve(ctx, state, pod, node)
+	for i := len(sched.Extenders) - 1; i >= 0; i-- {
+		extender := sched.Extenders[i]
+		e, ok := extender.(framework.ReserveExtender)
+		if !ok || !extender.IsInterested(pod) {
+			continue
+		}
+		if err := e.Unreserve(pod, node); err != nil {
+			logExtenderError(extender, "Unreserve", pod, err)
+		}
+	}
+}
+
+// extendersPostBind tells the interested extenders that the pod is bound to
+// the node.
+func (sched *Scheduler) extendersPostBind(pod *v1.Pod, node string) {
+	for _, extender := range sched.Extenders {
+		e, ok := extender.(framework.ReserveExtender)
+		if !ok || !extender.IsInterested(pod) {
+			continue
+		}
+		if err := e.PostBind(pod, node); err != nil {
+			logExtenderError(extender, "PostBind", pod, err)
+		}
+	}
+}
+
+// logExtenderError logs the error of an extender call that can't fail the
+// scheduling of the pod, as an error unless the extender is ignorable.
+func logExtenderError(extender framework.Extender, call string, pod *v1.Pod, err error) {
+	if extender.IsIgnorable() {
+		klog.V(4).InfoS("Ignorable extender call failed", "extender", extender.Name(), "call", call, "pod", klog.KObj(pod), "err", err)
+		return
+	}
+	klog.ErrorS(err, "Extender call failed", "extender", extender.Name(), "call", call, "pod", klog.KObj(pod))
+}
+
 func (sched *Scheduler) finishBinding(fwk framework.Framework, assumed *v1.Pod, targetNode string, err error) {
 	if finErr := sched.SchedulerCache.FinishBinding(assumed); finErr != nil {
 		klog.ErrorS(finErr, "Scheduler cache FinishBinding failed")
@@ -428,6 +765,8 @@ func (sched *Scheduler) scheduleOne(ctx context.Context) {
 	if podInfo == nil || podInfo.Pod == nil {
 		return
 	}
+	sched.cycleLock.Lock()
+	defer sched.cycleLock.Unlock()
 	pod := podInfo.Pod
 	fwk, err := sched.frameworkForPod(pod)
 	if err != nil {
@@ -439,7 +778,19 @@ func (sched *Scheduler) scheduleOne(ctx context.Context) {
 	if sched.skipPodSchedule(fwk, pod) {
 		return
 	}
+	if algo, ok := sched.Algorithm.(BatchScheduleAlgorithm); ok && sched.batchSize > 1 {
+		if batch := sched.nextBatch(fwk, podInfo); len(batch) > 1 {
+			sched.scheduleBatch(ctx, fwk, algo, batch)
+			return
+		}
+	}
+	sched.schedulePod(ctx, fwk, podInfo)
+}
 
+// schedulePod runs the scheduling cycle of a single pod, and starts its binding
+// cycle if it is placed on a node.
+func (sched *Scheduler) schedulePod(ctx context.Context, fwk framework.Framework, podInfo *framework.QueuedPodInfo) {
+	pod := podInfo.Pod
 	klog.V(3).InfoS("Attempting to schedule pod", "pod", klog.KObj(pod))
 
 	// Synchronously attempt to find a fit for the pod.
@@ -447,7 +798,7 @@ func (sched *Scheduler) scheduleOne(ctx context.Context) {
 	my_start := time.Now().UnixNano()
 	fmt.Printf("[INFO] Start scheduling...\n\n")
 	state := framework.NewCycleState()
-	state.SetRecordPluginMetrics(rand.Intn(100) < pluginMetricsSamplePercent)
+	state.SetRecordPluginMetrics(fwk.Rand().Intn(100) < pluginMetricsSamplePercent)
 	// Initialize an empty podsToActivate struct, which will be filled up by plugins or stay empty.
 	podsToActivate := framework.NewPodsToActivate()
 	state.Write(framework.PodsToActivateKey, podsToActivate)
@@ -456,53 +807,67 @@ func (sched *Scheduler) scheduleOne(ctx context.Context) {
 	defer cancel()
 	scheduleResult, err := sched.Algorithm.Schedule(schedulingCycleCtx, sched.Extenders, fwk, state, pod)
 	if err != nil {
-		// Schedule() may have failed because the pod would not fit on any host, so we try to
-		// preempt, with the expectation that the next time the pod is tried for scheduling it
-		// will fit due to the preemption. It is also possible that a different pod will schedule
-		// into the resources that were preempted, but this is harmless.
-		var nominatingInfo *framework.NominatingInfo
-		if fitError, ok := err.(*framework.FitError); ok {
-			if !fwk.HasPostFilterPlugins() {
-				klog.V(3).InfoS("No PostFilter plugins are registered, so no preemption will be performed")
-			} else {
-				// Run PostFilter plugins to try to make the pod schedulable in a future scheduling cycle.
-				result, status := fwk.RunPostFilterPlugins(ctx, state, pod, fitError.Diagnosis.NodeToStatusMap)
-				if status.Code() == framework.Error {
-					klog.ErrorS(nil, "Status after running PostFilter plugins for pod", "pod", klog.KObj(pod), "status", status)
-				} else {
-					klog.V(5).InfoS("Status after running PostFilter plugins for pod", "pod", klog.KObj(pod), "status", status)
-				}
-				if result != nil {
-					nominatingInfo = result.NominatingInfo
-				}
-			}
-			// Pod did not fit anywhere, so it is counted as a failure. If preemption
-			// succeeds, the pod should get counted as a success the next time we try to
-			// schedule it. (hopefully)
-			metrics.PodUnschedulable(fwk.ProfileName(), metrics.SinceInSeconds(start))
-		} else if err == ErrNoNodesAvailable {
-			nominatingInfo = clearNominatedNode
-			// No nodes available is counted as unschedulable rather than an error.
-			metrics.PodUnschedulable(fwk.ProfileName(), metrics.SinceInSeconds(start))
-		} else {
-			nominatingInfo = clearNominatedNode
-			klog.ErrorS(err, "Error selecting node for pod", "pod", klog.KObj(pod))
-			metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
-		}
-		sched.recordSchedulingFailure(fwk, podInfo, err, v1.PodReasonUnschedulable, nominatingInfo)
+		sched.handleSchedulingFailure(ctx, fwk, state, podInfo, err, start)
 		return
 	}
 	metrics.SchedulingAlgorithmLatency.Observe(metrics.SinceInSeconds(start))
 	my_end := time.Now().UnixNano()
 	fmt.Printf("[INFO] Scheduling over, latency: %vus\n\n", (my_end-my_start)/1000)
 	fmt.Printf("[INFO] The scheduleResult: %v\n\n", scheduleResult)
+	sched.assumeAndBind(ctx, schedulingCycleCtx, fwk, state, podsToActivate, podInfo, scheduleResult, start)
+}
+
+// handleSchedulingFailure handles a pod that the scheduling algorithm could not
+// place, running the PostFilter plugins if it didn't fit on any node.
+func (sched *Scheduler) handleSchedulingFailure(ctx context.Context, fwk framework.Framework, state *framework.CycleState, podInfo *framework.QueuedPodInfo, err error, start time.Time) {
+	pod := podInfo.Pod
+	// Schedule() may have failed because the pod would not fit on any host, so we try to
+	// preempt, with the expectation that the next time the pod is tried for scheduling it
+	// will fit due to the preemption. It is also possible that a different pod will schedule
+	// into the resources that were preempted, but this is harmless.
+	var nominatingInfo *framework.NominatingInfo
+	if fitError, ok := err.(*framework.FitError); ok {
+		if !fwk.HasPostFilterPlugins() {
+			klog.V(3).InfoS("No PostFilter plugins are registered, so no preemption will be performed")
+		} else {
+			// Run PostFilter plugins to try to make the pod schedulable in a future scheduling cycle.
+			result, status := fwk.RunPostFilterPlugins(ctx, state, pod, fitError.Diagnosis.NodeToStatusMap)
+			if status.Code() == framework.Error {
+				klog.ErrorS(nil, "Status after running PostFilter plugins for pod", "pod", klog.KObj(pod), "status", status)
+			} else {
+				klog.V(5).InfoS("Status after running PostFilter plugins for pod", "pod", klog.KObj(pod), "status", status)
+			}
+			if result != nil {
+				nominatingInfo = result.NominatingInfo
+			}
+		}
+		// Pod did not fit anywhere, so it is counted as a failure. If preemption
+		// succeeds, the pod should get counted as a success the next time we try to
+		// schedule it. (hopefully)
+		metrics.PodUnschedulable(fwk.ProfileName(), metrics.SinceInSeconds(start))
+	} else if err == ErrNoNodesAvailable {
+		nominatingInfo = clearNominatedNode
+		// No nodes available is counted as unschedulable rather than an error.
+		metrics.PodUnschedulable(fwk.ProfileName(), metrics.SinceInSeconds(start))
+	} else {
+		nominatingInfo = clearNominatedNode
+		klog.ErrorS(err, "Error selecting node for pod", "pod", klog.KObj(pod))
+		metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
+	}
+	sched.recordSchedulingFailure(fwk, podInfo, err, v1.PodReasonUnschedulable, nominatingInfo)
+}
+
+// assumeAndBind assumes the pod on the host suggested by the scheduling
+// algorithm, runs the Reserve and Permit plugins, and binds the pod to its host
+// asynchronously.
+func (sched *Scheduler) assumeAndBind(ctx, schedulingCycleCtx context.Context, fwk framework.Framework, state *framework.CycleState, podsToActivate *framework.PodsToActivate, podInfo *framework.QueuedPodInfo, scheduleResult ScheduleResult, start time.Time) {
+	pod := podInfo.Pod
 	// Tell the cache to assume that a pod now is running on a given node, even though it hasn't been bound yet.
 	// This allows us to keep scheduling without waiting on binding to occur.
 	assumedPodInfo := podInfo.DeepCopy()
 	assumedPod := assumedPodInfo.Pod
 	// assume modifies `assumedPod` by setting NodeName=scheduleResult.SuggestedHost
-	err = sched.assume(assumedPod, scheduleResult.SuggestedHost)
-	if err != nil {
+	if err := sched.assume(assumedPod, scheduleResult.SuggestedHost); err != nil {
 		metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
 		// This is most probably result of a BUG in retrying logic.
 		// We report an error here so that pod scheduling can be retried.
@@ -513,35 +878,31 @@ func (sched *Scheduler) scheduleOne(ctx context.Context) {
 		return
 	}
 
-	// Run the Reserve method of reserve plugins.
-	if sts := fwk.RunReservePluginsReserve(schedulingCycleCtx, state, assumedPod, scheduleResult.SuggestedHost); !sts.IsSuccess() {
-		metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
-		// trigger un-reserve to clean up state associated with the reserved Pod
-		fwk.RunReservePluginsUnreserve(schedulingCycleCtx, state, assumedPod, scheduleResult.SuggestedHost)
-		if forgetErr := sched.SchedulerCache.ForgetPod(assumedPod); forgetErr != nil {
-			klog.ErrorS(forgetErr, "Scheduler cache ForgetPod failed")
+	// If the suggested host rejects the pod, the pod is moved on to the next-best
+	// ranked hosts instead of being requeued, unless a plugin disabled it.
+	// fallbackFrom is the extension point at which the suggested host rejected
+	// the pod.
+	host := scheduleResult.SuggestedHost
+	fallbackHosts := scheduleResult.FallbackHosts
+	if framework.IsHostFallbackDisabled(state) {
+		fallbackHosts = nil
+	}
+	var fallbackFrom string
+	recordFallback := func(result string) {
+		if fallbackFrom != "" {
+			metrics.HostFallbacks.WithLabelValues(fallbackFrom, result).Inc()
 		}
-		sched.recordSchedulingFailure(fwk, assumedPodInfo, sts.AsError(), SchedulerError, clearNominatedNode)
-		return
 	}
 
-	// Run "permit" plugins.
-	runPermitStatus := fwk.RunPermitPlugins(schedulingCycleCtx, state, assumedPod, scheduleResult.SuggestedHost)
-	if runPermitStatus.Code() != framework.Wait && !runPermitStatus.IsSuccess() {
-		var reason string
-		if runPermitStatus.IsUnschedulable() {
-			metrics.PodUnschedulable(fwk.ProfileName(), metrics.SinceInSeconds(start))
-			reason = v1.PodReasonUnschedulable
-		} else {
-			metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
-			reason = SchedulerError
-		}
-		// One of the plugins returned status different than success or wait.
-		fwk.RunReservePluginsUnreserve(schedulingCycleCtx, state, assumedPod, scheduleResult.SuggestedHost)
-		if forgetErr := sched.SchedulerCache.ForgetPod(assumedPod); forgetErr != nil {
-			klog.ErrorS(forgetErr, "Scheduler cache ForgetPod failed")
-		}
-		sched.recordSchedulingFailure(fwk, assumedPodInfo, runPermitStatus.AsError(), reason, clearNominatedNode)
+	extensionPoint, sts := sched.reserveAndPermit(schedulingCycleCtx, fwk, state, assumedPod, host)
+	if isRejected(sts) && len(fallbackHosts) != 0 {
+		fallbackFrom = extensionPoint
+		klog.V(3).InfoS("Node rejected pod, falling back to the next-best nodes", "pod", klog.KObj(pod), "node", host, "extensionPoint", extensionPoint, "status", sts)
+		host, fallbackHosts, extensionPoint, sts = sched.reserveOnFallbackHosts(schedulingCycleCtx, fwk, state, assumedPod, fallbackHosts)
+	}
+	if isRejected(sts) {
+		recordFallback(fallbackRequeued)
+		sched.handleRejection(fwk, assumedPodInfo, extensionPoint, sts, start)
 		return
 	}
 
@@ -559,89 +920,319 @@ func (sched *Scheduler) scheduleOne(ctx context.Context) {
 		metrics.SchedulerGoroutines.WithLabelValues(metrics.Binding).Inc()
 		defer metrics.SchedulerGoroutines.WithLabelValues(metrics.Binding).Dec()
 
-		waitOnPermitStatus := fwk.WaitOnPermit(bindingCycleCtx, assumedPod)
-		if !waitOnPermitStatus.IsSuccess() {
-			var reason string
-			if waitOnPermitStatus.IsUnschedulable() {
-				metrics.PodUnschedulable(fwk.ProfileName(), metrics.SinceInSeconds(start))
-				reason = v1.PodReasonUnschedulable
-			} else {
-				metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
-				reason = SchedulerError
-			}
-			// trigger un-reserve plugins to clean up state associated with the reserved Pod
-			fwk.RunReservePluginsUnreserve(bindingCycleCtx, state, assumedPod, scheduleResult.SuggestedHost)
-			if forgetErr := sched.SchedulerCache.ForgetPod(assumedPod); forgetErr != nil {
-				klog.ErrorS(forgetErr, "scheduler cache ForgetPod failed")
-			} else {
-				// "Forget"ing an assumed Pod in binding cycle should be treated as a PodDelete event,
-				// as the assumed Pod had occupied a certain amount of resources in scheduler cache.
-				// TODO(#103853): de-duplicate the logic.
-				// Avoid moving the assumed Pod itself as it's always Unschedulable.
-				// It's intentional to "defer" this operation; otherwise MoveAllToActiveOrBackoffQueue() would
-				// update `q.moveRequest` and thus move the assumed pod to backoffQ anyways.
-				defer sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(internalqueue.AssignedPodDelete, func(pod *v1.Pod) bool {
-					return assumedPod.UID != pod.UID
-				})
+		for {
+			waitOnPermitStatus := fwk.WaitOnPermit(bindingCycleCtx, assumedPod)
+			if !waitOnPermitStatus.IsSuccess() {
+				recordFallback(fallbackRequeued)
+				var reason string
+				if waitOnPermitStatus.IsUnschedulable() {
+					metrics.PodUnschedulable(fwk.ProfileName(), metrics.SinceInSeconds(start))
+					reason = v1.PodReasonUnschedulable
+				} else {
+					metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
+					reason = SchedulerError
+				}
+				// trigger un-reserve plugins to clean up state associated with the reserved Pod
+				sched.unreserve(bindingCycleCtx, fwk, state, assumedPod, host)
+				if forgetErr := sched.SchedulerCache.ForgetPod(assumedPod); forgetErr != nil {
+					klog.ErrorS(forgetErr, "scheduler cache ForgetPod failed")
+				} else {
+					// "Forget"ing an assumed Pod in binding cycle should be treated as a PodDelete event,
+					// as the assumed Pod had occupied a certain amount of resources in scheduler cache.
+					// TODO(#103853): de-duplicate the logic.
+					// Avoid moving the assumed Pod itself as it's always Unschedulable.
+					// It's intentional to "defer" this operation; otherwise MoveAllToActiveOrBackoffQueue() would
+					// update `q.moveRequest` and thus move the assumed pod to backoffQ anyways.
+					defer sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(internalqueue.AssignedPodDelete, nil, nil, func(pod *v1.Pod) bool {
+						return assumedPod.UID != pod.UID
+					})
+				}
+				sched.recordSchedulingFailure(fwk, assumedPodInfo, waitOnPermitStatus.AsError(), reason, clearNominatedNode)
+				return
 			}
-			sched.recordSchedulingFailure(fwk, assumedPodInfo, waitOnPermitStatus.AsError(), reason, clearNominatedNode)
-			return
-		}
 
-		// Run "prebind" plugins.
-		preBindStatus := fwk.RunPreBindPlugins(bindingCycleCtx, state, assumedPod, scheduleResult.SuggestedHost)
-		if !preBindStatus.IsSuccess() {
-			metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
-			// trigger un-reserve plugins to clean up state associated with the reserved Pod
-			fwk.RunReservePluginsUnreserve(bindingCycleCtx, state, assumedPod, scheduleResult.SuggestedHost)
-			if forgetErr := sched.SchedulerCache.ForgetPod(assumedPod); forgetErr != nil {
-				klog.ErrorS(forgetErr, "scheduler cache ForgetPod failed")
-			} else {
-				// "Forget"ing an assumed Pod in binding cycle should be treated as a PodDelete event,
-				// as the assumed Pod had occupied a certain amount of resources in scheduler cache.
-				// TODO(#103853): de-duplicate the logic.
-				sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(internalqueue.AssignedPodDelete, nil)
+			// Run "prebind" plugins.
+			preBindStatus := fwk.RunPreBindPlugins(bindingCycleCtx, state, assumedPod, host)
+			if !preBindStatus.IsSuccess() {
+				recordFallback(fallbackRequeued)
+				metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
+				// trigger un-reserve plugins to clean up state associated with the reserved Pod
+				sched.unreserve(bindingCycleCtx, fwk, state, assumedPod, host)
+				if forgetErr := sched.SchedulerCache.ForgetPod(assumedPod); forgetErr != nil {
+					klog.ErrorS(forgetErr, "scheduler cache ForgetPod failed")
+				} else {
+					// "Forget"ing an assumed Pod in binding cycle should be treated as a PodDelete event,
+					// as the assumed Pod had occupied a certain amount of resources in scheduler cache.
+					// TODO(#103853): de-duplicate the logic.
+					sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(internalqueue.AssignedPodDelete, nil, nil, nil)
+				}
+				sched.recordSchedulingFailure(fwk, assumedPodInfo, preBindStatus.AsError(), SchedulerError, clearNominatedNode)
+				return
 			}
-			sched.recordSchedulingFailure(fwk, assumedPodInfo, preBindStatus.AsError(), SchedulerError, clearNominatedNode)
-			return
-		}
 
-		err := sched.bind(bindingCycleCtx, fwk, assumedPod, scheduleResult.SuggestedHost, state)
-		if err != nil {
-			metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
+			err := sched.bind(bindingCycleCtx, fwk, assumedPod, host, state)
+			if err == nil {
+				break
+			}
 			// trigger un-reserve plugins to clean up state associated with the reserved Pod
-			fwk.RunReservePluginsUnreserve(bindingCycleCtx, state, assumedPod, scheduleResult.SuggestedHost)
+			sched.unreserve(bindingCycleCtx, fwk, state, assumedPod, host)
 			if err := sched.SchedulerCache.ForgetPod(assumedPod); err != nil {
 				klog.ErrorS(err, "scheduler cache ForgetPod failed")
 			} else {
 				// "Forget"ing an assumed Pod in binding cycle should be treated as a PodDelete event,
 				// as the assumed Pod had occupied a certain amount of resources in scheduler cache.
 				// TODO(#103853): de-duplicate the logic.
-				sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(internalqueue.AssignedPodDelete, nil)
+				sched.SchedulingQueue.MoveAllToActiveOrBackoffQueue(internalqueue.AssignedPodDelete, nil, nil, nil)
 			}
-			sched.recordSchedulingFailure(fwk, assumedPodInfo, fmt.Errorf("binding rejected: %w", err), SchedulerError, clearNominatedNode)
-		} else {
-			// Calculating nodeResourceString can be heavy. Avoid it if klog verbosity is below 2.
-			if klog.V(2).Enabled() {
-				klog.InfoS("Successfully bound pod to node", "pod", klog.KObj(pod), "node", scheduleResult.SuggestedHost, "evaluatedNodes", scheduleResult.EvaluatedNodes, "feasibleNodes", scheduleResult.FeasibleNodes)
+			if len(fallbackHosts) == 0 {
+				recordFallback(fallbackRequeued)
+				metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
+				sched.recordSchedulingFailure(fwk, assumedPodInfo, fmt.Errorf("binding rejected: %w", err), SchedulerError, clearNominatedNode)
+				return
 			}
-			metrics.PodScheduled(fwk.ProfileName(), metrics.SinceInSeconds(start))
-			metrics.PodSchedulingAttempts.Observe(float64(podInfo.Attempts))
-			metrics.PodSchedulingDuration.WithLabelValues(getAttemptsLabel(podInfo)).Observe(metrics.SinceInSeconds(podInfo.InitialAttemptTimestamp))
-
-			// Run "postbind" plugins.
-			fwk.RunPostBindPlugins(bindingCycleCtx, state, assumedPod, scheduleResult.SuggestedHost)
-
-			// At the end of a successful binding cycle, move up Pods if needed.
-			if len(podsToActivate.Map) != 0 {
-				sched.SchedulingQueue.Activate(podsToActivate.Map)
-				// Unlike the logic in scheduling cycle, we don't bother deleting the entries
-				// as `podsToActivate.Map` is no longer consumed.
+
+			if fallbackFrom == "" {
+				fallbackFrom = bindExtensionPoint
 			}
+			klog.V(3).InfoS("Binding pod failed, falling back to the next-best nodes", "pod", klog.KObj(pod), "node", host, "err", err)
+			host, fallbackHosts, extensionPoint, sts = sched.reserveOnFallbackHostsAfterBind(bindingCycleCtx, fwk, state, assumedPod, fallbackHosts)
+			if isRejected(sts) {
+				recordFallback(fallbackRequeued)
+				sched.handleRejection(fwk, assumedPodInfo, extensionPoint, sts, start)
+				return
+			}
+		}
+
+		recordFallback(fallbackBound)
+		// Calculating nodeResourceString can be heavy. Avoid it if klog verbosity is below 2.
+		if klog.V(2).Enabled() {
+			klog.InfoS("Successfully bound pod to node", "pod", klog.KObj(pod), "node", host, "evaluatedNodes", scheduleResult.EvaluatedNodes, "feasibleNodes", scheduleResult.FeasibleNodes, "hostSelector", fwk.HostSelector().Name())
+		}
+		metrics.PodScheduled(fwk.ProfileName(), metrics.SinceInSeconds(start))
+		metrics.PodSchedulingAttempts.Observe(float64(podInfo.Attempts))
+		metrics.PodSchedulingDuration.WithLabelValues(getAttemptsLabel(podInfo)).Observe(metrics.SinceInSeconds(podInfo.InitialAttemptTimestamp))
+
+		// Run "postbind" plugins.
+		fwk.RunPostBindPlugins(bindingCycleCtx, state, assumedPod, host)
+		sched.extendersPostBind(assumedPod, host)
+
+		// At the end of a successful binding cycle, move up Pods if needed.
+		if len(podsToActivate.Map) != 0 {
+			sched.SchedulingQueue.Activate(podsToActivate.Map)
+			// Unlike the logic in scheduling cycle, we don't bother deleting the entries
+			// as `podsToActivate.Map` is no longer consumed.
 		}
 	}()
 }
 
+// batchKey returns the key shared by the pods that can be scheduled in the same
+// batch: pods of the same profile that are owned by the same controller, and are
+// thus expected to have identical scheduling requirements. It returns an empty
+// string for pods that can't be batched.
+func batchKey(profileName string, pod *v1.Pod) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return ""
+	}
+	return profileName + "/" + string(ref.UID)
+}
+
+// nextBatch returns the given pod along with up to batchSize-1 compatible pods
+// popped, without blocking, from the scheduling queue, in the order of its pop
+// policy. The batch ends at the first pod that isn't compatible.
+func (sched *Scheduler) nextBatch(fwk framework.Framework, podInfo *framework.QueuedPodInfo) []*framework.QueuedPodInfo {
+	batch := []*framework.QueuedPodInfo{podInfo}
+	key := batchKey(fwk.ProfileName(), podInfo.Pod)
+	if key == "" {
+		return batch
+	}
+	for len(batch) < sched.batchSize {
+		next := sched.SchedulingQueue.PopIf(func(pInfo *framework.QueuedPodInfo) bool {
+			return batchKey(sched.profileNameForPod(pInfo.Pod), pInfo.Pod) == key
+		})
+		if next == nil {
+			break
+		}
+		if sched.skipPodSchedule(fwk, next.Pod) {
+			continue
+		}
+		batch = append(batch, next)
+	}
+	return batch
+}
+
+// scheduleBatch runs a single scheduling cycle for a batch of compatible pods.
+// The pods are placed one after the other against the same snapshot, and each
+// placed pod then goes through Reserve, Permit and binding on its own. The first
+// pod that can't be placed is handled as in scheduleOne, and the pods after it,
+// which weren't attempted, are then scheduled one by one.
+func (sched *Scheduler) scheduleBatch(ctx context.Context, fwk framework.Framework, algo BatchScheduleAlgorithm, batch []*framework.QueuedPodInfo) {
+	klog.V(3).InfoS("Attempting to schedule batch of pods", "pod", klog.KObj(batch[0].Pod), "batchSize", len(batch))
+
+	start := time.Now()
+	pods := make([]*v1.Pod, len(batch))
+	states := make([]*framework.CycleState, len(batch))
+	podsToActivate := make([]*framework.PodsToActivate, len(batch))
+	for i, podInfo := range batch {
+		pods[i] = podInfo.Pod
+		states[i] = framework.NewCycleState()
+		states[i].SetRecordPluginMetrics(fwk.Rand().Intn(100) < pluginMetricsSamplePercent)
+		podsToActivate[i] = framework.NewPodsToActivate()
+		states[i].Write(framework.PodsToActivateKey, podsToActivate[i])
+	}
+
+	schedulingCycleCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results, err := algo.ScheduleBatch(schedulingCycleCtx, sched.Extenders, fwk, states, pods)
+	metrics.SchedulingAlgorithmLatency.Observe(metrics.SinceInSeconds(start))
+	for i, result := range results {
+		sched.assumeAndBind(ctx, schedulingCycleCtx, fwk, states[i], podsToActivate[i], batch[i], result, start)
+	}
+	if err == nil {
+		return
+	}
+	failed := len(results)
+	if failed != 0 {
+		// The snapshot was restored when the batch stopped: refresh it, so
+		// that the failure is handled against the pods placed before the
+		// failed one that are now assumed in the cache.
+		if err := sched.SchedulerCache.UpdateSnapshot(sched.nodeInfoSnapshot); err != nil {
+			klog.ErrorS(err, "Failed to update the snapshot after a batch of pods")
+		}
+	}
+	sched.handleSchedulingFailure(ctx, fwk, states[failed], batch[failed], err, start)
+	// The failure of a pod doesn't say why the next ones would fail, nor
+	// whether they would: the preemption of the failed pod may have made room
+	// for them, or they may have failed on a transient error.
+	for _, podInfo := range batch[failed+1:] {
+		if sched.skipPodSchedule(fwk, podInfo.Pod) {
+			continue
+		}
+		sched.schedulePod(ctx, fwk, podInfo)
+	}
+}
+
+// Extension points at which a host can reject a pod, so that the pod falls back
+// to the next-best ranked host. They are used as values of the extension_point
+// label of the host fallback metric.
+const (
+	reserveExtensionPoint = "Reserve"
+	permitExtensionPoint  = "Permit"
+	bindExtensionPoint    = "Bind"
+)
+
+// Results of a host fallback, used as values of the result label of the host
+// fallback metric.
+const (
+	fallbackBound    = "bound"
+	fallbackRequeued = "requeued"
+)
+
+// isRejected returns true if the status returned by reserveAndPermit means that
+// the pod can't stay on the host it was assumed on.
+func isRejected(sts *framework.Status) bool {
+	return !sts.IsSuccess() && sts.Code() != framework.Wait
+}
+
+// reserveAndPermit runs the Reserve and Permit plugins for a pod assumed on host.
+// If either of them rejects the pod, the reservation and the assumption are
+// undone. It returns the extension point that returned the final status.
+func (sched *Scheduler) reserveAndPermit(ctx context.Context, fwk framework.Framework, state *framework.CycleState, assumedPod *v1.Pod, host string) (string, *framework.Status) {
+	// Run the Reserve method of reserve plugins, then of the extenders.
+	sts := fwk.RunReservePluginsReserve(ctx, state, assumedPod, host)
+	if sts.IsSuccess() {
+		sts = sched.extendersReserve(assumedPod, host)
+	}
+	if !sts.IsSuccess() {
+		// trigger un-reserve to clean up state associated with the reserved Pod
+		sched.unreserve(ctx, fwk, state, assumedPod, host)
+		if forgetErr := sched.SchedulerCache.ForgetPod(assumedPod); forgetErr != nil {
+			klog.ErrorS(forgetErr, "Scheduler cache ForgetPod failed")
+		}
+		return reserveExtensionPoint, sts
+	}
+
+	// Run "permit" plugins.
+	runPermitStatus := fwk.RunPermitPlugins(ctx, state, assumedPod, host)
+	if isRejected(runPermitStatus) {
+		// One of the plugins returned status different than success or wait.
+		sched.unreserve(ctx, fwk, state, assumedPod, host)
+		if forgetErr := sched.SchedulerCache.ForgetPod(assumedPod); forgetErr != nil {
+			klog.ErrorS(forgetErr, "Scheduler cache ForgetPod failed")
+		}
+	}
+	return permitExtensionPoint, runPermitStatus
+}
+
+// reserveOnFallbackHosts assumes the pod on each of the given ranked hosts in
+// turn, until one of them gets it through Reserve and Permit. It returns the
+// last host tried, the hosts left after it, and the final status along with the
+// extension point that returned it. If the pod can't be assumed, the returned
+// extension point is empty.
+func (sched *Scheduler) reserveOnFallbackHosts(ctx context.Context, fwk framework.Framework, state *framework.CycleState, assumedPod *v1.Pod, hosts []string) (string, []string, string, *framework.Status) {
+	var host, extensionPoint string
+	var sts *framework.Status
+	for len(hosts) != 0 {
+		host, hosts = hosts[0], hosts[1:]
+		if err := sched.assume(assumedPod, host); err != nil {
+			return host, hosts, "", framework.AsStatus(err)
+		}
+		extensionPoint, sts = sched.reserveAndPermit(ctx, fwk, state, assumedPod, host)
+		if !isRejected(sts) {
+			klog.V(3).InfoS("Pod fell back to node", "pod", klog.KObj(assumedPod), "node", host)
+			break
+		}
+		klog.V(4).InfoS("Fallback node rejected pod", "pod", klog.KObj(assumedPod), "node", host, "extensionPoint", extensionPoint, "status", sts)
+	}
+	return host, hosts, extensionPoint, sts
+}
+
+// reserveOnFallbackHostsAfterBind is reserveOnFallbackHosts for a pod that
+// failed to bind. It runs between two scheduling cycles: as the hosts were
+// filtered in a scheduling cycle that may have been followed by others, the
+// Filter plugins are run again on each of them against an updated snapshot,
+// and the hosts that don't fit the pod anymore are skipped.
+func (sched *Scheduler) reserveOnFallbackHostsAfterBind(ctx context.Context, fwk framework.Framework, state *framework.CycleState, assumedPod *v1.Pod, hosts []string) (string, []string, string, *framework.Status) {
+	sched.cycleLock.Lock()
+	defer sched.cycleLock.Unlock()
+	if err := sched.SchedulerCache.UpdateSnapshot(sched.nodeInfoSnapshot); err != nil {
+		return "", nil, "", framework.AsStatus(err)
+	}
+	pod := assumedPod.DeepCopy()
+	pod.Spec.NodeName = ""
+	var fitting []string
+	for _, host := range hosts {
+		nodeInfo, err := sched.nodeInfoSnapshot.NodeInfos().Get(host)
+		if err != nil {
+			klog.V(4).InfoS("Fallback node is gone", "pod", klog.KObj(pod), "node", host)
+			continue
+		}
+		if sts := fwk.RunFilterPluginsWithNominatedPods(ctx, state, pod, nodeInfo); !sts.IsSuccess() {
+			klog.V(4).InfoS("Fallback node doesn't fit pod anymore", "pod", klog.KObj(pod), "node", host, "status", sts)
+			continue
+		}
+		fitting = append(fitting, host)
+	}
+	if len(fitting) == 0 {
+		return "", nil, "", framework.NewStatus(framework.Unschedulable, "none of the fallback nodes fits the pod anymore")
+	}
+	return sched.reserveOnFallbackHosts(ctx, fwk, state, assumedPod, fitting)
+}
+
+// handleRejection records the failure of a pod that was rejected at the given
+// extension point. Only a pod rejected by Permit plugins is reported as
+// unschedulable, anything else is a scheduler error.
+func (sched *Scheduler) handleRejection(fwk framework.Framework, podInfo *framework.QueuedPodInfo, extensionPoint string, sts *framework.Status, start time.Time) {
+	reason := SchedulerError
+	if extensionPoint == permitExtensionPoint && sts.IsUnschedulable() {
+		metrics.PodUnschedulable(fwk.ProfileName(), metrics.SinceInSeconds(start))
+		reason = v1.PodReasonUnschedulable
+	} else {
+		metrics.PodScheduleError(fwk.ProfileName(), metrics.SinceInSeconds(start))
+	}
+	sched.recordSchedulingFailure(fwk, podInfo, sts.AsError(), reason, clearNominatedNode)
+}
+
 func getAttemptsLabel(p *framework.QueuedPodInfo) string {
 	// We breakdown the pod scheduling duration by attempts capped to a limit
 	// to avoid ending up with a high cardinality metric.
@@ -651,10 +1242,27 @@ func getAttemptsLabel(p *framework.QueuedPodInfo) string {
 	return strconv.Itoa(p.Attempts)
 }
 
+// profileMap returns the current profiles, which ReloadProfiles may replace
+// at any time between scheduling cycles.
+func (sched *Scheduler) profileMap() profile.Map {
+	sched.profilesLock.RLock()
+	defer sched.profilesLock.RUnlock()
+	return sched.Profiles
+}
+
+// profileNameForPod returns the name of the profile the pod is scheduled with.
+func (sched *Scheduler) profileNameForPod(pod *v1.Pod) string {
+	if sched.profileName == nil {
+		return pod.Spec.SchedulerName
+	}
+	return sched.profileName(pod)
+}
+
 func (sched *Scheduler) frameworkForPod(pod *v1.Pod) (framework.Framework, error) {
-	fwk, ok := sched.Profiles[pod.Spec.SchedulerName]
+	name := sched.profileNameForPod(pod)
+	fwk, ok := sched.profileMap()[name]
 	if !ok {
-		return nil, fmt.Errorf("profile not found for scheduler name %q", pod.Spec.SchedulerName)
+		return nil, fmt.Errorf("profile not found for scheduler name %q", name)
 	}
 	return fwk, nil
 }
@@ -43,8 +43,10 @@ import (
 	clienttesting "k8s.io/client-go/testing"
 	clientcache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/component-base/metrics/testutil"
 	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/coscheduling"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/nodeports"
@@ -55,6 +57,7 @@ import (
 	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
 	fakecache "k8s.io/kubernetes/pkg/scheduler/internal/cache/fake"
 	internalqueue "k8s.io/kubernetes/pkg/scheduler/internal/queue"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
 	"k8s.io/kubernetes/pkg/scheduler/profile"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
 )
@@ -206,6 +209,70 @@ func TestSchedulerCreation(t *testing.T) {
 				)},
 			wantErr: "duplicate profile with scheduler name \"foo\"",
 		},
+		{
+			name: "With host selector",
+			opts: []Option{
+				WithProfiles(
+					schedulerapi.KubeSchedulerProfile{
+						SchedulerName: "default-scheduler",
+						Plugins: &schedulerapi.Plugins{
+							QueueSort: schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{{Name: "PrioritySort"}}},
+							Bind:      schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{{Name: "DefaultBinder"}}},
+						},
+					},
+				),
+				WithHostSelector("default-scheduler", &schedulerapi.HostSelector{Type: schedulerapi.TopKHostSelector, K: 3}),
+			},
+			wantProfiles: []string{"default-scheduler"},
+		},
+		{
+			name: "host selector of unknown profile",
+			opts: []Option{
+				WithProfiles(
+					schedulerapi.KubeSchedulerProfile{
+						SchedulerName: "default-scheduler",
+						Plugins: &schedulerapi.Plugins{
+							QueueSort: schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{{Name: "PrioritySort"}}},
+							Bind:      schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{{Name: "DefaultBinder"}}},
+						},
+					},
+				),
+				WithHostSelector("foo", &schedulerapi.HostSelector{Type: schedulerapi.TopKHostSelector, K: 3}),
+			},
+			wantErr: "host selector of unknown profile \"foo\"",
+		},
+		{
+			name: "invalid backoff policy",
+			opts: []Option{
+				WithProfiles(
+					schedulerapi.KubeSchedulerProfile{
+						SchedulerName: "default-scheduler",
+						Plugins: &schedulerapi.Plugins{
+							QueueSort: schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{{Name: "PrioritySort"}}},
+							Bind:      schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{{Name: "DefaultBinder"}}},
+						},
+					},
+				),
+				WithBackoffPolicy(&schedulerapi.BackoffPolicy{Jitter: 1}),
+			},
+			wantErr: "backoffPolicy.jitter",
+		},
+		{
+			name: "negative batch size",
+			opts: []Option{
+				WithProfiles(
+					schedulerapi.KubeSchedulerProfile{
+						SchedulerName: "default-scheduler",
+						Plugins: &schedulerapi.Plugins{
+							QueueSort: schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{{Name: "PrioritySort"}}},
+							Bind:      schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{{Name: "DefaultBinder"}}},
+						},
+					},
+				),
+				WithBatchSize(-1),
+			},
+			wantErr: "batch size must be greater than or equal to 0",
+		},
 		{
 			name: "With extenders",
 			opts: []Option{
@@ -484,6 +551,299 @@ func TestSchedulerScheduleOne(t *testing.T) {
 	}
 }
 
+// fakeHostRejector rejects pods at Reserve or Permit on the given nodes.
+type fakeHostRejector struct {
+	filter  sets.String
+	reserve sets.String
+	permit  sets.String
+}
+
+func (pl *fakeHostRejector) Name() string {
+	return "FakeHostRejector"
+}
+
+func (pl *fakeHostRejector) Filter(_ context.Context, _ *framework.CycleState, _ *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	if pl.filter.Has(nodeInfo.Node().Name) {
+		return framework.NewStatus(framework.Unschedulable, "filter rejected "+nodeInfo.Node().Name)
+	}
+	return nil
+}
+
+func (pl *fakeHostRejector) Reserve(_ context.Context, _ *framework.CycleState, _ *v1.Pod, nodeName string) *framework.Status {
+	if pl.reserve.Has(nodeName) {
+		return framework.NewStatus(framework.Error, "reserve rejected "+nodeName)
+	}
+	return nil
+}
+
+func (pl *fakeHostRejector) Unreserve(_ context.Context, _ *framework.CycleState, _ *v1.Pod, _ string) {
+}
+
+func (pl *fakeHostRejector) Permit(_ context.Context, _ *framework.CycleState, _ *v1.Pod, nodeName string) (*framework.Status, time.Duration) {
+	if pl.permit.Has(nodeName) {
+		return framework.NewStatus(framework.Unschedulable, "permit rejected "+nodeName), 0
+	}
+	return nil, 0
+}
+
+// preFilteringMockScheduler is a mockScheduler that runs the PreFilter plugins
+// before returning its result.
+type preFilteringMockScheduler struct {
+	mockScheduler
+}
+
+func (es preFilteringMockScheduler) Schedule(ctx context.Context, extenders []framework.Extender, fwk framework.Framework, state *framework.CycleState, pod *v1.Pod) (ScheduleResult, error) {
+	if s := fwk.RunPreFilterPlugins(ctx, state, pod); !s.IsSuccess() {
+		return ScheduleResult{}, s.AsError()
+	}
+	return es.mockScheduler.Schedule(ctx, extenders, fwk, state, pod)
+}
+
+func TestSchedulerScheduleOneWithFallback(t *testing.T) {
+	metrics.Register()
+	eventBroadcaster := events.NewBroadcaster(&events.EventSinkImpl{Interface: clientsetfake.NewSimpleClientset().EventsV1()})
+	fallbackResult := ScheduleResult{SuggestedHost: "machine1", EvaluatedNodes: 3, FeasibleNodes: 3, FallbackHosts: []string{"machine2", "machine3"}}
+
+	table := []struct {
+		name               string
+		result             ScheduleResult
+		podGroup           bool
+		rejectFilter       []string
+		rejectReserve      []string
+		rejectPermit       []string
+		rejectBind         []string
+		wantAssumedHosts   []string
+		wantBindHosts      []string
+		wantError          string
+		wantEventReason    string
+		wantFallbackFrom   string
+		wantFallbackResult string
+	}{
+		{
+			name:               "reserve rejects suggested host",
+			result:             fallbackResult,
+			rejectReserve:      []string{"machine1"},
+			wantAssumedHosts:   []string{"machine1", "machine2"},
+			wantBindHosts:      []string{"machine2"},
+			wantEventReason:    "Scheduled",
+			wantFallbackFrom:   "Reserve",
+			wantFallbackResult: "bound",
+		},
+		{
+			name:               "permit rejects suggested host and first fallback",
+			result:             fallbackResult,
+			rejectPermit:       []string{"machine1", "machine2"},
+			wantAssumedHosts:   []string{"machine1", "machine2", "machine3"},
+			wantBindHosts:      []string{"machine3"},
+			wantEventReason:    "Scheduled",
+			wantFallbackFrom:   "Permit",
+			wantFallbackResult: "bound",
+		},
+		{
+			name:               "bind fails on suggested host",
+			result:             fallbackResult,
+			rejectBind:         []string{"machine1"},
+			wantAssumedHosts:   []string{"machine1", "machine2"},
+			wantBindHosts:      []string{"machine1", "machine2"},
+			wantEventReason:    "Scheduled",
+			wantFallbackFrom:   "Bind",
+			wantFallbackResult: "bound",
+		},
+		{
+			name:               "bind fails and fallback is rejected at reserve",
+			result:             fallbackResult,
+			rejectReserve:      []string{"machine2"},
+			rejectBind:         []string{"machine1"},
+			wantAssumedHosts:   []string{"machine1", "machine2", "machine3"},
+			wantBindHosts:      []string{"machine1", "machine3"},
+			wantEventReason:    "Scheduled",
+			wantFallbackFrom:   "Bind",
+			wantFallbackResult: "bound",
+		},
+		{
+			name:               "bind fails on fallback host",
+			result:             fallbackResult,
+			rejectPermit:       []string{"machine1"},
+			rejectBind:         []string{"machine2"},
+			wantAssumedHosts:   []string{"machine1", "machine2", "machine3"},
+			wantBindHosts:      []string{"machine2", "machine3"},
+			wantEventReason:    "Scheduled",
+			wantFallbackFrom:   "Permit",
+			wantFallbackResult: "bound",
+		},
+		{
+			name:               "bind fails and fallback hosts no longer fit",
+			result:             fallbackResult,
+			rejectFilter:       []string{"machine2", "machine3"},
+			rejectBind:         []string{"machine1"},
+			wantAssumedHosts:   []string{"machine1"},
+			wantBindHosts:      []string{"machine1"},
+			wantError:          "none of the fallback nodes fits the pod anymore",
+			wantEventReason:    "FailedScheduling",
+			wantFallbackFrom:   "Bind",
+			wantFallbackResult: "requeued",
+		},
+		{
+			name:               "bind fails on all hosts",
+			result:             fallbackResult,
+			rejectBind:         []string{"machine1", "machine2", "machine3"},
+			wantAssumedHosts:   []string{"machine1", "machine2", "machine3"},
+			wantBindHosts:      []string{"machine1", "machine2", "machine3"},
+			wantError:          `binding rejected: running Bind plugin "DefaultBinder": binder`,
+			wantEventReason:    "FailedScheduling",
+			wantFallbackFrom:   "Bind",
+			wantFallbackResult: "requeued",
+		},
+		{
+			name:               "all hosts reject",
+			result:             fallbackResult,
+			rejectReserve:      []string{"machine1", "machine2", "machine3"},
+			wantAssumedHosts:   []string{"machine1", "machine2", "machine3"},
+			wantError:          `running Reserve plugin "FakeHostRejector": reserve rejected machine3`,
+			wantEventReason:    "FailedScheduling",
+			wantFallbackFrom:   "Reserve",
+			wantFallbackResult: "requeued",
+		},
+		{
+			name:             "no fallback hosts",
+			result:           ScheduleResult{SuggestedHost: "machine1", EvaluatedNodes: 3, FeasibleNodes: 3},
+			rejectPermit:     []string{"machine1"},
+			wantAssumedHosts: []string{"machine1"},
+			wantError:        "permit rejected machine1",
+			wantEventReason:  "FailedScheduling",
+		},
+		{
+			name:             "pod group member doesn't fall back",
+			result:           fallbackResult,
+			podGroup:         true,
+			rejectReserve:    []string{"machine1"},
+			wantAssumedHosts: []string{"machine1"},
+			wantError:        `running Reserve plugin "FakeHostRejector": reserve rejected machine1`,
+			wantEventReason:  "FailedScheduling",
+		},
+	}
+
+	for _, item := range table {
+		t.Run(item.name, func(t *testing.T) {
+			metrics.HostFallbacks.Reset()
+			pod := podWithID("foo", "")
+			sibling := podWithID("bar", "")
+			if item.podGroup {
+				for _, p := range []*v1.Pod{pod, sibling} {
+					p.Labels = map[string]string{coscheduling.PodGroupLabel: "group", coscheduling.PodGroupMinMemberLabel: "2"}
+				}
+			}
+			var gotError error
+			var gotAssumedHosts, gotBindHosts []string
+			sCache := &fakecache.Cache{
+				AssumeFunc: func(pod *v1.Pod) {
+					gotAssumedHosts = append(gotAssumedHosts, pod.Spec.NodeName)
+				},
+				ForgetFunc: func(pod *v1.Pod) {},
+				IsAssumedPodFunc: func(pod *v1.Pod) bool {
+					return false
+				},
+			}
+			client := clientsetfake.NewSimpleClientset(pod, sibling)
+			client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "binding" {
+					return false, nil, nil
+				}
+				binding := action.(clienttesting.CreateAction).GetObject().(*v1.Binding)
+				gotBindHosts = append(gotBindHosts, binding.Target.Name)
+				if sets.NewString(item.rejectBind...).Has(binding.Target.Name) {
+					return true, nil, errors.New("binder")
+				}
+				return true, binding, nil
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			informerFactory := informers.NewSharedInformerFactory(client, 0)
+			queue := internalqueue.NewTestQueue(ctx, nil)
+			rejector := &fakeHostRejector{filter: sets.NewString(item.rejectFilter...), reserve: sets.NewString(item.rejectReserve...), permit: sets.NewString(item.rejectPermit...)}
+			fwk, err := st.NewFramework([]st.RegisterPluginFunc{
+				st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				st.RegisterPluginAsExtensions(rejector.Name(), func(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+					return rejector, nil
+				}, "Filter", "Reserve", "Permit"),
+				st.RegisterPluginAsExtensions(coscheduling.Name, coscheduling.New, "PreFilter", "Reserve", "Permit"),
+				st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			},
+				testSchedulerName,
+				frameworkruntime.WithClientSet(client),
+				frameworkruntime.WithInformerFactory(informerFactory),
+				frameworkruntime.WithPodNominator(queue),
+				frameworkruntime.WithEventRecorder(eventBroadcaster.NewRecorder(scheme.Scheme, testSchedulerName)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			informerFactory.Start(ctx.Done())
+			informerFactory.WaitForCacheSync(ctx.Done())
+			var nodes []*v1.Node
+			for _, name := range []string{"machine1", "machine2", "machine3"} {
+				nodes = append(nodes, st.MakeNode().Name(name).Obj())
+			}
+
+			s := &Scheduler{
+				SchedulerCache: sCache,
+				Algorithm:      preFilteringMockScheduler{mockScheduler{item.result, nil}},
+				client:         client,
+				Error: func(p *framework.QueuedPodInfo, err error) {
+					gotError = err
+				},
+				NextPod: func() *framework.QueuedPodInfo {
+					return &framework.QueuedPodInfo{PodInfo: framework.NewPodInfo(pod)}
+				},
+				Profiles: profile.Map{
+					testSchedulerName: fwk,
+				},
+				SchedulingQueue:  queue,
+				nodeInfoSnapshot: internalcache.NewSnapshot(nil, nodes),
+			}
+			called := make(chan struct{})
+			stopFunc := eventBroadcaster.StartEventWatcher(func(obj runtime.Object) {
+				e, _ := obj.(*eventsv1.Event)
+				if e.Reason != item.wantEventReason {
+					t.Errorf("got event %v, want %v", e.Reason, item.wantEventReason)
+				}
+				close(called)
+			})
+			defer stopFunc()
+			s.scheduleOne(ctx)
+			<-called
+
+			if diff := cmp.Diff(item.wantAssumedHosts, gotAssumedHosts); diff != "" {
+				t.Errorf("Unexpected assumed hosts (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(item.wantBindHosts, gotBindHosts); diff != "" {
+				t.Errorf("Unexpected bind hosts (-want, +got):\n%s", diff)
+			}
+			var gotErrorMsg string
+			if gotError != nil {
+				gotErrorMsg = gotError.Error()
+			}
+			if gotErrorMsg != item.wantError {
+				t.Errorf("Unexpected error: got %q, want %q", gotErrorMsg, item.wantError)
+			}
+			for _, extensionPoint := range []string{"Reserve", "Permit", "Bind"} {
+				for _, result := range []string{"bound", "requeued"} {
+					got, err := testutil.GetCounterMetricValue(metrics.HostFallbacks.WithLabelValues(extensionPoint, result))
+					if err != nil {
+						t.Fatal(err)
+					}
+					want := 0.0
+					if extensionPoint == item.wantFallbackFrom && result == item.wantFallbackResult {
+						want = 1
+					}
+					if got != want {
+						t.Errorf("Unexpected host fallbacks with extension point %q and result %q: got %v, want %v", extensionPoint, result, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
 type fakeNodeSelectorArgs struct {
 	NodeName string `json:"nodeName"`
 }
@@ -775,6 +1135,94 @@ func TestSchedulerNoPhantomPodAfterDelete(t *testing.T) {
 	}
 }
 
+func TestSchedulerScheduleBatch(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	scache := internalcache.New(10*time.Minute, stop)
+
+	// Each node fits two of the batched pods.
+	var objects []runtime.Object
+	for i := 0; i < 2; i++ {
+		name := fmt.Sprintf("machine%v", i)
+		node := v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)},
+			Status: v1.NodeStatus{
+				Allocatable: v1.ResourceList{
+					v1.ResourceCPU:  *(resource.NewQuantity(2, resource.DecimalSI)),
+					v1.ResourcePods: *(resource.NewQuantity(10, resource.DecimalSI)),
+				}},
+		}
+		scache.AddNode(&node)
+		objects = append(objects, &node)
+	}
+	client := clientsetfake.NewSimpleClientset(objects...)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	fns := []st.RegisterPluginFunc{
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		st.RegisterPluginAsExtensions(noderesources.FitName, frameworkruntime.FactoryAdapter(feature.Features{}, noderesources.NewFit), "Filter", "PreFilter"),
+	}
+	scheduler, bindingChan, errChan := setupTestScheduler(nil, scache, informerFactory, nil, fns...)
+	queue := internalqueue.NewTestQueue(context.Background(), scheduler.Profiles[testSchedulerName].QueueSortFunc())
+	scheduler.SchedulingQueue = queue
+	scheduler.NextPod = internalqueue.MakeNextPodFunc(queue)
+	scheduler.batchSize = 6
+
+	informerFactory.Start(stop)
+	informerFactory.WaitForCacheSync(stop)
+
+	controller := metav1.NewControllerRef(&metav1.ObjectMeta{Name: "rs", UID: "rs"}, v1.SchemeGroupVersion.WithKind("ReplicaSet"))
+	requests := v1.ResourceList{v1.ResourceCPU: *(resource.NewQuantity(1, resource.DecimalSI))}
+	for i := 0; i < 5; i++ {
+		pod := podWithResources(fmt.Sprintf("foo%v", i), "", requests, requests)
+		pod.OwnerReferences = []metav1.OwnerReference{*controller}
+		queue.Add(pod)
+	}
+	// The pod after the first one that doesn't fit is scheduled on its own,
+	// rather than failed with the same error.
+	small := podWithID("foo5", "")
+	small.OwnerReferences = []metav1.OwnerReference{*controller}
+	queue.Add(small)
+	// A pod of another controller is not part of the batch.
+	queue.Add(podWithID("bar", ""))
+
+	scheduler.scheduleOne(context.Background())
+
+	// The placements are computed before any of the pods is assumed in the
+	// cache, so they only spread correctly if the snapshot took them into account.
+	podsPerNode := make(map[string]int)
+	smallBound := false
+	for i := 0; i < 5; i++ {
+		select {
+		case b := <-bindingChan:
+			if b.Name == small.Name {
+				smallBound = true
+				continue
+			}
+			podsPerNode[b.Target.Name]++
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatalf("timeout after %v", wait.ForeverTestTimeout)
+		}
+	}
+	if !smallBound {
+		t.Errorf("Expected pod %v to be bound", small.Name)
+	}
+	if diff := cmp.Diff(map[string]int{"machine0": 2, "machine1": 2}, podsPerNode); diff != "" {
+		t.Errorf("Unexpected pods per node (-want, +got):\n%s", diff)
+	}
+	select {
+	case err := <-errChan:
+		if _, ok := err.(*framework.FitError); !ok {
+			t.Errorf("Expected a FitError for the fifth pod, got %v", err)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatalf("timeout after %v", wait.ForeverTestTimeout)
+	}
+	if pods := queue.PendingPods(); len(pods) != 1 || pods[0].Name != "bar" {
+		t.Errorf("Expected only pod bar to be left in the queue, got %v", pods)
+	}
+}
+
 // queuedPodStore: pods queued before processing.
 // cache: scheduler cache that might contain assumed pods.
 func setupTestSchedulerWithOnePodOnNode(t *testing.T, queuedPodStore *clientcache.FIFO, scache internalcache.Cache,
@@ -923,16 +1371,19 @@ func setupTestScheduler(queuedPodStore *clientcache.FIFO, scache internalcache.C
 		frameworkruntime.WithPodNominator(internalqueue.NewPodNominator(informerFactory.Core().V1().Pods().Lister())),
 	)
 
+	snapshot := internalcache.NewEmptySnapshot()
 	algo := NewGenericScheduler(
 		scache,
-		internalcache.NewEmptySnapshot(),
+		snapshot,
 		schedulerapi.DefaultPercentageOfNodesToScore,
+		false, 0,
 	)
 
 	errChan := make(chan error, 1)
 	sched := &Scheduler{
-		SchedulerCache: scache,
-		Algorithm:      algo,
+		SchedulerCache:   scache,
+		Algorithm:        algo,
+		nodeInfoSnapshot: snapshot,
 		NextPod: func() *framework.QueuedPodInfo {
 			return &framework.QueuedPodInfo{PodInfo: framework.NewPodInfo(clientcache.Pop(queuedPodStore).(*v1.Pod))}
 		},
@@ -1197,6 +1648,7 @@ func TestSchedulerBinding(t *testing.T) {
 				scache,
 				nil,
 				0,
+				false, 0,
 			)
 			sched := Scheduler{
 				Algorithm:      algo,
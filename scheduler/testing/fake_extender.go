@@ -19,14 +19,20 @@ package testing
 import (
 	"context"
 	"fmt"
+	"net"
 	"sort"
 
+	"google.golang.org/grpc"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
 	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	grpcextenderv1 "k8s.io/kubernetes/pkg/scheduler/apis/extender/v1alpha1"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
 	"k8s.io/kubernetes/pkg/scheduler/util"
 )
 
@@ -374,3 +380,177 @@ func (f *FakeExtender) IsInterested(pod *v1.Pod) bool {
 }
 
 var _ framework.Extender = &FakeExtender{}
+
+// FakeGRPCExtender serves an Extender, e.g. a FakeExtender, with the Extender
+// gRPC service.
+type FakeGRPCExtender struct {
+	grpcextenderv1.UnimplementedExtenderServer
+
+	Extender framework.Extender
+	// Nodes and Pods are the cluster known to the extender, used to resolve
+	// the names of the nodes and the UIDs of the pods of the requests.
+	Nodes []*v1.Node
+	Pods  []*v1.Pod
+
+	snapshot *internalcache.Snapshot
+	server   *grpc.Server
+}
+
+// Start serves the extender on a local port and returns its address.
+func (f *FakeGRPCExtender) Start() (string, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	f.snapshot = internalcache.NewSnapshot(f.Pods, f.Nodes)
+	f.server = grpc.NewServer()
+	grpcextenderv1.RegisterExtenderServer(f.server, f)
+	go f.server.Serve(lis)
+	return lis.Addr().String(), nil
+}
+
+// Stop stops serving the extender.
+func (f *FakeGRPCExtender) Stop() {
+	f.server.Stop()
+}
+
+// nodes returns the nodes of the Filter and Prioritize arguments.
+func (f *FakeGRPCExtender) nodes(args *grpcextenderv1.ExtenderArgs) ([]*v1.Node, error) {
+	nodes := make([]*v1.Node, 0, len(args.NodeNames))
+	if len(args.Nodes) != 0 {
+		for _, data := range args.Nodes {
+			node := &v1.Node{}
+			if err := node.Unmarshal(data); err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes, nil
+	}
+	for _, name := range args.NodeNames {
+		nodeInfo, err := f.snapshot.NodeInfos().Get(name)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, nodeInfo.Node())
+	}
+	return nodes, nil
+}
+
+// Filter implements the Filter call of the Extender service.
+func (f *FakeGRPCExtender) Filter(_ context.Context, args *grpcextenderv1.ExtenderArgs) (*grpcextenderv1.ExtenderFilterResult, error) {
+	pod, err := unmarshalPod(args.Pod)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := f.nodes(args)
+	if err != nil {
+		return nil, err
+	}
+	filtered, failed, failedAndUnresolvable, err := f.Extender.Filter(pod, nodes)
+	if err != nil {
+		return &grpcextenderv1.ExtenderFilterResult{Error: err.Error()}, nil
+	}
+	result := &grpcextenderv1.ExtenderFilterResult{
+		FailedNodes:                failed,
+		FailedAndUnresolvableNodes: failedAndUnresolvable,
+	}
+	for _, n := range filtered {
+		result.NodeNames = append(result.NodeNames, n.Name)
+	}
+	return result, nil
+}
+
+// Prioritize implements the Prioritize call of the Extender service.
+func (f *FakeGRPCExtender) Prioritize(_ context.Context, args *grpcextenderv1.ExtenderArgs) (*grpcextenderv1.HostPriorityList, error) {
+	pod, err := unmarshalPod(args.Pod)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := f.nodes(args)
+	if err != nil {
+		return nil, err
+	}
+	priorities, _, err := f.Extender.Prioritize(pod, nodes)
+	if err != nil {
+		return nil, err
+	}
+	result := &grpcextenderv1.HostPriorityList{}
+	for _, p := range *priorities {
+		result.Priorities = append(result.Priorities, &grpcextenderv1.HostPriority{Host: p.Host, Score: p.Score})
+	}
+	return result, nil
+}
+
+// Bind implements the Bind call of the Extender service.
+func (f *FakeGRPCExtender) Bind(_ context.Context, args *grpcextenderv1.ExtenderBindingArgs) (*grpcextenderv1.ExtenderBindingResult, error) {
+	err := f.Extender.Bind(&v1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: args.PodNamespace, Name: args.PodName, UID: types.UID(args.PodUid)},
+		Target:     v1.ObjectReference{Kind: "Node", Name: args.Node},
+	})
+	if err != nil {
+		return &grpcextenderv1.ExtenderBindingResult{Error: err.Error()}, nil
+	}
+	return &grpcextenderv1.ExtenderBindingResult{}, nil
+}
+
+// ProcessPreemption implements the ProcessPreemption call of the Extender
+// service.
+func (f *FakeGRPCExtender) ProcessPreemption(_ context.Context, args *grpcextenderv1.ExtenderPreemptionArgs) (*grpcextenderv1.ExtenderPreemptionResult, error) {
+	pod, err := unmarshalPod(args.Pod)
+	if err != nil {
+		return nil, err
+	}
+	nodeInfos := f.snapshot.NodeInfos()
+	nodeNameToVictims := make(map[string]*extenderv1.Victims, len(args.NodeNameToVictims))
+	for nodeName, v := range args.NodeNameToVictims {
+		victims := &extenderv1.Victims{NumPDBViolations: v.NumPdbViolations}
+		for _, data := range v.Pods {
+			p, err := unmarshalPod(data)
+			if err != nil {
+				return nil, err
+			}
+			victims.Pods = append(victims.Pods, p)
+		}
+		if len(v.Pods) == 0 {
+			nodeInfo, err := nodeInfos.Get(nodeName)
+			if err != nil {
+				return nil, err
+			}
+			for _, uid := range v.PodUids {
+				for _, p := range nodeInfo.Pods {
+					if string(p.Pod.UID) == uid {
+						victims.Pods = append(victims.Pods, p.Pod)
+					}
+				}
+			}
+		}
+		nodeNameToVictims[nodeName] = victims
+	}
+	nodeNameToVictims, err = f.Extender.ProcessPreemption(pod, nodeNameToVictims, nodeInfos)
+	if err != nil {
+		return nil, err
+	}
+	result := &grpcextenderv1.ExtenderPreemptionResult{
+		NodeNameToVictims: make(map[string]*grpcextenderv1.Victims, len(nodeNameToVictims)),
+	}
+	for nodeName, victims := range nodeNameToVictims {
+		v := &grpcextenderv1.Victims{NumPdbViolations: victims.NumPDBViolations}
+		for _, p := range victims.Pods {
+			v.PodUids = append(v.PodUids, string(p.UID))
+		}
+		result.NodeNameToVictims[nodeName] = v
+	}
+	return result, nil
+}
+
+// unmarshalPod decodes a pod of the arguments of the Extender service.
+func unmarshalPod(data []byte) (*v1.Pod, error) {
+	pod := &v1.Pod{}
+	if err := pod.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+var _ grpcextenderv1.ExtenderServer = &FakeGRPCExtender{}
@@ -254,6 +254,12 @@ func (p *PodWrapper) NodeAffinityNotIn(key string, vals []string) *PodWrapper {
 	return p
 }
 
+// CreationTimestamp sets `t` as .metadata.creationTimestamp for the inner pod.
+func (p *PodWrapper) CreationTimestamp(t metav1.Time) *PodWrapper {
+	p.ObjectMeta.CreationTimestamp = t
+	return p
+}
+
 // StartTime sets `t` as .status.startTime for the inner pod.
 func (p *PodWrapper) StartTime(t metav1.Time) *PodWrapper {
 	p.Status.StartTime = &t
@@ -416,6 +422,15 @@ func (p *PodWrapper) Label(k, v string) *PodWrapper {
 	return p
 }
 
+// Annotation sets a {k,v} pair to the annotations of the inner pod.
+func (p *PodWrapper) Annotation(k, v string) *PodWrapper {
+	if p.Annotations == nil {
+		p.Annotations = make(map[string]string)
+	}
+	p.Annotations[k] = v
+	return p
+}
+
 // Req adds a new container to the inner pod with given resource map.
 func (p *PodWrapper) Req(resMap map[v1.ResourceName]string) *PodWrapper {
 	if len(resMap) == 0 {
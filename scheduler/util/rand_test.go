@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewRand(t *testing.T) {
+	draw := func(seed int64) []int {
+		r := NewRand(seed)
+		var got []int
+		for i := 0; i < 20; i++ {
+			got = append(got, r.Intn(1000))
+		}
+		return got
+	}
+
+	if diff := cmp.Diff(draw(42), draw(42)); diff != "" {
+		t.Errorf("Same seed produced different sequences (-first, +second):\n%s", diff)
+	}
+	if diff := cmp.Diff(draw(42), draw(43)); diff == "" {
+		t.Errorf("Different seeds produced the same sequence")
+	}
+}
+
+func TestNewRandConcurrentUse(t *testing.T) {
+	r := NewRand(1)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				r.Int63()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/internal/weighttuner"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
+	"k8s.io/kubernetes/pkg/scheduler/util"
+)
+
+// scoreWeightTuner tunes the weights of the Score plugins of a profile.
+type scoreWeightTuner struct {
+	profileName string
+	// fwk is the framework whose weights are tuned. The tuning starts over
+	// when the profile is reloaded.
+	fwk    framework.Framework
+	bandit *weighttuner.Bandit
+	// rand is the random source of the bandit, derived from the seed of the
+	// scheduler and the profile name.
+	rand *rand.Rand
+}
+
+// newScoreWeightTuner returns a tuner for the profile.
+func (sched *Scheduler) newScoreWeightTuner(profileName string) *scoreWeightTuner {
+	h := fnv.New64a()
+	h.Write([]byte(profileName))
+	return &scoreWeightTuner{
+		profileName: profileName,
+		rand:        util.NewRand(sched.randomSeed ^ int64(h.Sum64())),
+	}
+}
+
+// startScoreWeightTuning starts tuning the weights of the Score plugins of the
+// profiles configured with WithScoreWeightTuning, until the context is done.
+func (sched *Scheduler) startScoreWeightTuning(ctx context.Context) {
+	for name, cfg := range sched.scoreWeightTunings {
+		if cfg == nil {
+			continue
+		}
+		t := sched.newScoreWeightTuner(name)
+		go wait.UntilWithContext(ctx, func(context.Context) {
+			sched.tuneScoreWeights(t)
+		}, cfg.Interval.Duration)
+	}
+}
+
+// tuneScoreWeights rewards the weights tried by the tuner since its last call
+// with the balance of the utilization of the nodes, and applies the weights to
+// try next.
+func (sched *Scheduler) tuneScoreWeights(t *scoreWeightTuner) {
+	fwk := sched.profileMap()[t.profileName]
+	if fwk == nil {
+		return
+	}
+	if fwk != t.fwk {
+		t.fwk, t.bandit = fwk, nil
+		if cfg := sched.scoreWeightTunings[t.profileName]; cfg != nil {
+			t.bandit = weighttuner.New(fwk.ScorePluginWeights(), cfg, t.rand)
+			klog.V(2).InfoS("Started tuning the weights of Score plugins", "profile", t.profileName, "weights", t.bandit.Label())
+			recordScorePluginWeights(t.profileName, t.bandit.Weights())
+		}
+		return
+	}
+	if t.bandit == nil || sched.nodeUtilization == nil {
+		return
+	}
+	objective, ok := sched.nodeUtilization.balance()
+	if !ok {
+		klog.V(4).InfoS("No node utilization reported, keeping the weights of Score plugins", "profile", t.profileName)
+		return
+	}
+	tried := t.bandit.Label()
+	t.bandit.Record(objective)
+	metrics.ScoreWeightTuningObjective.WithLabelValues(t.profileName).Set(objective)
+	metrics.ScoreWeightTuningArmEvaluations.WithLabelValues(t.profileName, tried).Inc()
+	for _, s := range t.bandit.Stats() {
+		if s.Pulls > 0 {
+			metrics.ScoreWeightTuningArmObjective.WithLabelValues(t.profileName, s.Label).Set(s.Mean)
+		}
+	}
+
+	weights := t.bandit.Weights()
+	if err := fwk.SetScorePluginWeights(weights); err != nil {
+		klog.ErrorS(err, "Failed to set the weights of Score plugins", "profile", t.profileName)
+		return
+	}
+	klog.V(4).InfoS("Evaluated the weights of Score plugins", "profile", t.profileName, "weights", tried, "objective", objective, "next", t.bandit.Label())
+	recordScorePluginWeights(t.profileName, weights)
+}
+
+// profileConfig returns the configuration the profile was built from.
+func (sched *Scheduler) profileConfig(name string) schedulerapi.KubeSchedulerProfile {
+	sched.profilesLock.RLock()
+	defer sched.profilesLock.RUnlock()
+	return sched.profileConfigs[name]
+}
+
+func recordScorePluginWeights(profileName string, weights map[string]int) {
+	for plugin, w := range weights {
+		metrics.ScorePluginWeight.WithLabelValues(profileName, plugin).Set(float64(w))
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/imagelocality"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/tainttoleration"
+	"k8s.io/kubernetes/pkg/scheduler/profile"
+)
+
+func newTuningTestScheduler(t *testing.T, stopCh <-chan struct{}, epsilon float64, opts ...Option) *Scheduler {
+	client := clientsetfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	eventBroadcaster := events.NewBroadcaster(&events.EventSinkImpl{Interface: client.EventsV1()})
+	sched, err := New(client, informerFactory, nil, profile.NewRecorderFactory(eventBroadcaster), stopCh,
+		append([]Option{WithProfiles(schedulerapi.KubeSchedulerProfile{
+			SchedulerName: "tuned",
+			Plugins: &schedulerapi.Plugins{
+				QueueSort: schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{{Name: queuesort.Name}}},
+				Score: schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{
+					{Name: imagelocality.Name, Weight: 2},
+					{Name: tainttoleration.Name, Weight: 1},
+				}},
+				Bind: schedulerapi.PluginSet{Enabled: []schedulerapi.Plugin{{Name: defaultbinder.Name}}},
+			},
+		}), WithScoreWeightTuning("tuned", &schedulerapi.ScoreWeightTuning{
+			Plugins:   []string{imagelocality.Name},
+			Interval:  metav1.Duration{Duration: time.Minute},
+			Epsilon:   epsilon,
+			MaxWeight: 4,
+		})}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sched
+}
+
+func TestTuneScoreWeights(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	sched := newTuningTestScheduler(t, stopCh, 0)
+	fwk := sched.Profiles["tuned"]
+	tuner := sched.newScoreWeightTuner("tuned")
+	wantWeights := func(want map[string]int) {
+		t.Helper()
+		if diff := cmp.Diff(want, fwk.ScorePluginWeights()); diff != "" {
+			t.Errorf("Unexpected weights (-want,+got):\n%s", diff)
+		}
+	}
+
+	// The tuning starts with the configured weights.
+	sched.tuneScoreWeights(tuner)
+	wantWeights(map[string]int{imagelocality.Name: 2, tainttoleration.Name: 1})
+
+	// The weights are kept until the node telemetry reports utilization.
+	sched.tuneScoreWeights(tuner)
+	wantWeights(map[string]int{imagelocality.Name: 2, tainttoleration.Name: 1})
+
+	sched.ReportNodeUtilization("node1", 0.6)
+	sched.ReportNodeUtilization("node2", 0.2)
+	sched.tuneScoreWeights(tuner)
+	wantWeights(map[string]int{imagelocality.Name: 4, tainttoleration.Name: 1})
+	sched.tuneScoreWeights(tuner)
+	wantWeights(map[string]int{imagelocality.Name: 1, tainttoleration.Name: 1})
+
+	// Once every candidate was tried, the most balanced one is kept.
+	sched.ReportNodeUtilization("node2", 0.6)
+	sched.tuneScoreWeights(tuner)
+	wantWeights(map[string]int{imagelocality.Name: 1, tainttoleration.Name: 1})
+	sched.tuneScoreWeights(tuner)
+	wantWeights(map[string]int{imagelocality.Name: 1, tainttoleration.Name: 1})
+}
+
+func TestTuneScoreWeightsKeepsSchedulingReproducible(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	tuned := newTuningTestScheduler(t, stopCh, 1, WithRandomSeed(7))
+	untuned := newTuningTestScheduler(t, stopCh, 1, WithRandomSeed(7))
+
+	tuner := tuned.newScoreWeightTuner("tuned")
+	tuned.ReportNodeUtilization("node1", 0.6)
+	for i := 0; i < 10; i++ {
+		tuned.tuneScoreWeights(tuner)
+	}
+	for i := 0; i < 10; i++ {
+		if got, want := tuned.Profiles["tuned"].Rand().Int63(), untuned.Profiles["tuned"].Rand().Int63(); got != want {
+			t.Fatalf("Draw %d: got %d from the scheduling random source, want %d as without tuning", i, got, want)
+		}
+	}
+}